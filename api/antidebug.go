@@ -0,0 +1,169 @@
+package api
+
+import (
+	"regexp"
+	"strings"
+)
+
+var timerRegex = regexp.MustCompile(`\bset(Interval|Timeout)\s*\(`)
+
+// AntiDebugOptions selects the anti-debugging constructs removed by NeutralizeAntiDebugging
+type AntiDebugOptions struct {
+	Statements bool             // Neutralize standalone debugger statements
+	Timers     bool             // Replace timer callbacks that only run debugger statements with an empty function
+	Patterns   []*regexp.Regexp // Additional patterns, matches are removed from the body
+}
+
+// NeutralizeAntiDebugging removes anti-debugging constructs from a script. Only debugger keywords in statement
+// position are replaced, by `void 0` so that the surrounding code remains valid; strings, comments and anything
+// that could be part of an expression are left alone.
+// It returns the new body and the number of constructs neutralized
+func NeutralizeAntiDebugging(body string, opts AntiDebugOptions) (string, int) {
+	count := 0
+	if opts.Timers {
+		var n int
+		body, n = neutralizeTimers(body)
+		count += n
+	}
+	if opts.Statements {
+		var n int
+		body, n = neutralizeDebuggerStatements(body)
+		count += n
+	}
+	for _, p := range opts.Patterns {
+		count += len(p.FindAllStringIndex(body, -1))
+		body = p.ReplaceAllString(body, "")
+	}
+	return body, count
+}
+
+// neutralizeTimers replaces callbacks of setInterval and setTimeout calls whose body is nothing but
+// debugger statements
+func neutralizeTimers(body string) (string, int) {
+	var b strings.Builder
+	count := 0
+	last := 0
+	for _, loc := range timerRegex.FindAllStringIndex(body, -1) {
+		open := loc[1] - 1
+		if open < last {
+			continue
+		}
+		end := matchClosing(body, open)
+		if end == -1 {
+			continue
+		}
+		callback := splitArgs(body[open+1 : end])[0]
+		handler, _ := inlineHandlerBody(callback, 0)
+		if !onlyDebugger(handler) {
+			continue
+		}
+		start := open + 1 + strings.Index(body[open+1:end], callback)
+		b.WriteString(body[last:start])
+		b.WriteString("function(){}")
+		last = start + len(callback)
+		count++
+	}
+	b.WriteString(body[last:])
+	return b.String(), count
+}
+
+// onlyDebugger reports whether a function body contains debugger statements and nothing else
+func onlyDebugger(handler string) bool {
+	handler = strings.TrimSpace(handler)
+	if strings.HasPrefix(handler, "{") && strings.HasSuffix(handler, "}") {
+		handler = handler[1 : len(handler)-1]
+	}
+	found := false
+	for _, s := range strings.Split(handler, ";") {
+		switch strings.TrimSpace(s) {
+		case "":
+		case "debugger":
+			found = true
+		default:
+			return false
+		}
+	}
+	return found
+}
+
+// neutralizeDebuggerStatements replaces debugger keywords found in statement position with `void 0`
+func neutralizeDebuggerStatements(body string) (string, int) {
+	const keyword = "debugger"
+	var b strings.Builder
+	count := 0
+	last := 0
+	var quote byte
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case strings.HasPrefix(body[i:], "//"):
+			end := strings.IndexByte(body[i:], '\n')
+			if end == -1 {
+				i = len(body)
+			} else {
+				i += end
+			}
+		case strings.HasPrefix(body[i:], "/*"):
+			end := strings.Index(body[i+2:], "*/")
+			if end == -1 {
+				i = len(body)
+			} else {
+				i += end + 3
+			}
+		case strings.HasPrefix(body[i:], keyword) && isStatementStart(body, i) && isStatementEnd(body, i+len(keyword)):
+			b.WriteString(body[last:i])
+			b.WriteString("void 0")
+			last = i + len(keyword)
+			i = last - 1
+			count++
+		}
+	}
+	b.WriteString(body[last:])
+	return b.String(), count
+}
+
+// isStatementStart reports whether a statement can begin at index i of body
+func isStatementStart(body string, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		switch body[j] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case ';', '{', '}', ')', ':':
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// isStatementEnd reports whether a statement can end at index i of body, without the next line
+// continuing it
+func isStatementEnd(body string, i int) bool {
+	newline := false
+	for j := i; j < len(body); j++ {
+		switch body[j] {
+		case ' ', '\t', '\r':
+			continue
+		case '\n':
+			newline = true
+			continue
+		case ';', '}':
+			return true
+		case '(', '[', '`', '+', '-', '/', '.', ',':
+			return false
+		default:
+			return newline
+		}
+	}
+	return true
+}
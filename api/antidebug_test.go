@@ -0,0 +1,64 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"regexp"
+	"testing"
+)
+
+var allAntiDebug = AntiDebugOptions{Statements: true, Timers: true}
+
+func TestNeutralizeDebuggerStatements(t *testing.T) {
+	body, n := NeutralizeAntiDebugging("function check() {\n\tdebugger;\n\treturn 1;\n}", allAntiDebug)
+	assert.Equal(t, n, 1)
+	assert.Equal(t, body, "function check() {\n\tvoid 0;\n\treturn 1;\n}")
+
+	body, n = NeutralizeAntiDebugging("if (open) debugger\nrender()", allAntiDebug)
+	assert.Equal(t, n, 1)
+	assert.Equal(t, body, "if (open) void 0\nrender()")
+
+	body, n = NeutralizeAntiDebugging("while(true){debugger}", allAntiDebug)
+	assert.Equal(t, n, 1)
+	assert.Equal(t, body, "while(true){void 0}")
+}
+
+func TestNeutralizeAntiDebuggingTimers(t *testing.T) {
+	body, n := NeutralizeAntiDebugging("var t = setInterval(() => { debugger; }, 100);", allAntiDebug)
+	assert.Equal(t, n, 1)
+	assert.Equal(t, body, "var t = setInterval(function(){}, 100);")
+
+	body, n = NeutralizeAntiDebugging("window.setTimeout(function () {debugger}, 50)", allAntiDebug)
+	assert.Equal(t, n, 1)
+	assert.Equal(t, body, "window.setTimeout(function(){}, 50)")
+
+	// callbacks doing anything else only lose their debugger statement
+	body, n = NeutralizeAntiDebugging("setInterval(function(){ debugger; poll(); }, 100)", allAntiDebug)
+	assert.Equal(t, n, 1)
+	assert.Equal(t, body, "setInterval(function(){ void 0; poll(); }, 100)")
+}
+
+func TestNeutralizeAntiDebuggingLeavesCodeAlone(t *testing.T) {
+	clean := []string{
+		`var debuggerEnabled = false; log("debugger;");`,
+		"// debugger;\n/* debugger; */ run();",
+		`options.debugger = true;`,
+		`setInterval(tick, 1000);`,
+		"var x = a\ndebugger\n(b)",
+	}
+	for _, c := range clean {
+		body, n := NeutralizeAntiDebugging(c, allAntiDebug)
+		assert.Equal(t, n, 0)
+		assert.Equal(t, body, c)
+	}
+
+	body, n := NeutralizeAntiDebugging("a();debugger;", AntiDebugOptions{})
+	assert.Equal(t, n, 0)
+	assert.Equal(t, body, "a();debugger;")
+}
+
+func TestNeutralizeAntiDebuggingPatterns(t *testing.T) {
+	opts := AntiDebugOptions{Patterns: []*regexp.Regexp{regexp.MustCompile(`devtoolsDetector\.launch\(\);?`)}}
+	body, n := NeutralizeAntiDebugging("init();devtoolsDetector.launch();start();", opts)
+	assert.Equal(t, n, 1)
+	assert.Equal(t, body, "init();start();")
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CORSIssue is a risky Cross-Origin Resource Sharing configuration found in a response
+type CORSIssue struct {
+	Kind          string // "reflected-origin", "wildcard-credentials", "null-origin" or "insecure-origin"
+	Detail        string
+	Informational bool // Whether the issue needs confirming before it can be called a misconfiguration
+}
+
+// CheckCORS compares the CORS headers of the response to a request for rawUrl with the Origin the request was
+// sent with. Access-Control-Allow-Origin echoing the request Origin is reported as a reflected origin, as it is
+// what servers trusting any origin do. The Origin of a request made by the browser is the page's own though, one
+// the server may well trust, so reflected origins are informational until sending the request again with an
+// arbitrary Origin confirms them. A wildcard allowed along with credentials, the null origin, and plain http
+// origins trusted by an https page are reported as well. Reflected and insecure origins are only reported when
+// credentials are allowed, which is what lets another site read authenticated responses.
+// It returns the issues found, nil when the response has no CORS headers or they look safe
+func CheckCORS(rawUrl string, origin string, allowOrigin string, allowCredentials string) []CORSIssue {
+	allowOrigin = strings.TrimSpace(allowOrigin)
+	if allowOrigin == "" {
+		return nil
+	}
+	credentials := strings.EqualFold(strings.TrimSpace(allowCredentials), "true")
+	origin = strings.TrimSpace(origin)
+	detail := "Origin: " + origin + ", Access-Control-Allow-Origin: " + allowOrigin
+	if credentials {
+		detail += ", Access-Control-Allow-Credentials: true"
+	}
+
+	var issues []CORSIssue
+	switch {
+	case allowOrigin == "*":
+		if credentials {
+			issues = append(issues, CORSIssue{Kind: "wildcard-credentials", Detail: detail})
+		}
+	case strings.EqualFold(allowOrigin, "null"):
+		issues = append(issues, CORSIssue{Kind: "null-origin", Detail: detail})
+	case credentials && origin != "" && strings.EqualFold(allowOrigin, origin) && !sameOrigin(rawUrl, origin):
+		issues = append(issues, CORSIssue{Kind: "reflected-origin", Detail: detail, Informational: true})
+	}
+	if credentials && strings.HasPrefix(strings.ToLower(allowOrigin), "http://") &&
+		strings.HasPrefix(strings.ToLower(rawUrl), "https://") {
+		issues = append(issues, CORSIssue{Kind: "insecure-origin", Detail: detail})
+	}
+	return issues
+}
+
+// sameOrigin reports whether origin is the origin of rawUrl, in which case the request was not cross origin
+func sameOrigin(rawUrl string, origin string) bool {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme+"://"+u.Host, origin)
+}
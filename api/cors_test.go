@@ -0,0 +1,36 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestCheckCORSReflectedOriginWithCredentials(t *testing.T) {
+	issues := CheckCORS("https://api.example.com/me", "https://evil.example", "https://evil.example", "true")
+	assert.Equal(t, issues, []CORSIssue{{
+		Kind:          "reflected-origin",
+		Detail:        "Origin: https://evil.example, Access-Control-Allow-Origin: https://evil.example, Access-Control-Allow-Credentials: true",
+		Informational: true,
+	}})
+
+	// an http origin trusted with the credentials of an https page is reported as well
+	issues = CheckCORS("https://api.example.com/me", "http://evil.example", "http://evil.example", "true")
+	assert.Equal(t, len(issues), 2)
+	assert.Equal(t, issues[1].Kind, "insecure-origin")
+	assert.Equal(t, issues[1].Informational, false)
+}
+
+func TestCheckCORSSafe(t *testing.T) {
+	// a public resource readable by anyone, without credentials
+	assert.Equal(t, len(CheckCORS("https://cdn.example.com/a.json", "https://www.example.com", "*", "")), 0)
+	// a reflected origin is harmless as long as credentials are not allowed
+	assert.Equal(t, len(CheckCORS("https://api.example.com/me", "https://evil.example", "https://evil.example", "")), 0)
+	// same origin requests are not cross origin at all
+	assert.Equal(t, len(CheckCORS("https://example.com/me", "https://example.com", "https://example.com", "true")), 0)
+	assert.Equal(t, len(CheckCORS("https://example.com/me", "https://example.com", "", "")), 0)
+}
+
+func TestCheckCORSWildcardAndNull(t *testing.T) {
+	assert.Equal(t, CheckCORS("https://api.example.com/me", "https://evil.example", "*", "true")[0].Kind, "wildcard-credentials")
+	assert.Equal(t, CheckCORS("https://api.example.com/me", "null", "null", "")[0].Kind, "null-origin")
+}
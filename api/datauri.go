@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DataURI holds a resource embedded in a data: URI
+type DataURI struct {
+	MediaType string // Media type and parameters as written in the URI, such as "image/svg+xml;charset=utf-8". May be empty
+	Base64    bool   // Whether the data is base64 encoded in the URI
+	Data      []byte // Decoded data
+}
+
+// ParseDataURI decodes a data: URI.
+// It returns a pointer to a DataURI object and an error
+func ParseDataURI(uri string) (*DataURI, error) {
+	if len(uri) < 5 || !strings.EqualFold(uri[:5], "data:") {
+		return nil, fmt.Errorf("not a data uri")
+	}
+	comma := strings.IndexByte(uri, ',')
+	if comma == -1 {
+		return nil, fmt.Errorf("data uri without data")
+	}
+	d := &DataURI{MediaType: uri[5:comma]}
+	if strings.HasSuffix(strings.ToLower(d.MediaType), ";base64") {
+		d.Base64 = true
+		d.MediaType = d.MediaType[:len(d.MediaType)-len(";base64")]
+	}
+	payload := uri[comma+1:]
+	if d.Base64 {
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			// padding is often left out
+			data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "="))
+			if err != nil {
+				return nil, err
+			}
+		}
+		d.Data = data
+		return d, nil
+	}
+	data, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, err
+	}
+	d.Data = []byte(data)
+	return d, nil
+}
+
+// String encodes the data URI, base64 encoded or percent encoded like the original
+func (d *DataURI) String() string {
+	if d.Base64 {
+		return "data:" + d.MediaType + ";base64," + base64.StdEncoding.EncodeToString(d.Data)
+	}
+	var b strings.Builder
+	b.WriteString("data:" + d.MediaType + ",")
+	for _, c := range d.Data {
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`"'%#<>()\`+"`", c) != -1 {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// ReplaceDataURIs calls fn with every data: URI found in an HTML, CSS or JS body, and re-encodes the URIs fn
+// reports as changed. A URI ends at the quote or parenthesis it was opened with, or at the first whitespace,
+// quote, parenthesis or angle bracket when it is not enclosed. URIs that cannot be decoded are left alone.
+// It returns the new body and the number of URIs replaced
+func ReplaceDataURIs(body string, fn func(uri *DataURI) bool) (string, int) {
+	var b strings.Builder
+	count := 0
+	last := 0
+	lower := strings.ToLower(body)
+	for i := 0; ; {
+		j := strings.Index(lower[i:], "data:")
+		if j == -1 {
+			break
+		}
+		start := i + j
+		end := start + dataURIEnd(body[start:], body[:start])
+		i = end
+		if start > 0 && isIdentChar(body[start-1]) {
+			// part of a longer word, such as metadata:
+			continue
+		}
+		uri, err := ParseDataURI(body[start:end])
+		if err != nil || !fn(uri) {
+			continue
+		}
+		b.WriteString(body[last:start])
+		b.WriteString(uri.String())
+		last = end
+		count++
+	}
+	b.WriteString(body[last:])
+	return b.String(), count
+}
+
+// dataURIEnd returns the length of the data URI at the start of s, given what precedes it
+func dataURIEnd(s string, before string) int {
+	terminators := " \t\r\n\"'`()<>"
+	if before != "" {
+		switch open := before[len(before)-1]; open {
+		case '"', '\'', '`':
+			terminators = string(open)
+		case '(':
+			terminators = ")\"'"
+		}
+	}
+	if k := strings.IndexAny(s, terminators); k != -1 {
+		return k
+	}
+	return len(s)
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+func TestParseDataURI(t *testing.T) {
+	d, err := ParseDataURI("data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, d.MediaType, "image/svg+xml")
+	assert.Equal(t, d.Base64, true)
+	assert.Equal(t, string(d.Data), "<svg></svg>")
+	assert.Equal(t, d.String(), "data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=")
+
+	d, err = ParseDataURI("data:text/plain;charset=utf-8,hello%20gorp%21")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, d.MediaType, "text/plain;charset=utf-8")
+	assert.Equal(t, string(d.Data), "hello gorp!")
+	assert.Equal(t, d.String(), "data:text/plain;charset=utf-8,hello%20gorp!")
+
+	_, err = ParseDataURI("data:image/png;base64")
+	assert.Equal(t, err != nil, true)
+}
+
+func TestReplaceDataURIs(t *testing.T) {
+	page := `<img src="data:image/svg+xml;base64,PHN2ZyBvbmxvYWQ9InRyYWNrKCkiPjwvc3ZnPg==">
+<p>metadata:none</p>
+<div style="background: url(data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=)"></div>
+<script>var icon = 'data:text/plain,not%20svg';</script>`
+
+	var seen []string
+	result, n := ReplaceDataURIs(page, func(uri *DataURI) bool {
+		seen = append(seen, uri.MediaType)
+		if uri.MediaType != "image/svg+xml" || !strings.Contains(string(uri.Data), "onload") {
+			return false
+		}
+		uri.Data = []byte("<svg></svg>")
+		return true
+	})
+
+	assert.Equal(t, seen, []string{"image/svg+xml", "image/svg+xml", "text/plain"})
+	assert.Equal(t, n, 1)
+	assert.Equal(t, result, `<img src="data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=">
+<p>metadata:none</p>
+<div style="background: url(data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=)"></div>
+<script>var icon = 'data:text/plain,not%20svg';</script>`)
+}
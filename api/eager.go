@@ -0,0 +1,132 @@
+package api
+
+import (
+	"strings"
+)
+
+// EagerLoadOptions selects the attributes removed by ForceEagerLoading
+type EagerLoadOptions struct {
+	Scripts bool // Remove defer and async from script tags
+	Lazy    bool // Remove loading="lazy" from img and iframe tags
+}
+
+// attribute is the location of an attribute within a tag. Start includes the whitespace preceding the name
+type attribute struct {
+	Name  string
+	Value string
+	Start int
+	End   int
+}
+
+// ForceEagerLoading removes the attributes that let browsers delay loading resources, so that scripts run in
+// document order and images and frames load right away. Only attributes of start tags are touched, the rest
+// of the document is left exactly as it was; comments and the contents of elements such as script and style
+// are skipped, so markup in strings or comments is never rewritten.
+// It returns the new document and the number of attributes removed
+func ForceEagerLoading(body string, opts EagerLoadOptions) (string, int) {
+	var b strings.Builder
+	count := 0
+	last := 0
+	for i := 0; i < len(body); i++ {
+		if body[i] != '<' {
+			continue
+		}
+		if strings.HasPrefix(body[i:], "<!--") {
+			end := strings.Index(body[i+4:], "-->")
+			if end == -1 {
+				break
+			}
+			i += end + 6
+			continue
+		}
+		tag := tagName(body[i+1:])
+		if tag == "" || strings.HasPrefix(tag, "/") {
+			continue
+		}
+		attrs, end := parseAttributes(body, i+1+len(tag))
+		for _, a := range attrs {
+			if !removeAttribute(tag, a, opts) {
+				continue
+			}
+			b.WriteString(body[last:a.Start])
+			last = a.End
+			count++
+		}
+		i = end - 1
+		if rawTextElements[tag] {
+			closing := indexFold(body[end:], "</"+tag)
+			if closing == -1 {
+				break
+			}
+			i = end + closing
+		}
+	}
+	b.WriteString(body[last:])
+	return b.String(), count
+}
+
+// removeAttribute reports whether a of tag delays loading and is selected by opts
+func removeAttribute(tag string, a attribute, opts EagerLoadOptions) bool {
+	switch tag {
+	case "script":
+		return opts.Scripts && (a.Name == "defer" || a.Name == "async")
+	case "img", "iframe":
+		return opts.Lazy && a.Name == "loading" && strings.EqualFold(strings.TrimSpace(a.Value), "lazy")
+	}
+	return false
+}
+
+// parseAttributes reads the attributes of the tag whose name ends at index i of body.
+// It returns the attributes with lower cased names and the index right after the end of the tag
+func parseAttributes(body string, i int) ([]attribute, int) {
+	var attrs []attribute
+	for i < len(body) {
+		start := i
+		for i < len(body) && strings.IndexByte(" \t\r\n\f/", body[i]) != -1 {
+			i++
+		}
+		if i == len(body) {
+			break
+		}
+		if body[i] == '>' {
+			return attrs, i + 1
+		}
+		n := i
+		for i < len(body) && strings.IndexByte(" \t\r\n\f/>=", body[i]) == -1 {
+			i++
+		}
+		if i == n {
+			// stray equals sign
+			i++
+		}
+		a := attribute{Name: strings.ToLower(body[n:i]), Start: start}
+		j := i
+		for j < len(body) && strings.IndexByte(" \t\r\n\f", body[j]) != -1 {
+			j++
+		}
+		if j < len(body) && body[j] == '=' {
+			j++
+			for j < len(body) && strings.IndexByte(" \t\r\n\f", body[j]) != -1 {
+				j++
+			}
+			if j < len(body) && (body[j] == '"' || body[j] == '\'') {
+				end := strings.IndexByte(body[j+1:], body[j])
+				if end == -1 {
+					return attrs, len(body)
+				}
+				a.Value = body[j+1 : j+1+end]
+				j += end + 2
+			} else {
+				v := j
+				for j < len(body) && strings.IndexByte(" \t\r\n\f>", body[j]) == -1 {
+					j++
+				}
+				a.Value = body[v:j]
+			}
+			i = j
+		}
+		a.End = i
+		attrs = append(attrs, a)
+	}
+	return attrs, len(body)
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+const lazyPage = `<html>
+<head>
+<script defer src="/app.js"></script>
+<SCRIPT src='/vendor.js' ASYNC type="text/javascript"></SCRIPT>
+<script>var s = '<img src="a.png" loading="lazy">';</script>
+<!-- <script async src="/old.js"></script> -->
+</head>
+<body>
+<img src="hero.png" loading="lazy" decoding="async" alt="defer">
+<img src=thumb.png loading=LAZY />
+<img src="logo.png" loading="eager">
+<iframe src="/widget" loading = "lazy"></iframe>
+<div async defer loading="lazy"></div>
+</body>
+</html>`
+
+func TestForceEagerLoading(t *testing.T) {
+	result, n := ForceEagerLoading(lazyPage, EagerLoadOptions{Scripts: true, Lazy: true})
+	assert.Equal(t, n, 5)
+	assert.Equal(t, result, `<html>
+<head>
+<script src="/app.js"></script>
+<SCRIPT src='/vendor.js' type="text/javascript"></SCRIPT>
+<script>var s = '<img src="a.png" loading="lazy">';</script>
+<!-- <script async src="/old.js"></script> -->
+</head>
+<body>
+<img src="hero.png" decoding="async" alt="defer">
+<img src=thumb.png />
+<img src="logo.png" loading="eager">
+<iframe src="/widget"></iframe>
+<div async defer loading="lazy"></div>
+</body>
+</html>`)
+}
+
+func TestForceEagerLoadingScriptsOnly(t *testing.T) {
+	body := `<script async src="/a.js"></script><img src="b.png" loading="lazy">`
+	result, n := ForceEagerLoading(body, EagerLoadOptions{Scripts: true})
+	assert.Equal(t, n, 1)
+	assert.Equal(t, result, `<script src="/a.js"></script><img src="b.png" loading="lazy">`)
+
+	result, n = ForceEagerLoading(body, EagerLoadOptions{Lazy: true})
+	assert.Equal(t, n, 1)
+	assert.Equal(t, result, `<script async src="/a.js"></script><img src="b.png">`)
+}
+
+func TestForceEagerLoadingQuotedValues(t *testing.T) {
+	body := `<script data-x="a > b defer" async src="/a.js"></script>`
+	result, n := ForceEagerLoading(body, EagerLoadOptions{Scripts: true})
+	assert.Equal(t, n, 1)
+	assert.Equal(t, result, `<script data-x="a > b defer" src="/a.js"></script>`)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxExfilSamples is the number of matched values included in the detail of a PII signal
+const maxExfilSamples = 5
+
+var (
+	// EmailPattern matches email addresses
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// PhonePattern matches phone numbers of at least 7 digits, optionally prefixed with a country code and
+	// separated by spaces, dots or dashes
+	PhonePattern = regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?\(?\d{3}\)?[ .-]?\d{3}[ .-]?\d{4}\b`)
+)
+
+// ExfilRules sets what makes an outgoing request body suspicious
+type ExfilRules struct {
+	MaxBodySize int                       // Bodies larger than this many bytes are flagged, 0 for no limit
+	MinMatches  int                       // Number of distinct values a PII pattern must match for the body to be flagged
+	Patterns    map[string]*regexp.Regexp // PII patterns by name, such as "email"
+}
+
+// ExfilSignal is a reason an outgoing request body looks like it is leaking data
+type ExfilSignal struct {
+	Kind   string // "size", or the name of the PII pattern that matched
+	Detail string
+}
+
+// FindExfiltration checks an outgoing request body against rules. Url encoded bodies are decoded first so
+// that encoded values are matched as well.
+// It returns the signals found, sorted by kind
+func FindExfiltration(body string, rules ExfilRules) []ExfilSignal {
+	var signals []ExfilSignal
+	if rules.MaxBodySize > 0 && len(body) > rules.MaxBodySize {
+		signals = append(signals, ExfilSignal{
+			Kind:   "size",
+			Detail: fmt.Sprintf("%d bytes sent, more than %d", len(body), rules.MaxBodySize),
+		})
+	}
+
+	if decoded, err := url.QueryUnescape(body); err == nil {
+		body = decoded
+	}
+	minMatches := rules.MinMatches
+	if minMatches < 1 {
+		minMatches = 1
+	}
+	names := make([]string, 0, len(rules.Patterns))
+	for name := range rules.Patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := distinctMatches(rules.Patterns[name], body)
+		if len(values) < minMatches {
+			continue
+		}
+		samples := values
+		if len(samples) > maxExfilSamples {
+			samples = samples[:maxExfilSamples]
+		}
+		signals = append(signals, ExfilSignal{
+			Kind:   name,
+			Detail: fmt.Sprintf("%d distinct values sent: %s", len(values), strings.Join(samples, ", ")),
+		})
+	}
+	sort.SliceStable(signals, func(i, j int) bool {
+		return signals[i].Kind < signals[j].Kind
+	})
+	return signals
+}
+
+// distinctMatches returns the distinct matches of p in s, in the order they were found
+func distinctMatches(p *regexp.Regexp, s string) []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, m := range p.FindAllString(s, -1) {
+		if !seen[m] {
+			seen[m] = true
+			values = append(values, m)
+		}
+	}
+	return values
+}
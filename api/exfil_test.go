@@ -0,0 +1,37 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var piiRules = ExfilRules{
+	MaxBodySize: 1024,
+	MinMatches:  3,
+	Patterns:    map[string]*regexp.Regexp{"email": EmailPattern, "phone": PhonePattern},
+}
+
+func TestFindExfiltrationLargeBody(t *testing.T) {
+	body := `{"events":"` + strings.Repeat("A", 2000) + `"}`
+	assert.Equal(t, FindExfiltration(body, piiRules), []ExfilSignal{{Kind: "size", Detail: "2013 bytes sent, more than 1024"}})
+	assert.Equal(t, len(FindExfiltration(body[:100], piiRules)), 0)
+}
+
+func TestFindExfiltrationEmails(t *testing.T) {
+	body := "contacts=alice%40example.com,bob@example.org,carol@example.net,alice@example.com&phone=555-123-4567"
+	assert.Equal(t, FindExfiltration(body, piiRules), []ExfilSignal{
+		{Kind: "email", Detail: "3 distinct values sent: alice@example.com, bob@example.org, carol@example.net"},
+	})
+
+	// a single address, such as a login form, is expected
+	assert.Equal(t, len(FindExfiltration("user=alice@example.com&password=hunter2", piiRules)), 0)
+}
+
+func TestFindExfiltrationPhones(t *testing.T) {
+	body := `{"numbers":["+1 555-123-4567","(555) 987-6543","555.111.2222"]}`
+	signals := FindExfiltration(body, piiRules)
+	assert.Equal(t, len(signals), 1)
+	assert.Equal(t, signals[0].Kind, "phone")
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultFilterTimeout is how long a filter command may run on a single body
+const DefaultFilterTimeout = 10 * time.Second
+
+// RunFilter runs a program with args, writing body to its standard input, and returns what the program wrote to
+// its standard output. The program is run directly rather than through a shell, so nothing in args is expanded.
+// It is killed once timeout has elapsed, 0 for no limit. Anything written to standard error is included in the
+// error when the program fails.
+// It returns the transformed body and an error
+func RunFilter(program string, args []string, body string, timeout time.Duration) (string, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, program, args...)
+	cmd.Stdin = strings.NewReader(body)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("%s timed out after %s", program, timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s failed: %s: %s", program, err, msg)
+		}
+		return "", fmt.Errorf("%s failed: %s", program, err)
+	}
+	return stdout.String(), nil
+}
+
+// SplitCommand splits a command line into a program and its arguments on whitespace. Single and double quotes
+// group words, and a backslash escapes the next character outside of single quotes. No other shell syntax is
+// interpreted.
+// It returns the words of the command and an error if a quote is left open
+func SplitCommand(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, c := range command {
+		switch {
+		case escaped:
+			word.WriteRune(c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote, inWord = c, true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape in command")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunFilter(t *testing.T) {
+	body, err := RunFilter("tr", []string{"a-z", "A-Z"}, "var answer = 42;", time.Second)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, "VAR ANSWER = 42;")
+
+	body, err = RunFilter("cat", nil, "", time.Second)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, "")
+}
+
+func TestRunFilterWithoutShell(t *testing.T) {
+	body, err := RunFilter("echo", []string{"$(id);", "`id`", "|", "cat"}, "", time.Second)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, "$(id); `id` | cat\n")
+}
+
+func TestRunFilterErrors(t *testing.T) {
+	_, err := RunFilter("sleep", []string{"5"}, "", 50*time.Millisecond)
+	assert.Equal(t, err.Error(), "sleep timed out after 50ms")
+
+	_, err = RunFilter("cat", []string{"/does/not/exist"}, "", time.Second)
+	assert.Equal(t, strings.HasPrefix(err.Error(), "cat failed: exit status 1: "), true)
+
+	_, err = RunFilter("/does/not/exist", nil, "", time.Second)
+	assert.Equal(t, err != nil, true)
+}
+
+func TestSplitCommand(t *testing.T) {
+	words, err := SplitCommand(`js-beautify --indent-size 2 "--type=js" 'it'\''s' a\ b -`)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, words, []string{"js-beautify", "--indent-size", "2", "--type=js", "it's", "a b", "-"})
+
+	words, err = SplitCommand(`deob "" x`)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, words, []string{"deob", "", "x"})
+
+	_, err = SplitCommand(`deob "unterminated`)
+	assert.Equal(t, err.Error(), "unterminated quote or escape in command")
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// HashStore keeps the SHA-256 hashes of response bodies by url, so that assets changing between or during
+// sessions can be detected. It is safe for concurrent use.
+type HashStore struct {
+	mu       sync.Mutex
+	saveMu   sync.Mutex          // Keeps concurrent saves from overwriting a newer snapshot with an older one
+	baseline map[string]string   // Hashes loaded from a previous session
+	history  map[string][]string // Hashes seen during this session, oldest first
+}
+
+// AssetChange describes an asset whose content differs from the last time it was seen
+type AssetChange struct {
+	Url      string
+	Previous string
+	Current  string
+}
+
+// NewHashStore returns an empty HashStore
+func NewHashStore() *HashStore {
+	return &HashStore{baseline: make(map[string]string), history: make(map[string][]string)}
+}
+
+// LoadHashStore reads a baseline saved by HashStore.Save. A missing file results in an empty baseline.
+// It returns a pointer to a HashStore object and an error
+func LoadHashStore(path string) (*HashStore, error) {
+	h := NewHashStore()
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &h.baseline); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// HashContent returns the hex encoded SHA-256 hash of body
+func HashContent(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// Record adds the hash of body to the history of url. Assets seen for the first time are compared against
+// the baseline.
+// It returns a pointer to an AssetChange if the content is different from the last time the url was seen, nil otherwise
+func (h *HashStore) Record(url string, body string) *AssetChange {
+	hash := HashContent(body)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	previous := h.baseline[url]
+	if seen := h.history[url]; len(seen) > 0 {
+		previous = seen[len(seen)-1]
+		if previous == hash {
+			return nil
+		}
+	}
+	h.history[url] = append(h.history[url], hash)
+	if previous == "" || previous == hash {
+		return nil
+	}
+	return &AssetChange{Url: url, Previous: previous, Current: hash}
+}
+
+// History returns the distinct hashes seen for url during this session, oldest first
+func (h *HashStore) History(url string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.history[url]...)
+}
+
+// Latest returns the latest hash of every known url, from this session or else from the baseline
+func (h *HashStore) Latest() map[string]string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	latest := make(map[string]string, len(h.baseline))
+	for url, hash := range h.baseline {
+		latest[url] = hash
+	}
+	for url, seen := range h.history {
+		latest[url] = seen[len(seen)-1]
+	}
+	return latest
+}
+
+// AddBaseline adds hashes returned by Latest to the baseline, replacing those already known for the same urls
+func (h *HashStore) AddBaseline(hashes map[string]string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for url, hash := range hashes {
+		h.baseline[url] = hash
+	}
+}
+
+// Save writes the latest hash of every known url to path, to be used as the baseline of a later session
+func (h *HashStore) Save(path string) error {
+	h.saveMu.Lock()
+	defer h.saveMu.Unlock()
+
+	data, err := json.MarshalIndent(h.Latest(), "", "  ")
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first so that a crash never leaves a truncated baseline behind
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashStoreRecord(t *testing.T) {
+	h := NewHashStore()
+	url := "https://example.com/static/app.js"
+
+	assert.Equal(t, h.Record(url, "var a = 1;") == nil, true)
+	assert.Equal(t, h.Record(url, "var a = 1;") == nil, true)
+
+	change := h.Record(url, "var a = 2;")
+	assert.Equal(t, change == nil, false)
+	assert.Equal(t, change.Url, url)
+	assert.Equal(t, change.Previous, HashContent("var a = 1;"))
+	assert.Equal(t, change.Current, HashContent("var a = 2;"))
+	assert.Equal(t, h.History(url), []string{HashContent("var a = 1;"), HashContent("var a = 2;")})
+}
+
+func TestHashStoreBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-hashes")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "hashes.json")
+
+	// a missing baseline is not an error
+	h, err := LoadHashStore(path)
+	assert.Equal(t, err, nil)
+	h.Record("https://example.com/a.js", "a")
+	h.Record("https://example.com/b.js", "b")
+	assert.Equal(t, h.Save(path), nil)
+
+	next, err := LoadHashStore(path)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, next.Record("https://example.com/a.js", "a") == nil, true)
+	assert.Equal(t, next.History("https://example.com/a.js"), []string{HashContent("a")})
+	change := next.Record("https://example.com/b.js", "b2")
+	assert.Equal(t, change == nil, false)
+	assert.Equal(t, change.Previous, HashContent("b"))
+}
+
+func TestHashStoreAddBaseline(t *testing.T) {
+	h := NewHashStore()
+	h.Record("https://example.com/a.js", "a")
+
+	next := NewHashStore()
+	next.AddBaseline(h.Latest())
+	assert.Equal(t, next.Latest(), map[string]string{"https://example.com/a.js": HashContent("a")})
+	change := next.Record("https://example.com/a.js", "a2")
+	assert.Equal(t, change == nil, false)
+	assert.Equal(t, change.Previous, HashContent("a"))
+}
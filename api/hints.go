@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/url"
+	"strings"
+)
+
+// hintRels are the values of the rel attribute of link elements that hint at resources to resolve, connect
+// to or fetch ahead of time
+var hintRels = map[string]bool{
+	"dns-prefetch":  true,
+	"preconnect":    true,
+	"preload":       true,
+	"modulepreload": true,
+	"prefetch":      true,
+	"prerender":     true,
+}
+
+// ResourceHint is a link element of an HTML document asking the browser to prepare for a resource
+type ResourceHint struct {
+	Rel    string // "dns-prefetch", "preconnect", "preload", "modulepreload", "prefetch" or "prerender"
+	Url    string // Url of the hint, resolved against the url of the document
+	Origin string // Scheme and host of Url, which is all dns-prefetch and preconnect hints are about
+	As     string // Type of the resource to preload, from the as attribute
+}
+
+// FindResourceHints returns the resource hints of an HTML document served from docUrl, in document order.
+// A link with several hint relations, such as rel="preconnect dns-prefetch", yields a hint for each of them,
+// and hints repeated with the same relation and url are returned once. Comments and the contents of elements
+// such as script and textarea are skipped, so markup in strings is never mistaken for a hint
+func FindResourceHints(body string, docUrl string) []ResourceHint {
+	base, _ := url.Parse(docUrl)
+	var hints []ResourceHint
+	seen := make(map[ResourceHint]bool)
+	eachStartTag(body, func(tag string, attrs []attribute) {
+		if tag != "link" {
+			return
+		}
+		var rel, href, as string
+		for _, a := range attrs {
+			switch a.Name {
+			case "rel":
+				rel = a.Value
+			case "href":
+				href = strings.TrimSpace(a.Value)
+			case "as":
+				as = strings.ToLower(strings.TrimSpace(a.Value))
+			}
+		}
+		if href == "" {
+			return
+		}
+		u, err := url.Parse(href)
+		if err != nil {
+			return
+		}
+		if base != nil {
+			u = base.ResolveReference(u)
+		}
+		origin := ""
+		if u.Host != "" {
+			origin = u.Scheme + "://" + u.Host
+		}
+		for _, r := range strings.Fields(strings.ToLower(rel)) {
+			if !hintRels[r] {
+				continue
+			}
+			h := ResourceHint{Rel: r, Url: u.String(), Origin: origin, As: as}
+			if !seen[h] {
+				seen[h] = true
+				hints = append(hints, h)
+			}
+		}
+	})
+	return hints
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestFindResourceHints(t *testing.T) {
+	doc := `<html><head>
+<link rel="preconnect dns-prefetch" href="https://api.example.net">
+<link rel=preload href="/static/app.js" as="script">
+<link rel="stylesheet" href="/static/app.css">
+<link rel="preconnect" href="https://api.example.net">
+<!-- <link rel="preconnect" href="https://old.example.org"> -->
+<script>var s = '<link rel="preload" href="/fake.js">';</script>
+<link rel="dns-prefetch" href="//cdn.example.com">
+</head><body></body></html>`
+
+	hints := FindResourceHints(doc, "https://example.com/shop/")
+	assert.Equal(t, hints, []ResourceHint{
+		{Rel: "preconnect", Url: "https://api.example.net", Origin: "https://api.example.net"},
+		{Rel: "dns-prefetch", Url: "https://api.example.net", Origin: "https://api.example.net"},
+		{Rel: "preload", Url: "https://example.com/static/app.js", Origin: "https://example.com", As: "script"},
+		{Rel: "dns-prefetch", Url: "https://cdn.example.com", Origin: "https://cdn.example.com"},
+	})
+}
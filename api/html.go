@@ -0,0 +1,128 @@
+package api
+
+import (
+	"strings"
+)
+
+// rawTextElements hold text that is not parsed as markup, so tags found inside them are not real tags
+var rawTextElements = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"textarea": true,
+	"title":    true,
+	"xmp":      true,
+	"iframe":   true,
+	"noembed":  true,
+	"noframes": true,
+	"noscript": true,
+}
+
+// InjectHTML inserts snippet right before the end tag of the head or body element (position "head" or "body")
+// of an HTML document. Comments and the contents of elements such as script and style are skipped while
+// looking for the tag, so markup in strings or comments is never mistaken for it. When the document has no
+// closing head tag the snippet goes before the body, and when it has no closing body tag it goes at the end.
+// It returns the new document and whether the snippet was injected
+func InjectHTML(body string, snippet string, position string) (string, bool) {
+	if position != "head" && position != "body" {
+		return body, false
+	}
+	i := findTag(body, "/"+position)
+	if i == -1 && position == "head" {
+		i = findTag(body, "body")
+	}
+	if i == -1 {
+		if !strings.Contains(strings.ToLower(body), "<html") && findTag(body, "head") == -1 {
+			// not a document
+			return body, false
+		}
+		i = len(body)
+	}
+	return body[:i] + snippet + body[i:], true
+}
+
+// findTag returns the index of the first tag with the given name, "/name" for end tags, or -1 if there is none
+func findTag(body string, name string) int {
+	for i := 0; i < len(body); i++ {
+		if body[i] != '<' {
+			continue
+		}
+		if strings.HasPrefix(body[i:], "<!--") {
+			end := strings.Index(body[i+4:], "-->")
+			if end == -1 {
+				return -1
+			}
+			i += end + 6
+			continue
+		}
+		tag := tagName(body[i+1:])
+		if tag == "" {
+			continue
+		}
+		if tag == name {
+			return i
+		}
+		if rawTextElements[tag] {
+			end := indexFold(body[i+1:], "</"+tag)
+			if end == -1 {
+				return -1
+			}
+			i += end
+		}
+	}
+	return -1
+}
+
+// tagName returns the lower cased name of the tag s starts with, prefixed with "/" for end tags
+func tagName(s string) string {
+	prefix := ""
+	if strings.HasPrefix(s, "/") {
+		prefix = "/"
+		s = s[1:]
+	}
+	n := 0
+	for n < len(s) && (s[n] >= 'a' && s[n] <= 'z' || s[n] >= 'A' && s[n] <= 'Z' || n > 0 && s[n] >= '0' && s[n] <= '9') {
+		n++
+	}
+	if n == 0 || n < len(s) && !strings.ContainsRune(" \t\r\n/>", rune(s[n])) {
+		return ""
+	}
+	return prefix + strings.ToLower(s[:n])
+}
+
+// eachStartTag calls fn with the lower cased name and the attributes of every start tag of an HTML document,
+// in document order. Comments and the contents of elements such as script and textarea are skipped, so markup
+// in strings or comments is never mistaken for a tag
+func eachStartTag(body string, fn func(tag string, attrs []attribute)) {
+	for i := 0; i < len(body); i++ {
+		if body[i] != '<' {
+			continue
+		}
+		if strings.HasPrefix(body[i:], "<!--") {
+			end := strings.Index(body[i+4:], "-->")
+			if end == -1 {
+				return
+			}
+			i += end + 6
+			continue
+		}
+		tag := tagName(body[i+1:])
+		if tag == "" || strings.HasPrefix(tag, "/") {
+			continue
+		}
+		attrs, end := parseAttributes(body, i+1+len(tag))
+		fn(tag, attrs)
+		i = end - 1
+		if rawTextElements[tag] {
+			closing := indexFold(body[end:], "</"+tag)
+			if closing == -1 {
+				return
+			}
+			i = end + closing
+		}
+	}
+}
+
+// indexFold is strings.Index ignoring ASCII case
+func indexFold(s string, substr string) int {
+	return strings.Index(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+const injectionPage = `<!DOCTYPE html>
+<html>
+<HEAD>
+<title>not </head> yet</title>
+<!-- </head> -->
+<script>var s = "</head></body>";</script>
+</HEAD>
+<body>
+<p>hello</p>
+</body>
+</html>`
+
+func TestInjectHTMLHead(t *testing.T) {
+	css := "<style>.hidden{display:block !important}</style>"
+	result, ok := InjectHTML(injectionPage, css, "head")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, result, `<!DOCTYPE html>
+<html>
+<HEAD>
+<title>not </head> yet</title>
+<!-- </head> -->
+<script>var s = "</head></body>";</script>
+`+css+`</HEAD>
+<body>
+<p>hello</p>
+</body>
+</html>`)
+}
+
+func TestInjectHTMLBody(t *testing.T) {
+	js := "<script>console.log('gorp')</script>"
+	result, ok := InjectHTML(injectionPage, js, "body")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, result, `<!DOCTYPE html>
+<html>
+<HEAD>
+<title>not </head> yet</title>
+<!-- </head> -->
+<script>var s = "</head></body>";</script>
+</HEAD>
+<body>
+<p>hello</p>
+`+js+`</body>
+</html>`)
+}
+
+func TestInjectHTMLFallbacks(t *testing.T) {
+	result, ok := InjectHTML("<html><body><p>hi</p></body></html>", "<style></style>", "head")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, result, "<html><style></style><body><p>hi</p></body></html>")
+
+	result, ok = InjectHTML("<html><p>hi</p>", "<script></script>", "body")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, result, "<html><p>hi</p><script></script>")
+
+	_, ok = InjectHTML(`{"html": "<b>not a document</b>"}`, "<script></script>", "body")
+	assert.Equal(t, ok, false)
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"strings"
+)
+
+// scriptTypes are the values of the type attribute of script elements holding JavaScript, besides no type at all
+var scriptTypes = map[string]bool{
+	"module":                   true,
+	"text/javascript":          true,
+	"application/javascript":   true,
+	"application/x-javascript": true,
+	"text/ecmascript":          true,
+	"application/ecmascript":   true,
+	"text/jscript":             true,
+	"text/livescript":          true,
+}
+
+// InlineScript is a script element embedded in an HTML document
+type InlineScript struct {
+	Attributes map[string]string // Attributes of the start tag, by lower cased name
+	Code       string
+}
+
+// ReplaceInlineScripts calls fn with every script element of an HTML document holding JavaScript code rather
+// than loading it with a src attribute, and writes back the code of the scripts fn reports as changed.
+// Scripts with a type that is not JavaScript, such as JSON data or templates, are left alone, and so are
+// comments and the contents of other elements such as textarea.
+// It returns the new document and the number of scripts replaced
+func ReplaceInlineScripts(body string, fn func(script *InlineScript) bool) (string, int) {
+	var b strings.Builder
+	count := 0
+	last := 0
+	for i := 0; i < len(body); i++ {
+		if body[i] != '<' {
+			continue
+		}
+		if strings.HasPrefix(body[i:], "<!--") {
+			end := strings.Index(body[i+4:], "-->")
+			if end == -1 {
+				break
+			}
+			i += end + 6
+			continue
+		}
+		tag := tagName(body[i+1:])
+		if !rawTextElements[tag] {
+			continue
+		}
+		attrs, open := parseAttributes(body, i+1+len(tag))
+		closing := indexFold(body[open:], "</"+tag)
+		if closing == -1 {
+			break
+		}
+		end := open + closing
+		i = end
+		if tag != "script" {
+			continue
+		}
+		script := &InlineScript{Attributes: make(map[string]string), Code: body[open:end]}
+		for _, a := range attrs {
+			if _, ok := script.Attributes[a.Name]; !ok {
+				script.Attributes[a.Name] = a.Value
+			}
+		}
+		if !isInlineJavaScript(script.Attributes) || !fn(script) {
+			continue
+		}
+		b.WriteString(body[last:open])
+		b.WriteString(script.Code)
+		last = end
+		count++
+	}
+	b.WriteString(body[last:])
+	return b.String(), count
+}
+
+// isInlineJavaScript reports whether a script element with the given attributes runs the code it holds
+func isInlineJavaScript(attributes map[string]string) bool {
+	if _, ok := attributes["src"]; ok {
+		return false
+	}
+	t, ok := attributes["type"]
+	t = strings.ToLower(strings.TrimSpace(t))
+	return !ok || t == "" || scriptTypes[t]
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+const inlineScriptPage = `<html>
+<head>
+<!-- <script>commented()</script> -->
+<script src="/app.js"></script>
+<SCRIPT type="text/javascript" data-note="a > b">debugger; init();</SCRIPT>
+<script type="application/ld+json">{"debugger": true}</script>
+<textarea><script>typed()</script></textarea>
+</head>
+<body>
+<script type=module>import "./x.js"; debugger;</script>
+<script>var html = "<b>debugger</b>";</script>
+</body>
+</html>`
+
+func TestReplaceInlineScripts(t *testing.T) {
+	var seen []string
+	result, n := ReplaceInlineScripts(inlineScriptPage, func(script *InlineScript) bool {
+		seen = append(seen, script.Code)
+		if !strings.Contains(script.Code, "debugger;") {
+			return false
+		}
+		script.Code = strings.Replace(script.Code, "debugger;", "", -1)
+		return true
+	})
+	assert.Equal(t, seen, []string{
+		"debugger; init();",
+		`import "./x.js"; debugger;`,
+		`var html = "<b>debugger</b>";`,
+	})
+	assert.Equal(t, n, 2)
+	assert.Equal(t, result, strings.NewReplacer(
+		"debugger; init();", " init();",
+		`import "./x.js"; debugger;`, `import "./x.js"; `,
+	).Replace(inlineScriptPage))
+}
+
+func TestInlineScriptAttributes(t *testing.T) {
+	var attributes []map[string]string
+	ReplaceInlineScripts(`<script nonce='abc' async type=module>f()</script><script
+		defer>g()</script>`, func(script *InlineScript) bool {
+		attributes = append(attributes, script.Attributes)
+		return false
+	})
+	assert.Equal(t, attributes, []map[string]string{
+		{"nonce": "abc", "async": "", "type": "module"},
+		{"defer": ""},
+	})
+}
+
+func TestReplaceInlineScriptsUnterminated(t *testing.T) {
+	body := "<p>hi</p><script>var a = 1;"
+	result, n := ReplaceInlineScripts(body, func(script *InlineScript) bool {
+		t.Fatal("unterminated scripts are not passed on")
+		return true
+	})
+	assert.Equal(t, n, 0)
+	assert.Equal(t, result, body)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"html"
+	"strings"
+)
+
+// urlAttributes are the attributes holding urls that run code when they use the javascript: scheme
+var urlAttributes = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"action":     true,
+	"formaction": true,
+	"data":       true,
+	"xlink:href": true,
+}
+
+// InlineSink is an attribute of an HTML element holding code run by the browser, a place where injected
+// markup turns into script execution
+type InlineSink struct {
+	Kind      string // "event-handler" for on* attributes, "javascript-url" or "srcdoc"
+	Tag       string // Lower cased name of the element
+	Attribute string // Lower cased name of the attribute
+	Value     string // Value of the attribute, with character references decoded, shortened to a readable length
+}
+
+// FindInlineSinks returns the inline event handlers, javascript: urls and iframe srcdoc attributes of an HTML
+// document, in document order. Comments and the contents of elements such as script and textarea are skipped,
+// so markup in strings is never mistaken for an element
+func FindInlineSinks(body string) []InlineSink {
+	var sinks []InlineSink
+	eachStartTag(body, func(tag string, attrs []attribute) {
+		for _, a := range attrs {
+			kind := ""
+			value := html.UnescapeString(a.Value)
+			switch {
+			case isEventHandler(a.Name):
+				kind = "event-handler"
+			case urlAttributes[a.Name] && isJavaScriptUrl(value):
+				kind = "javascript-url"
+			case tag == "iframe" && a.Name == "srcdoc":
+				kind = "srcdoc"
+			default:
+				continue
+			}
+			sinks = append(sinks, InlineSink{
+				Kind:      kind,
+				Tag:       tag,
+				Attribute: a.Name,
+				Value:     snippet(value, 0, len(value)),
+			})
+		}
+	})
+	return sinks
+}
+
+// isEventHandler reports whether an attribute name is that of an inline event handler, such as onclick
+func isEventHandler(name string) bool {
+	if len(name) < 3 || !strings.HasPrefix(name, "on") {
+		return false
+	}
+	for _, c := range name[2:] {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// isJavaScriptUrl reports whether a url uses the javascript: scheme, the way browsers read it: ignoring case,
+// surrounding whitespace and the tabs and newlines within it
+func isJavaScriptUrl(u string) bool {
+	u = strings.NewReplacer("\t", "", "\n", "", "\r", "").Replace(u)
+	// leading C0 control characters and spaces are stripped as well
+	u = strings.TrimLeftFunc(u, func(r rune) bool { return r <= ' ' })
+	return len(u) >= len("javascript:") && strings.EqualFold(u[:len("javascript:")], "javascript:")
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestFindInlineSinks(t *testing.T) {
+	doc := `<html><body>
+<button id="buy" onclick="buy(location.hash.slice(1))">Buy</button>
+<a href="javascript:void(go())">Go</a>
+<a href=" JaVa&#x09;script:alert(1)">Encoded</a>
+<a href="/javascript:help">Help</a>
+<iframe srcdoc="<p>hi</p>"></iframe>
+<!-- <img src=x onerror=alert(1)> -->
+<textarea><div onmouseover="x()"></div></textarea>
+</body></html>`
+
+	assert.Equal(t, FindInlineSinks(doc), []InlineSink{
+		{Kind: "event-handler", Tag: "button", Attribute: "onclick", Value: "buy(location.hash.slice(1))"},
+		{Kind: "javascript-url", Tag: "a", Attribute: "href", Value: "javascript:void(go())"},
+		{Kind: "javascript-url", Tag: "a", Attribute: "href", Value: " JaVa\tscript:alert(1)"},
+		{Kind: "srcdoc", Tag: "iframe", Attribute: "srcdoc", Value: "<p>hi</p>"},
+	})
+}
+
+func TestIsEventHandler(t *testing.T) {
+	assert.Equal(t, isEventHandler("onerror"), true)
+	assert.Equal(t, isEventHandler("on"), false)
+	assert.Equal(t, isEventHandler("on-click"), false)
+	assert.Equal(t, isEventHandler("icon"), false)
+}
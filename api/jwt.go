@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// JSON objects always start with {" which is eyJ once base64 encoded, which keeps false positives low
+var jwtRegex = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*`)
+
+// Jwt holds the decoded header and claims of a JSON Web Token
+type Jwt struct {
+	Raw    string
+	Header map[string]interface{}
+	Claims map[string]interface{}
+}
+
+// FindJwts looks for JWT shaped strings in s and decodes them. Signatures are not verified
+// and tokens that cannot be decoded are skipped.
+// It returns a list of pointers to Jwt objects
+func FindJwts(s string) []*Jwt {
+	var result []*Jwt
+	for _, token := range jwtRegex.FindAllString(s, -1) {
+		jwt, err := DecodeJwt(token)
+		if err != nil {
+			continue
+		}
+		result = append(result, jwt)
+	}
+	return result
+}
+
+// DecodeJwt decodes the header and payload of a JWT without verifying its signature.
+// It returns a pointer to a Jwt object and an error if the token is malformed
+func DecodeJwt(token string) (*Jwt, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("a JWT must have 3 parts, found %d", len(parts))
+	}
+	result := Jwt{Raw: token}
+	if err := decodeJwtPart(parts[0], &result.Header); err != nil {
+		return nil, fmt.Errorf("invalid JWT header: %s", err)
+	}
+	if err := decodeJwtPart(parts[1], &result.Claims); err != nil {
+		return nil, fmt.Errorf("invalid JWT payload: %s", err)
+	}
+	return &result, nil
+}
+
+// PrettyClaims returns the header and claims of the token as indented JSON
+func (j *Jwt) PrettyClaims() string {
+	out, err := json.MarshalIndent(map[string]interface{}{
+		"header": j.Header,
+		"claims": j.Claims,
+	}, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+func decodeJwtPart(part string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+// {"alg":"HS256","typ":"JWT"}.{"sub":"1234567890","name":"John Doe","exp":1516239022}
+const jwtToken = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9." +
+	"eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRG9lIiwiZXhwIjoxNTE2MjM5MDIyfQ." +
+	"SflKxwRJSMeKKF2QT4fwpMeJf36POk6yJV_adQssw5c"
+
+func TestFindJwts(t *testing.T) {
+	body := `{"access_token":"` + jwtToken + `","token_type":"Bearer"}`
+	jwts := FindJwts(body)
+	assert.Equal(t, len(jwts), 1)
+	assert.Equal(t, jwts[0].Raw, jwtToken)
+	assert.Equal(t, jwts[0].Header["alg"], "HS256")
+	assert.Equal(t, jwts[0].Claims["sub"], "1234567890")
+	assert.Equal(t, jwts[0].Claims["exp"], float64(1516239022))
+
+	jwts = FindJwts("Bearer " + jwtToken)
+	assert.Equal(t, len(jwts), 1)
+}
+
+func TestFindJwtsMalformed(t *testing.T) {
+	assert.Equal(t, len(FindJwts("eyJhbGciOi.eyJzdWIiOi.abc")), 0)
+	assert.Equal(t, len(FindJwts("no tokens in here")), 0)
+
+	_, err := DecodeJwt("eyJhbGciOiJIUzI1NiJ9.bm90IGpzb24")
+	assert.Equal(t, err != nil, true)
+}
@@ -0,0 +1,244 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LibrarySignature tells how to recognize a JavaScript library and which of its versions are vulnerable.
+// Patterns are regular expressions whose first group captures the version
+type LibrarySignature struct {
+	Library    string               `json:"library"`
+	Content    []string             `json:"content"`    // Patterns matched against the script body
+	Filename   []string             `json:"filename"`   // Patterns matched against the last segment of the script url
+	Vulnerable []VulnerableVersions `json:"vulnerable"` // Version ranges affected by known vulnerabilities
+}
+
+// VulnerableVersions is a range of versions of a library affected by a vulnerability
+type VulnerableVersions struct {
+	AtOrAbove   string   `json:"atOrAbove"`   // First affected version, all versions below Below when empty
+	Below       string   `json:"below"`       // First version with the fix
+	Identifiers []string `json:"identifiers"` // CVE or advisory ids
+	Summary     string   `json:"summary"`
+}
+
+// LibraryMatch is a library found in a script
+type LibraryMatch struct {
+	Library         string
+	Version         string
+	Source          string               // "content" when found in the body of the script, "filename" when found in its url
+	Vulnerabilities []VulnerableVersions // Known vulnerabilities affecting the version
+}
+
+// Vulnerable reports whether the version found is affected by any known vulnerability
+func (m LibraryMatch) Vulnerable() bool {
+	return len(m.Vulnerabilities) > 0
+}
+
+// LibraryDatabase fingerprints libraries from a set of signatures
+type LibraryDatabase struct {
+	signatures []compiledSignature
+}
+
+type compiledSignature struct {
+	LibrarySignature
+	content  []*regexp.Regexp
+	filename []*regexp.Regexp
+}
+
+// DefaultLibrarySignatures covers widely used libraries with well known vulnerabilities. It is far from
+// exhaustive, use LoadLibraryDatabase to add signatures
+var DefaultLibrarySignatures = []LibrarySignature{
+	{
+		Library: "jquery",
+		Content: []string{
+			`/\*!? jQuery v(\d+\.\d+\.\d+[\w.-]*)`,
+			`jQuery JavaScript Library v(\d+\.\d+\.\d+[\w.-]*)`,
+		},
+		Filename: []string{`^jquery-(\d+\.\d+\.\d+[\w-]*?)(\.slim)?(\.min)?\.js$`},
+		Vulnerable: []VulnerableVersions{
+			{Below: "1.9.0", Identifiers: []string{"CVE-2012-6708"}, Summary: "XSS through selectors starting with HTML"},
+			{AtOrAbove: "1.4.0", Below: "3.0.0", Identifiers: []string{"CVE-2015-9251"}, Summary: "XSS through cross domain ajax responses executed as scripts"},
+			{Below: "3.4.0", Identifiers: []string{"CVE-2019-11358"}, Summary: "Prototype pollution in jQuery.extend"},
+			{AtOrAbove: "1.2.0", Below: "3.5.0", Identifiers: []string{"CVE-2020-11022", "CVE-2020-11023"}, Summary: "XSS when passing untrusted HTML to DOM manipulation methods"},
+		},
+	},
+	{
+		Library: "angularjs",
+		Content: []string{
+			`AngularJS v(\d+\.\d+\.\d+[\w.-]*)`,
+		},
+		Filename: []string{`^angular(?:js)?-(\d+\.\d+\.\d+[\w-]*?)(\.min)?\.js$`},
+		Vulnerable: []VulnerableVersions{
+			{Below: "1.7.9", Identifiers: []string{"CVE-2019-10768"}, Summary: "Prototype pollution in angular.merge"},
+			{Below: "1.8.0", Identifiers: []string{"CVE-2020-7676"}, Summary: "XSS through option elements nested in select elements"},
+		},
+	},
+	{
+		Library: "lodash",
+		Content: []string{
+			`@license\s+lodash (\d+\.\d+\.\d+)`,
+			`(?s)Lodash <https://lodash\.com/>.{0,1000}?VERSION\s*=\s*'(\d+\.\d+\.\d+)'`,
+		},
+		Filename: []string{`^lodash-(\d+\.\d+\.\d+)(\.min)?\.js$`},
+		Vulnerable: []VulnerableVersions{
+			{Below: "4.17.12", Identifiers: []string{"CVE-2019-10744"}, Summary: "Prototype pollution in defaultsDeep"},
+			{Below: "4.17.21", Identifiers: []string{"CVE-2021-23337"}, Summary: "Command injection through template"},
+		},
+	},
+	{
+		Library: "bootstrap",
+		Content: []string{
+			`\*\s*Bootstrap v(\d+\.\d+\.\d+[\w.-]*)`,
+		},
+		Filename: []string{`^bootstrap-(\d+\.\d+\.\d+[\w-]*?)(\.bundle)?(\.min)?\.js$`},
+		Vulnerable: []VulnerableVersions{
+			{Below: "3.4.0", Identifiers: []string{"CVE-2018-14040", "CVE-2018-14042"}, Summary: "XSS in the data-parent and data-container attributes"},
+			{AtOrAbove: "4.0.0", Below: "4.1.2", Identifiers: []string{"CVE-2018-14040", "CVE-2018-14042"}, Summary: "XSS in the data-parent and data-container attributes"},
+			{Below: "3.4.1", Identifiers: []string{"CVE-2019-8331"}, Summary: "XSS in the tooltip and popover data-template attribute"},
+			{AtOrAbove: "4.0.0", Below: "4.3.1", Identifiers: []string{"CVE-2019-8331"}, Summary: "XSS in the tooltip and popover data-template attribute"},
+		},
+	},
+}
+
+// NewLibraryDatabase compiles signatures into a database.
+// It returns a pointer to a LibraryDatabase object and an error if a pattern does not compile
+func NewLibraryDatabase(signatures []LibrarySignature) (*LibraryDatabase, error) {
+	db := &LibraryDatabase{}
+	for _, s := range signatures {
+		c := compiledSignature{LibrarySignature: s}
+		for _, p := range s.Content {
+			r, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid content pattern for %s: %s", s.Library, err)
+			}
+			c.content = append(c.content, r)
+		}
+		for _, p := range s.Filename {
+			r, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filename pattern for %s: %s", s.Library, err)
+			}
+			c.filename = append(c.filename, r)
+		}
+		db.signatures = append(db.signatures, c)
+	}
+	return db, nil
+}
+
+// LoadLibraryDatabase reads a JSON list of signatures and compiles them along with DefaultLibrarySignatures.
+// It returns a pointer to a LibraryDatabase object and an error
+func LoadLibraryDatabase(path string) (*LibraryDatabase, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var signatures []LibrarySignature
+	if err := json.Unmarshal(data, &signatures); err != nil {
+		return nil, fmt.Errorf("unable to parse signatures in %s: %s", path, err)
+	}
+	return NewLibraryDatabase(append(append([]LibrarySignature(nil), DefaultLibrarySignatures...), signatures...))
+}
+
+// Fingerprint finds the libraries included in a script, along with their versions. The body is looked at
+// first, the url of the script is only used for libraries whose version is not found in the body.
+// It returns one match per library found
+func (db *LibraryDatabase) Fingerprint(scriptUrl string, body string) []LibraryMatch {
+	filename := ""
+	if u, err := url.Parse(scriptUrl); err == nil {
+		filename = path.Base(u.Path)
+	}
+
+	var matches []LibraryMatch
+	for _, s := range db.signatures {
+		version, source := firstGroup(s.content, body), "content"
+		if version == "" && filename != "" {
+			version, source = firstGroup(s.filename, filename), "filename"
+		}
+		if version == "" {
+			continue
+		}
+		m := LibraryMatch{Library: s.Library, Version: version, Source: source}
+		for _, v := range s.Vulnerable {
+			if v.affects(version) {
+				m.Vulnerabilities = append(m.Vulnerabilities, v)
+			}
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+func firstGroup(patterns []*regexp.Regexp, s string) string {
+	for _, p := range patterns {
+		if m := p.FindStringSubmatch(s); len(m) > 1 && m[1] != "" {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// affects reports whether version falls in the range
+func (v VulnerableVersions) affects(version string) bool {
+	if v.AtOrAbove != "" && CompareVersions(version, v.AtOrAbove) < 0 {
+		return false
+	}
+	return v.Below == "" || CompareVersions(version, v.Below) < 0
+}
+
+// CompareVersions compares two dotted version numbers such as 1.12.4, where missing parts count as 0 and
+// pre-releases such as 3.0.0-beta1 come before the release.
+// It returns -1 if a is lower than b, 1 if a is higher and 0 if they are the same
+func CompareVersions(a string, b string) int {
+	a, preA := splitPrerelease(a)
+	b, preB := splitPrerelease(b)
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		if c := compareInts(versionPart(partsA, i), versionPart(partsB, i)); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	case preA < preB:
+		return -1
+	}
+	return 1
+}
+
+func splitPrerelease(version string) (string, string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+	return version, ""
+}
+
+func versionPart(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[i])
+	return n
+}
+
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
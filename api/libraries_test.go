@@ -0,0 +1,83 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func defaultLibraryDatabase(t *testing.T) *LibraryDatabase {
+	db, err := NewLibraryDatabase(DefaultLibrarySignatures)
+	assert.Equal(t, err, nil)
+	return db
+}
+
+func TestFingerprintJQuery(t *testing.T) {
+	db := defaultLibraryDatabase(t)
+
+	matches := db.Fingerprint("https://example.com/js/vendor.js",
+		`/*! jQuery v3.5.1 | (c) JS Foundation and other contributors | jquery.org/license */!function(e,t){}`)
+	assert.Equal(t, len(matches), 1)
+	assert.Equal(t, matches[0].Library, "jquery")
+	assert.Equal(t, matches[0].Version, "3.5.1")
+	assert.Equal(t, matches[0].Source, "content")
+	assert.Equal(t, matches[0].Vulnerable(), false)
+}
+
+func TestFingerprintVulnerableJQuery(t *testing.T) {
+	db := defaultLibraryDatabase(t)
+
+	matches := db.Fingerprint("https://code.jquery.com/jquery-1.8.3.min.js?v=2", "(function(e,t){var n,r})(window);")
+	assert.Equal(t, len(matches), 1)
+	assert.Equal(t, matches[0].Version, "1.8.3")
+	assert.Equal(t, matches[0].Source, "filename")
+	var ids []string
+	for _, v := range matches[0].Vulnerabilities {
+		ids = append(ids, v.Identifiers...)
+	}
+	assert.Equal(t, ids, []string{"CVE-2012-6708", "CVE-2015-9251", "CVE-2019-11358", "CVE-2020-11022", "CVE-2020-11023"})
+}
+
+func TestFingerprintBundle(t *testing.T) {
+	db := defaultLibraryDatabase(t)
+	body := "/**\n * @license AngularJS v1.8.2\n * (c) 2010-2020 Google LLC. http://angularjs.org\n */\n" +
+		"/*!\n  * Bootstrap v4.3.1 (https://getbootstrap.com/)\n  */"
+
+	matches := db.Fingerprint("https://example.com/bundle.js", body)
+	assert.Equal(t, len(matches), 2)
+	assert.Equal(t, matches[0].Library+" "+matches[0].Version, "angularjs 1.8.2")
+	assert.Equal(t, matches[0].Vulnerable(), false)
+	assert.Equal(t, matches[1].Library+" "+matches[1].Version, "bootstrap 4.3.1")
+	assert.Equal(t, matches[1].Vulnerable(), false)
+	assert.Equal(t, len(db.Fingerprint("https://example.com/app.js", "var jquery = 1;")), 0)
+}
+
+func TestLoadLibraryDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signatures.json")
+	err = ioutil.WriteFile(path, []byte(`[{"library":"widget","content":["Widget v(\\d+\\.\\d+)"],
+		"vulnerable":[{"below":"2.0","identifiers":["GHSA-1234"],"summary":"XSS"}]}]`), 0644)
+	assert.Equal(t, err, nil)
+
+	db, err := LoadLibraryDatabase(path)
+	assert.Equal(t, err, nil)
+	matches := db.Fingerprint("https://example.com/w.js", "/* Widget v1.9 */ /*! jQuery v3.6.0 */")
+	assert.Equal(t, len(matches), 2)
+	assert.Equal(t, matches[1].Library, "widget")
+	assert.Equal(t, matches[1].Vulnerabilities[0].Identifiers, []string{"GHSA-1234"})
+
+	_, err = NewLibraryDatabase([]LibrarySignature{{Library: "bad", Content: []string{"("}}})
+	assert.Equal(t, err != nil, true)
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, CompareVersions("1.12.4", "1.9.0"), 1)
+	assert.Equal(t, CompareVersions("1.9", "1.9.0"), 0)
+	assert.Equal(t, CompareVersions("3.0.0-beta1", "3.0.0"), -1)
+	assert.Equal(t, CompareVersions("3.0.0-rc1", "3.0.0-beta1"), 1)
+	assert.Equal(t, CompareVersions("v2.0.0", "10.0.0"), -1)
+}
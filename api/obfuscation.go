@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// DefaultObfuscationThreshold is the score above which a script is considered likely obfuscated
+const DefaultObfuscationThreshold = 0.5
+
+// minObfuscationSample is the size a script must have for line length and entropy to tell anything about it
+const minObfuscationSample = 512
+
+var (
+	packerRegex        = regexp.MustCompile(`eval\s*\(\s*function\s*\(\s*p\s*,\s*a\s*,\s*c\s*,\s*k\s*,\s*e\s*,\s*[rd]\s*\)`)
+	hexIdentifierRegex = regexp.MustCompile(`\b_0x[0-9a-fA-F]{4,}\b`)
+	escapeRegex        = regexp.MustCompile(`\\x[0-9a-fA-F]{2}|\\u[0-9a-fA-F]{4}`)
+)
+
+// Obfuscation holds the heuristics computed by ScoreObfuscation
+type Obfuscation struct {
+	Score          float64  // Between 0 and 1, how likely the script is obfuscated
+	Packed         bool     // Whether the script is wrapped in an eval(function(p,a,c,k,e,...)) packer
+	HexIdentifiers int      // Number of distinct identifiers such as _0x3f2a
+	Escapes        int      // Number of \x and \u escape sequences
+	AvgLineLength  float64  // Average number of bytes per line
+	Entropy        float64  // Shannon entropy of the script, in bits per byte
+	Reasons        []string // What contributed to the score
+}
+
+// Obfuscated reports whether the score is above threshold
+func (o Obfuscation) Obfuscated(threshold float64) bool {
+	return o.Score > threshold
+}
+
+// ScoreObfuscation computes heuristics telling obfuscated scripts apart from regular and minified ones. Long
+// lines and high entropy are common in minified code, so they only add a little to the score, whereas eval
+// packers, hex identifiers and heavy use of escape sequences are rarely seen outside of obfuscated code.
+// It returns the heuristics and the resulting score
+func ScoreObfuscation(body string) Obfuscation {
+	o := Obfuscation{}
+	if len(body) == 0 {
+		return o
+	}
+	score := 0.0
+
+	if packerRegex.MatchString(body) {
+		o.Packed = true
+		score += 0.6
+		o.Reasons = append(o.Reasons, "eval packer")
+	}
+
+	o.HexIdentifiers = len(distinctMatches(hexIdentifierRegex, body))
+	if o.HexIdentifiers >= 8 {
+		score += 0.6
+		o.Reasons = append(o.Reasons, fmt.Sprintf("%d hex identifiers", o.HexIdentifiers))
+	} else if o.HexIdentifiers >= 3 {
+		score += 0.3
+		o.Reasons = append(o.Reasons, fmt.Sprintf("%d hex identifiers", o.HexIdentifiers))
+	}
+
+	o.Escapes = len(escapeRegex.FindAllStringIndex(body, -1))
+	if o.Escapes >= 20 && float64(o.Escapes*4)/float64(len(body)) > 0.05 {
+		score += 0.3
+		o.Reasons = append(o.Reasons, fmt.Sprintf("%d escape sequences", o.Escapes))
+	}
+
+	lines := strings.Count(body, "\n") + 1
+	o.AvgLineLength = float64(len(body)) / float64(lines)
+	o.Entropy = entropy(body)
+	if len(body) >= minObfuscationSample {
+		if o.AvgLineLength > 1000 {
+			score += 0.15
+			o.Reasons = append(o.Reasons, fmt.Sprintf("average line length of %.0f", o.AvgLineLength))
+		}
+		if o.Entropy > 5.5 {
+			score += 0.15
+			o.Reasons = append(o.Reasons, fmt.Sprintf("entropy of %.2f bits per byte", o.Entropy))
+		}
+	}
+
+	o.Score = math.Min(score, 1)
+	return o
+}
+
+// entropy returns the Shannon entropy of s, in bits per byte
+func entropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	e := 0.0
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / float64(len(s))
+		e -= p * math.Log2(p)
+	}
+	return e
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+const packedScript = `eval(function(p,a,c,k,e,d){e=function(c){return c};if(!''.replace(/^/,String)){while(c--){d[c]=k[c]||c}` +
+	`k=[function(e){return d[e]}];e=function(){return'\\w+'};c=1};while(c--){if(k[c]){p=p.replace(new RegExp('\\b'+e(c)+'\\b','g'),k[c])}}` +
+	`return p}('3 0=\'1\';2(0);',4,4,'token|s3cr3t|alert|var'.split('|'),0,{}))`
+
+const hexScript = `var _0x4f2a=['\x6c\x6f\x67','\x68\x65\x6c\x6c\x6f'];(function(_0x1b2c3d,_0x4f2a1e){var _0x5a7b9c=function(_0x2e8f10)` +
+	`{while(--_0x2e8f10){_0x1b2c3d['push'](_0x1b2c3d['shift']());}};_0x5a7b9c(++_0x4f2a1e);}(_0x4f2a,0x1f4));var _0x3c1d=function(_0x6d2e4f,` +
+	`_0x7e3f50){_0x6d2e4f=_0x6d2e4f-0x0;var _0x8f4061=_0x4f2a[_0x6d2e4f];return _0x8f4061;};console[_0x3c1d('0x0')](_0x3c1d('0x1'));`
+
+const plainScript = `// Toggles the navigation menu on small screens
+function toggleMenu(event) {
+    event.preventDefault();
+    var menu = document.getElementById("menu");
+    if (menu.classList.contains("open")) {
+        menu.classList.remove("open");
+    } else {
+        menu.classList.add("open");
+    }
+}
+
+document.addEventListener("DOMContentLoaded", function () {
+    document.querySelector(".menu-toggle").addEventListener("click", toggleMenu);
+});
+`
+
+func TestScoreObfuscationPacked(t *testing.T) {
+	o := ScoreObfuscation(packedScript)
+	assert.Equal(t, o.Packed, true)
+	assert.Equal(t, o.Obfuscated(DefaultObfuscationThreshold), true)
+	assert.Equal(t, o.Reasons[0], "eval packer")
+}
+
+func TestScoreObfuscationHexIdentifiers(t *testing.T) {
+	o := ScoreObfuscation(hexScript)
+	assert.Equal(t, o.Packed, false)
+	assert.Equal(t, o.HexIdentifiers, 9)
+	assert.Equal(t, o.Obfuscated(DefaultObfuscationThreshold), true)
+}
+
+func TestScoreObfuscationPlain(t *testing.T) {
+	o := ScoreObfuscation(plainScript)
+	assert.Equal(t, o.Score, 0.0)
+	assert.Equal(t, o.Obfuscated(DefaultObfuscationThreshold), false)
+
+	// minified code has long lines, but is not obfuscated
+	minified := strings.Repeat(`function t(e){e.preventDefault();var n=document.getElementById("menu");n.classList.toggle("open")}`, 20)
+	o = ScoreObfuscation(minified)
+	assert.Equal(t, o.AvgLineLength > 1000, true)
+	assert.Equal(t, o.Obfuscated(DefaultObfuscationThreshold), false)
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	postMessageRegex     = regexp.MustCompile(`\.postMessage\s*\(`)
+	messageListenerRegex = regexp.MustCompile(`addEventListener\s*\(\s*["'\x60]message["'\x60]\s*,\s*`)
+	onMessageRegex       = regexp.MustCompile(`\.onmessage\s*=\s*`)
+	handlerStartRegex    = regexp.MustCompile(`^(async\s+)?(function\b[^(]*\([^)]*\)|\([^)]*\)\s*=>|[A-Za-z_$][\w$]*\s*=>)\s*`)
+	originCheckRegex     = regexp.MustCompile(`\.origin\b`)
+)
+
+// PostMessageIssue describes a likely insecure use of the postMessage API
+type PostMessageIssue struct {
+	Kind    string // "wildcard-target" or "unchecked-origin"
+	Snippet string
+}
+
+// FindInsecurePostMessage looks for postMessage calls sending data to any origin ('*') and for message
+// listeners that never look at the origin of the messages they receive. Listeners that are passed by
+// reference rather than defined inline are skipped, keeping false positives low.
+// It returns a list of issues found in body
+func FindInsecurePostMessage(body string) []PostMessageIssue {
+	var result []PostMessageIssue
+	for _, loc := range postMessageRegex.FindAllStringIndex(body, -1) {
+		end := matchClosing(body, loc[1]-1)
+		if end == -1 {
+			continue
+		}
+		args := splitArgs(body[loc[1]:end])
+		if len(args) >= 2 && isWildcardLiteral(args[1]) {
+			result = append(result, PostMessageIssue{Kind: "wildcard-target", Snippet: snippet(body, loc[0], end+1)})
+		}
+	}
+
+	var listeners [][]int
+	listeners = append(listeners, messageListenerRegex.FindAllStringIndex(body, -1)...)
+	listeners = append(listeners, onMessageRegex.FindAllStringIndex(body, -1)...)
+	for _, loc := range listeners {
+		handlerBody, end := inlineHandlerBody(body, loc[1])
+		if end == -1 {
+			continue
+		}
+		if !originCheckRegex.MatchString(handlerBody) {
+			result = append(result, PostMessageIssue{Kind: "unchecked-origin", Snippet: snippet(body, loc[0], end+1)})
+		}
+	}
+	return result
+}
+
+// inlineHandlerBody returns the body of a function defined at index start of body, and the index where it ends
+func inlineHandlerBody(body string, start int) (string, int) {
+	m := handlerStartRegex.FindStringIndex(body[start:])
+	if m == nil {
+		return "", -1
+	}
+	open := start + m[1]
+	if open >= len(body) || body[open] != '{' {
+		// expression bodied arrow function
+		end := strings.IndexAny(body[open:], ";\n")
+		if end == -1 {
+			end = len(body) - open
+		}
+		return body[open : open+end], open + end - 1
+	}
+	end := matchClosing(body, open)
+	if end == -1 {
+		return "", -1
+	}
+	return body[open : end+1], end
+}
+
+// matchClosing returns the index of the bracket closing the one found at index open,
+// ignoring brackets inside string literals. It returns -1 if there is none.
+func matchClosing(body string, open int) int {
+	pairs := map[byte]byte{'(': ')', '{': '}', '[': ']'}
+	var stack []byte
+	var quote byte
+	for i := open; i < len(body); i++ {
+		c := body[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'', '`':
+			quote = c
+		case '(', '{', '[':
+			stack = append(stack, pairs[c])
+		case ')', '}', ']':
+			if len(stack) == 0 || stack[len(stack)-1] != c {
+				return -1
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitArgs splits a list of call arguments on the commas that are not nested in brackets or strings
+func splitArgs(args string) []string {
+	var result []string
+	depth := 0
+	var quote byte
+	last := 0
+	for i := 0; i < len(args); i++ {
+		c := args[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'', '`':
+			quote = c
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, strings.TrimSpace(args[last:i]))
+				last = i + 1
+			}
+		}
+	}
+	return append(result, strings.TrimSpace(args[last:]))
+}
+
+func isWildcardLiteral(arg string) bool {
+	return arg == `"*"` || arg == `'*'` || arg == "`*`"
+}
+
+// snippet returns body[start:end], shortened to a readable length
+func snippet(body string, start, end int) string {
+	const maxLen = 200
+	if end-start > maxLen {
+		return body[start:start+maxLen] + "..."
+	}
+	return body[start:end]
+}
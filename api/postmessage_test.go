@@ -0,0 +1,35 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+const vulnerableListener = `window.addEventListener("message", function(e) { document.body.innerHTML = e.data; }, false);`
+
+const checkedListener = `window.addEventListener('message', (event) => {
+	if (event.origin !== "https://example.com") return;
+	render(event.data);
+});`
+
+func TestFindInsecurePostMessageListeners(t *testing.T) {
+	issues := FindInsecurePostMessage(vulnerableListener)
+	assert.Equal(t, len(issues), 1)
+	assert.Equal(t, issues[0].Kind, "unchecked-origin")
+	assert.Equal(t, issues[0].Snippet, `addEventListener("message", function(e) { document.body.innerHTML = e.data; }`)
+
+	assert.Equal(t, len(FindInsecurePostMessage(checkedListener)), 0)
+	assert.Equal(t, len(FindInsecurePostMessage(`ws.onmessage = e => update(JSON.parse(e.data))`)), 1)
+	// handlers passed by reference cannot be checked and are not reported
+	assert.Equal(t, len(FindInsecurePostMessage(`window.addEventListener("message", onMessage)`)), 0)
+}
+
+func TestFindInsecurePostMessageTargets(t *testing.T) {
+	issues := FindInsecurePostMessage(`parent.postMessage({token: getToken(a, b)}, "*");`)
+	assert.Equal(t, len(issues), 1)
+	assert.Equal(t, issues[0].Kind, "wildcard-target")
+	assert.Equal(t, issues[0].Snippet, `.postMessage({token: getToken(a, b)}, "*")`)
+
+	assert.Equal(t, len(FindInsecurePostMessage(`parent.postMessage(msg, "https://example.com");`)), 0)
+	assert.Equal(t, len(FindInsecurePostMessage(`worker.postMessage("*")`)), 0)
+}
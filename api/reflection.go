@@ -0,0 +1,113 @@
+package api
+
+import (
+	"mime"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxPendingRequests bounds the number of requests kept while waiting for their response, as some
+// responses are never intercepted
+const maxPendingRequests = 1000
+
+// Reflection is a request parameter whose value was found verbatim in the response body
+type Reflection struct {
+	Url   string
+	Param string
+	Value string
+}
+
+// ReflectionTracker correlates request parameters with response bodies using the request id shared by both.
+// It is safe for concurrent use.
+type ReflectionTracker struct {
+	MinLength int // Values shorter than this are ignored, as they show up everywhere
+
+	mu      sync.Mutex
+	pending map[string]url.Values
+	order   []string
+}
+
+// NewReflectionTracker returns a ReflectionTracker ignoring values shorter than minLength
+func NewReflectionTracker(minLength int) *ReflectionTracker {
+	return &ReflectionTracker{MinLength: minLength, pending: make(map[string]url.Values)}
+}
+
+// Request records the form parameters sent in the body of a request, to be checked against its response.
+// Query parameters do not need recording as they are part of the response url.
+func (r *ReflectionTracker) Request(requestId string, contentType string, body string) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if requestId == "" || body == "" || mediaType != "application/x-www-form-urlencoded" {
+		return
+	}
+	params, err := url.ParseQuery(body)
+	if err != nil || len(params) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.pending[requestId]; !ok {
+		r.order = append(r.order, requestId)
+	}
+	r.pending[requestId] = params
+	for len(r.order) > maxPendingRequests {
+		delete(r.pending, r.order[0])
+		r.order = r.order[1:]
+	}
+}
+
+// Response looks for the query parameters of rawUrl and the form parameters recorded for requestId in body.
+// It returns the list of reflected parameters, sorted by name
+func (r *ReflectionTracker) Response(requestId string, rawUrl string, body string) []Reflection {
+	params := url.Values{}
+	if u, err := url.Parse(rawUrl); err == nil {
+		params = u.Query()
+	}
+	r.mu.Lock()
+	if posted, ok := r.pending[requestId]; ok {
+		for k, v := range posted {
+			params[k] = append(params[k], v...)
+		}
+		delete(r.pending, requestId)
+		for i, id := range r.order {
+			if id == requestId {
+				r.order = append(r.order[:i], r.order[i+1:]...)
+				break
+			}
+		}
+	}
+	r.mu.Unlock()
+
+	var result []Reflection
+	for _, p := range FindReflections(params, body, r.MinLength) {
+		p.Url = rawUrl
+		result = append(result, p)
+	}
+	return result
+}
+
+// FindReflections returns the parameters whose value is found verbatim in body, sorted by name.
+// Values shorter than minLength are ignored
+func FindReflections(params url.Values, body string, minLength int) []Reflection {
+	names := make([]string, 0, len(params))
+	for k := range params {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var result []Reflection
+	for _, name := range names {
+		seen := make(map[string]bool)
+		for _, v := range params[name] {
+			if len(v) < minLength || seen[v] {
+				continue
+			}
+			seen[v] = true
+			if strings.Contains(body, v) {
+				result = append(result, Reflection{Param: name, Value: v})
+			}
+		}
+	}
+	return result
+}
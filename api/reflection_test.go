@@ -0,0 +1,29 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestReflectedQueryParam(t *testing.T) {
+	r := NewReflectionTracker(4)
+	found := r.Response("1", "https://example.com/search?q=gorp%3Cb%3E&page=1",
+		`<h1>Results for gorp<b></h1><a href="?page=2">next</a>`)
+	assert.Equal(t, found, []Reflection{{Url: "https://example.com/search?q=gorp%3Cb%3E&page=1", Param: "q", Value: "gorp<b>"}})
+
+	// values are not reflected, or too short to tell
+	assert.Equal(t, len(r.Response("2", "https://example.com/search?q=gorp&page=1", `<h1>No results</h1> 1`)), 0)
+}
+
+func TestReflectedFormParam(t *testing.T) {
+	r := NewReflectionTracker(4)
+	r.Request("1", "application/x-www-form-urlencoded; charset=UTF-8", "name=hex0punk&token=s3cr3t")
+	r.Request("2", "application/x-www-form-urlencoded", "comment=s3cr3t")
+
+	found := r.Response("1", "https://example.com/profile", `<p>Welcome back, hex0punk</p>`)
+	assert.Equal(t, found, []Reflection{{Url: "https://example.com/profile", Param: "name", Value: "hex0punk"}})
+
+	// parameters are only checked against the response of their own request
+	assert.Equal(t, len(r.Response("3", "https://example.com/other", `hex0punk s3cr3t`)), 0)
+	assert.Equal(t, len(r.Response("2", "https://example.com/comments", `<p>Thanks!</p>`)), 0)
+}
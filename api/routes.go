@@ -0,0 +1,299 @@
+package api
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	routeArrayRegex = regexp.MustCompile(`\[\s*\{`)
+	routeJSXRegex   = regexp.MustCompile(`\bRoute\s*,\s*\{`)
+
+	// routers maps framework names to the code that gives away their router
+	routers = []struct {
+		framework string
+		pattern   *regexp.Regexp
+	}{
+		{"vue", regexp.MustCompile(`\bVueRouter\b|vue-router|\bcreateWeb(?:Hash)?History\b`)},
+		{"react", regexp.MustCompile(`react-router|\bcreate(?:Browser|Hash|Memory)Router\b|\buseRoutes\b`)},
+		{"angular", regexp.MustCompile(`\bRouterModule\b`)},
+	}
+)
+
+// routeKeys are the keys, besides path, that tell a route definition apart from any object with a path
+var routeKeys = []string{"component", "components", "element", "redirect", "redirectTo", "children",
+	"loadChildren", "loadComponent", "lazy"}
+
+// Route is a client side route, either defined in a script or navigated to by the browser
+type Route struct {
+	Path      string
+	Framework string // "vue", "react" or "angular", empty for routes that were only navigated to
+	Source    string // Url of the script defining the route
+	Visited   bool   // Whether the browser navigated to the route
+}
+
+// FindRoutes looks for the routing table of single page applications in a script. Route definitions such as
+// {path: "/users/:id", component: User} are read from arrays, along with their children, and for React
+// Router from Route elements as well. Scripts that do not include a known router are left alone, so that
+// any array of objects with a path is not mistaken for routes.
+// It returns the routes in the order they are defined, with the paths of children joined to their parent's
+func FindRoutes(body string) []Route {
+	framework := ""
+	for _, r := range routers {
+		if r.pattern.MatchString(body) {
+			framework = r.framework
+			break
+		}
+	}
+	if framework == "" {
+		return nil
+	}
+
+	var routes []Route
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			routes = append(routes, Route{Path: path, Framework: framework})
+		}
+	}
+
+	for i := 0; i < len(body); {
+		loc := routeArrayRegex.FindStringIndex(body[i:])
+		if loc == nil {
+			break
+		}
+		open := i + loc[0]
+		end := matchClosing(body, open)
+		if end == -1 || !walkRoutes(body[open:end+1], "", add) {
+			i = open + 1
+			continue
+		}
+		i = end + 1
+	}
+
+	if framework == "react" {
+		for _, loc := range routeJSXRegex.FindAllStringIndex(body, -1) {
+			open := loc[1] - 1
+			end := matchClosing(body, open)
+			if end == -1 {
+				continue
+			}
+			if path, ok := stringLiteral(objectFields(body[open : end+1])["path"]); ok {
+				add(joinRoute("", path))
+			}
+		}
+	}
+	return routes
+}
+
+// walkRoutes passes the paths of the routes defined in array to add, joined to parent.
+// It returns whether the array holds route definitions
+func walkRoutes(array string, parent string, add func(string)) bool {
+	found := false
+	for _, e := range splitArgs(array[1 : len(array)-1]) {
+		if !strings.HasPrefix(e, "{") || !strings.HasSuffix(e, "}") {
+			continue
+		}
+		fields := objectFields(e)
+		path, ok := stringLiteral(fields["path"])
+		if !ok || !isRoute(fields) {
+			continue
+		}
+		found = true
+		path = joinRoute(parent, path)
+		add(path)
+		if children := fields["children"]; strings.HasPrefix(children, "[") && strings.HasSuffix(children, "]") {
+			walkRoutes(children, path, add)
+		}
+	}
+	return found
+}
+
+// isRoute reports whether the fields of an object with a path look like a route definition
+func isRoute(fields map[string]string) bool {
+	for _, k := range routeKeys {
+		if _, ok := fields[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// objectFields returns the values of the properties of an object literal by key. Shorthand properties and
+// methods are left out
+func objectFields(obj string) map[string]string {
+	fields := make(map[string]string)
+	for _, p := range splitArgs(obj[1 : len(obj)-1]) {
+		i := strings.IndexByte(p, ':')
+		if i == -1 {
+			continue
+		}
+		key := strings.TrimSpace(p[:i])
+		if unquoted, ok := stringLiteral(key); ok {
+			key = unquoted
+		} else if strings.ContainsAny(key, "([{") {
+			continue
+		}
+		fields[key] = strings.TrimSpace(p[i+1:])
+	}
+	return fields
+}
+
+// stringLiteral returns the value of a quoted string literal without interpolation
+func stringLiteral(s string) (string, bool) {
+	if len(s) < 2 || s[0] != s[len(s)-1] || !strings.ContainsRune(`"'`+"`", rune(s[0])) {
+		return "", false
+	}
+	v := s[1 : len(s)-1]
+	if s[0] == '`' && strings.Contains(v, "${") {
+		return "", false
+	}
+	return v, true
+}
+
+// joinRoute joins a route path to the path of its parent. Absolute paths are left as is
+func joinRoute(parent string, path string) string {
+	if strings.HasPrefix(path, "/") {
+		return path
+	}
+	if path == "" {
+		return parent
+	}
+	return strings.TrimSuffix(parent, "/") + "/" + path
+}
+
+// RouteMap collects the routes of an application as scripts defining them are loaded and pages are
+// navigated to. It is safe for concurrent use.
+type RouteMap struct {
+	mu     sync.Mutex
+	routes map[string]*Route
+}
+
+// NewRouteMap returns an empty RouteMap
+func NewRouteMap() *RouteMap {
+	return &RouteMap{routes: make(map[string]*Route)}
+}
+
+// AddScript records the routes defined in a script.
+// It returns the routes that were not known yet
+func (m *RouteMap) AddScript(scriptUrl string, body string) []Route {
+	var added []Route
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range FindRoutes(body) {
+		if existing, ok := m.routes[r.Path]; ok {
+			if existing.Framework == "" {
+				// only navigated to until now
+				existing.Framework, existing.Source = r.Framework, scriptUrl
+			}
+			continue
+		}
+		r.Source = scriptUrl
+		route := r
+		m.routes[r.Path] = &route
+		added = append(added, r)
+	}
+	return added
+}
+
+// Visit records a navigation to pageUrl, marking the route it matches as visited. Applications using hash
+// based routing, such as example.com/#/users, are matched on the fragment. Paths matching no known route
+// are added as routes of their own.
+// It returns the path of the route visited
+func (m *RouteMap) Visit(pageUrl string) string {
+	u, err := url.Parse(pageUrl)
+	if err != nil {
+		return ""
+	}
+	path := u.Path
+	if strings.HasPrefix(u.Fragment, "/") {
+		path = strings.SplitN(u.Fragment, "?", 2)[0]
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r, ok := m.routes[path]; ok {
+		r.Visited = true
+		return path
+	}
+	// catch-all routes only count when no other route matches
+	var match *Route
+	matchCatchAll := false
+	for _, r := range m.routes {
+		ok, catchAll := matchRoute(r.Path, path)
+		if !ok || match != nil && catchAll && !matchCatchAll {
+			continue
+		}
+		if match == nil || !catchAll && matchCatchAll || r.Path < match.Path {
+			match, matchCatchAll = r, catchAll
+		}
+	}
+	if match == nil {
+		match = &Route{Path: path}
+		m.routes[path] = match
+	}
+	match.Visited = true
+	return match.Path
+}
+
+// Routes returns the routes recorded so far, sorted by path
+func (m *RouteMap) Routes() []Route {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]Route, 0, len(m.routes))
+	for _, r := range m.routes {
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
+
+// Restore records routes returned by Routes, such as those found in a previous session. Routes already known
+// keep what they recorded, and are marked as visited when either copy was
+func (m *RouteMap) Restore(routes []Route) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range routes {
+		if existing, ok := m.routes[r.Path]; ok {
+			if existing.Framework == "" {
+				existing.Framework, existing.Source = r.Framework, r.Source
+			}
+			existing.Visited = existing.Visited || r.Visited
+			continue
+		}
+		route := r
+		m.routes[r.Path] = &route
+	}
+}
+
+// matchRoute reports whether path matches a route pattern, where segments starting with a colon match any
+// segment, and whether it did through a catch-all segment matching anything
+func matchRoute(pattern string, path string) (bool, bool) {
+	ps := strings.Split(strings.Trim(pattern, "/"), "/")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, p := range ps {
+		if isCatchAllSegment(p) {
+			return true, true
+		}
+		if i >= len(segments) {
+			return false, false
+		}
+		if !strings.HasPrefix(p, ":") && p != segments[i] {
+			return false, false
+		}
+	}
+	return len(ps) == len(segments), false
+}
+
+func isCatchAllSegment(p string) bool {
+	return p == "*" || p == "**" || strings.HasPrefix(p, ":") && strings.HasSuffix(p, "(.*)*")
+}
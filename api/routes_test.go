@@ -0,0 +1,104 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+const vueRouterScript = `import VueRouter from 'vue-router'
+import Home from './views/Home.vue'
+
+Vue.use(VueRouter)
+
+const routes = [
+  { path: '/', name: 'home', component: Home },
+  { path: '/login', component: () => import('./views/Login.vue') },
+  {
+    path: '/users/:id',
+    component: User,
+    children: [
+      { path: '', component: UserHome },
+      { path: 'profile', component: UserProfile },
+      { path: "posts", component: UserPosts, meta: { tabs: [{ path: "ignored" }] } }
+    ]
+  },
+  { path: '/admin', redirect: '/admin/dashboard' },
+  { path: '/:pathMatch(.*)*', component: NotFound }
+]
+
+const links = [{ path: '/not-a-route', label: 'Docs' }]
+
+export default new VueRouter({ mode: 'history', routes })`
+
+func routePaths(routes []Route) []string {
+	var paths []string
+	for _, r := range routes {
+		paths = append(paths, r.Path)
+	}
+	return paths
+}
+
+func TestFindRoutesVue(t *testing.T) {
+	routes := FindRoutes(vueRouterScript)
+	assert.Equal(t, routePaths(routes), []string{
+		"/",
+		"/login",
+		"/users/:id",
+		"/users/:id/profile",
+		"/users/:id/posts",
+		"/admin",
+		"/:pathMatch(.*)*",
+	})
+	assert.Equal(t, routes[0].Framework, "vue")
+}
+
+func TestFindRoutesReact(t *testing.T) {
+	script := `var r=n("react-router-dom");function App(){return(0,o.jsxs)(r.Routes,{children:[` +
+		`(0,o.jsx)(r.Route,{path:"/",element:(0,o.jsx)(Home,{})}),(0,o.jsx)(r.Route,{path:"/settings",element:(0,o.jsx)(Settings,{})})]})}`
+	assert.Equal(t, routePaths(FindRoutes(script)), []string{"/", "/settings"})
+}
+
+func TestFindRoutesWithoutRouter(t *testing.T) {
+	assert.Equal(t, len(FindRoutes(`var menu = [{path: "/docs", component: "a"}]`)), 0)
+}
+
+func TestRouteMap(t *testing.T) {
+	m := NewRouteMap()
+	added := m.AddScript("https://example.com/app.js", vueRouterScript)
+	assert.Equal(t, len(added), 7)
+	assert.Equal(t, added[0].Source, "https://example.com/app.js")
+	assert.Equal(t, len(m.AddScript("https://example.com/app.js", vueRouterScript)), 0)
+
+	assert.Equal(t, m.Visit("https://example.com/users/42/profile?tab=1"), "/users/:id/profile")
+	assert.Equal(t, m.Visit("https://example.com/nothing/here"), "/:pathMatch(.*)*")
+	assert.Equal(t, m.Visit("https://example.com/#/login"), "/login")
+
+	routes := m.Routes()
+	assert.Equal(t, len(routes), 7)
+	for _, r := range routes {
+		visited := r.Path == "/users/:id/profile" || r.Path == "/:pathMatch(.*)*" || r.Path == "/login"
+		assert.Equal(t, r.Visited, visited, r.Path)
+	}
+}
+
+func TestRouteMapUnknownRoutes(t *testing.T) {
+	m := NewRouteMap()
+	assert.Equal(t, m.Visit("https://example.com/pricing"), "/pricing")
+	assert.Equal(t, m.Routes(), []Route{{Path: "/pricing", Visited: true}})
+}
+
+func TestRouteMapRestore(t *testing.T) {
+	previous := NewRouteMap()
+	previous.AddScript("https://example.com/app.js", vueRouterScript)
+	previous.Visit("https://example.com/#/login")
+
+	m := NewRouteMap()
+	m.Visit("https://example.com/about")
+	m.Restore(previous.Routes())
+	assert.Equal(t, len(m.Routes()), 8)
+	assert.Equal(t, m.Visit("https://example.com/users/42/profile"), "/users/:id/profile")
+	for _, r := range m.Routes() {
+		visited := r.Path == "/login" || r.Path == "/about" || r.Path == "/users/:id/profile"
+		assert.Equal(t, r.Visited, visited, r.Path)
+	}
+}
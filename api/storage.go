@@ -0,0 +1,67 @@
+package api
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	storageWriteRegex = regexp.MustCompile(`\b(localStorage|sessionStorage)\s*\.\s*setItem\s*\(`)
+	sensitiveKeyRegex = regexp.MustCompile(`(?i)token|passw(or)?d|pwd|secret|jwt|api_?key|credential|session_?id`)
+)
+
+// StorageWrite describes a value stored in the browser storage under a sensitive looking key
+type StorageWrite struct {
+	Storage string // "localStorage" or "sessionStorage"
+	Key     string
+	Snippet string
+}
+
+// IsSensitiveStorageKey reports whether a storage key looks like it holds a token, password or other secret
+func IsSensitiveStorageKey(key string) bool {
+	return sensitiveKeyRegex.MatchString(key)
+}
+
+// FindSensitiveStorageWrites looks for localStorage.setItem and sessionStorage.setItem calls storing values
+// under sensitive looking keys, such as "token" or "password". Only keys given as string literals are checked,
+// keys built at runtime are skipped.
+// It returns a list of writes found in body
+func FindSensitiveStorageWrites(body string) []StorageWrite {
+	var result []StorageWrite
+	for _, loc := range storageWriteRegex.FindAllStringSubmatchIndex(body, -1) {
+		end := matchClosing(body, loc[1]-1)
+		if end == -1 {
+			continue
+		}
+		args := splitArgs(body[loc[1]:end])
+		if len(args) < 2 {
+			continue
+		}
+		key, ok := plainStringLiteral(args[0])
+		if !ok || !IsSensitiveStorageKey(key) {
+			continue
+		}
+		result = append(result, StorageWrite{
+			Storage: body[loc[2]:loc[3]],
+			Key:     key,
+			Snippet: snippet(body, loc[0], end+1),
+		})
+	}
+	return result
+}
+
+// plainStringLiteral returns the value of a JavaScript string literal without escape sequences or substitutions
+func plainStringLiteral(arg string) (string, bool) {
+	if len(arg) < 2 {
+		return "", false
+	}
+	quote := arg[0]
+	if (quote != '"' && quote != '\'' && quote != '`') || arg[len(arg)-1] != quote {
+		return "", false
+	}
+	value := arg[1 : len(arg)-1]
+	if strings.ContainsAny(value, `\`+string(quote)) || (quote == '`' && strings.Contains(value, "${")) {
+		return "", false
+	}
+	return value, true
+}
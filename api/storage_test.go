@@ -0,0 +1,39 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+const tokenWrite = `fetch("/login").then(r => r.json()).then(function(data) {
+	localStorage.setItem("access_token", data.token);
+	localStorage.setItem("theme", "dark");
+});`
+
+func TestFindSensitiveStorageWrites(t *testing.T) {
+	writes := FindSensitiveStorageWrites(tokenWrite)
+	assert.Equal(t, writes, []StorageWrite{{
+		Storage: "localStorage",
+		Key:     "access_token",
+		Snippet: `localStorage.setItem("access_token", data.token)`,
+	}})
+
+	writes = FindSensitiveStorageWrites(`window.sessionStorage.setItem('userPassword', form.password.value)`)
+	assert.Equal(t, len(writes), 1)
+	assert.Equal(t, writes[0].Storage, "sessionStorage")
+	assert.Equal(t, writes[0].Key, "userPassword")
+
+	assert.Equal(t, len(FindSensitiveStorageWrites("localStorage.setItem(`client_secret`, s)")), 1)
+	// keys built at runtime cannot be checked
+	assert.Equal(t, len(FindSensitiveStorageWrites(`localStorage.setItem(prefix + "token", t)`)), 0)
+	assert.Equal(t, len(FindSensitiveStorageWrites("localStorage.setItem(`${app}_token`, t)")), 0)
+	// reading a token is not storing one
+	assert.Equal(t, len(FindSensitiveStorageWrites(`localStorage.getItem("token")`)), 0)
+}
+
+func TestIsSensitiveStorageKey(t *testing.T) {
+	assert.Equal(t, IsSensitiveStorageKey("idToken"), true)
+	assert.Equal(t, IsSensitiveStorageKey("API_KEY"), true)
+	assert.Equal(t, IsSensitiveStorageKey("sessionId"), true)
+	assert.Equal(t, IsSensitiveStorageKey("cart"), false)
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// regexKeywords are the keywords after which a slash starts a regular expression rather than a division
+var regexKeywords = map[string]bool{
+	"return": true, "typeof": true, "case": true, "do": true, "else": true, "in": true, "of": true, "new": true,
+	"delete": true, "void": true, "throw": true, "instanceof": true, "yield": true, "await": true,
+}
+
+// CheckJSON reports whether body is a single valid JSON value.
+// It returns an error describing the problem, nil when body is valid
+func CheckJSON(body string) error {
+	if !json.Valid([]byte(body)) {
+		var v interface{}
+		return json.Unmarshal([]byte(body), &v)
+	}
+	return nil
+}
+
+// CheckHTML is a lenient check of an HTML document, as browsers recover from most errors. It only catches what
+// swallows the rest of a page: comments, scripts, styles and tags left open at the end of the body.
+// It returns an error describing the problem, nil when body looks fine
+func CheckHTML(body string) error {
+	lower := strings.ToLower(body)
+	for i := 0; i < len(lower); i++ {
+		if lower[i] != '<' {
+			continue
+		}
+		rest := lower[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end == -1 {
+				return fmt.Errorf("comment at offset %d is not closed", i)
+			}
+			i += end + 6
+		case strings.HasPrefix(rest, "<script") || strings.HasPrefix(rest, "<style"):
+			name := "script"
+			if strings.HasPrefix(rest, "<style") {
+				name = "style"
+			}
+			if next := len(name) + 1; next < len(rest) && isTagNameChar(rest[next]) {
+				continue
+			}
+			end := strings.Index(rest, "</"+name)
+			if end == -1 {
+				return fmt.Errorf("<%s> at offset %d is not closed", name, i)
+			}
+			i += end + 1
+		case len(rest) > 1 && (isLetter(rest[1]) || rest[1] == '/' || rest[1] == '!'):
+			if strings.IndexByte(rest, '>') == -1 {
+				return fmt.Errorf("tag at offset %d is not closed", i)
+			}
+		}
+	}
+	return nil
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isTagNameChar(c byte) bool {
+	return isLetter(c) || c >= '0' && c <= '9' || c == '-'
+}
+
+// CheckJavaScript is a basic syntax check of a script: brackets, braces and parentheses must balance, and
+// strings, template literals, comments and regular expressions must be closed. It is no parser, so it misses
+// most errors, but it catches bodies that were cut or spliced in the wrong place.
+// It returns an error describing the problem, nil when body looks fine
+func CheckJavaScript(body string) error {
+	// stack holds the open brackets, and '$' for the expressions of template literals
+	var stack []byte
+	closing := map[byte]byte{')': '(', ']': '[', '}': '{'}
+	// prev is the last significant character before i, to tell regular expressions from divisions
+	var prev byte
+	prevWord := ""
+	inTemplate := false
+
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if inTemplate {
+			switch {
+			case c == '\\':
+				i++
+			case c == '`':
+				inTemplate = false
+				prev, prevWord = '`', ""
+			case c == '$' && i+1 < len(body) && body[i+1] == '{':
+				stack = append(stack, '$')
+				inTemplate = false
+				prev, prevWord = '{', ""
+				i++
+			}
+			continue
+		}
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			continue
+		case strings.HasPrefix(body[i:], "//"):
+			end := strings.IndexByte(body[i:], '\n')
+			if end == -1 {
+				return nil
+			}
+			i += end
+			continue
+		case strings.HasPrefix(body[i:], "/*"):
+			end := strings.Index(body[i+2:], "*/")
+			if end == -1 {
+				return fmt.Errorf("comment at offset %d is not closed", i)
+			}
+			i += end + 3
+			continue
+		case c == '"' || c == '\'':
+			end := stringEnd(body, i)
+			if end == -1 {
+				return fmt.Errorf("string at offset %d is not closed", i)
+			}
+			i = end
+		case c == '`':
+			inTemplate = true
+		case (c == '+' || c == '-') && i+1 < len(body) && body[i+1] == c:
+			// taken as a postfix increment, which ends an operand
+			i++
+			prev, prevWord = ')', ""
+			continue
+		case c == '/' && startsRegex(prev, prevWord):
+			end := regexEnd(body, i)
+			if end == -1 {
+				return fmt.Errorf("regular expression at offset %d is not closed", i)
+			}
+			i = end
+			// a regular expression ends an operand, like a closing parenthesis
+			prev, prevWord = ')', ""
+			continue
+		case c == '(' || c == '[' || c == '{':
+			stack = append(stack, c)
+		case c == ')' || c == ']' || c == '}':
+			if len(stack) == 0 {
+				return fmt.Errorf("unexpected %c at offset %d", c, i)
+			}
+			open := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if c == '}' && open == '$' {
+				inTemplate = true
+				continue
+			}
+			if open != closing[c] {
+				return fmt.Errorf("unexpected %c at offset %d", c, i)
+			}
+		}
+
+		if isJsIdentChar(c) {
+			start := i
+			for i+1 < len(body) && isJsIdentChar(body[i+1]) {
+				i++
+			}
+			prevWord = body[start : i+1]
+			prev = body[i]
+		} else {
+			prev, prevWord = body[i], ""
+		}
+	}
+	if inTemplate {
+		return errors.New("template literal is not closed")
+	}
+	if len(stack) > 0 {
+		return fmt.Errorf("%d bracket(s) left open", len(stack))
+	}
+	return nil
+}
+
+// startsRegex reports whether a slash following prev, or the word prevWord, starts a regular expression
+func startsRegex(prev byte, prevWord string) bool {
+	if prevWord != "" {
+		return regexKeywords[prevWord]
+	}
+	return prev == 0 || !(isJsIdentChar(prev) || prev == ')' || prev == ']' || prev == '}' || prev == '"' ||
+		prev == '\'' || prev == '`')
+}
+
+func isJsIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// stringEnd returns the index of the quote closing the string starting at i, -1 if it is not closed on its line
+func stringEnd(body string, i int) int {
+	quote := body[i]
+	for j := i + 1; j < len(body); j++ {
+		switch body[j] {
+		case '\\':
+			j++
+		case quote:
+			return j
+		case '\n':
+			return -1
+		}
+	}
+	return -1
+}
+
+// regexEnd returns the index of the slash closing the regular expression starting at i, -1 if it is not closed
+// on its line
+func regexEnd(body string, i int) int {
+	inClass := false
+	for j := i + 1; j < len(body); j++ {
+		switch body[j] {
+		case '\\':
+			j++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				return j
+			}
+		case '\n':
+			return -1
+		}
+	}
+	return -1
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestCheckJSON(t *testing.T) {
+	assert.Equal(t, CheckJSON(`{"a": [1, 2, {"b": null}]}`), nil)
+	assert.Equal(t, CheckJSON(`{"a": [1, 2}`) != nil, true)
+	assert.Equal(t, CheckJSON(`{"a": 1}{"b": 2}`) != nil, true)
+	assert.Equal(t, CheckJSON(``) != nil, true)
+}
+
+func TestCheckHTML(t *testing.T) {
+	// browsers recover from unclosed elements and stray end tags
+	assert.Equal(t, CheckHTML(`<html><body><p>one<p>two</div><script>if (a < b) {}</script><scripts></scripts>`), nil)
+	assert.Equal(t, CheckHTML(`<p>a < b</p>`), nil)
+
+	assert.Equal(t, CheckHTML(`<body><!-- hidden</body>`) != nil, true)
+	assert.Equal(t, CheckHTML(`<body><script>var a = 1;</body>`) != nil, true)
+	assert.Equal(t, CheckHTML(`<body><div class="x"`) != nil, true)
+}
+
+func TestCheckJavaScript(t *testing.T) {
+	valid := []string{
+		`function f(a) { return a / 2 / (b[0] || 1); }`,
+		"var s = `total: ${items.map(i => { return i.price; }).join(\", \")}`;",
+		`var re = /[/)\]]+/g, x = "({[", y = '\'}';`,
+		`if (/^a/.test(s)) { x = a++ / 2; } // unbalanced ( in a comment`,
+		`/* { */ var a = typeof /x/;`,
+	}
+	for _, js := range valid {
+		assert.Equal(t, CheckJavaScript(js), nil, js)
+	}
+
+	invalid := []string{
+		`function f(a) { return a;`,
+		`var a = [1, 2);`,
+		`var s = "unterminated;` + "\n",
+		"var t = `open ${a}",
+		`var a = 1; /* open`,
+		`x = a.replace(/[)/g, "")`,
+		`}`,
+	}
+	for _, js := range invalid {
+		assert.Equal(t, CheckJavaScript(js) != nil, true, js)
+	}
+}
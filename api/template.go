@@ -0,0 +1,85 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"text/template"
+)
+
+// TemplateContext holds the request data response templates are rendered with
+type TemplateContext struct {
+	Method  string
+	Url     string
+	Path    string
+	Query   map[string]string // First value of each query parameter
+	Headers map[string]string // Request headers, as sent by the browser
+	Body    string            // Response body the template replaces
+}
+
+// NewTemplateContext returns the context a template is rendered with for a request and the body of its response.
+// Header values that are not strings are skipped
+func NewTemplateContext(method string, rawUrl string, headers map[string]interface{}, body string) TemplateContext {
+	ctx := TemplateContext{
+		Method:  method,
+		Url:     rawUrl,
+		Query:   make(map[string]string),
+		Headers: make(map[string]string),
+		Body:    body,
+	}
+	if u, err := url.Parse(rawUrl); err == nil {
+		ctx.Path = u.Path
+		for name, values := range u.Query() {
+			ctx.Query[name] = values[0]
+		}
+	}
+	for name, v := range headers {
+		if s, ok := v.(string); ok {
+			ctx.Headers[name] = s
+		}
+	}
+	return ctx
+}
+
+// Header returns the value of a request header, whatever the case of its name, or an empty string
+func (c TemplateContext) Header(name string) string {
+	for k, v := range c.Headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// Param returns the first value of a query parameter, or an empty string
+func (c TemplateContext) Param(name string) string {
+	return c.Query[name]
+}
+
+// templateFuncs are the functions available to response templates, on top of the text/template ones
+var templateFuncs = template.FuncMap{
+	// json quotes a value as a JSON string, or encodes any other value as JSON
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ParseTemplate parses a response template written with the text/template syntax, such as
+// {"user": {{.Param "user" | json}}, "agent": {{.Header "User-Agent" | json}}}. Values are inserted as they are,
+// use the json, html or js functions to escape them for the body they end up in.
+// It returns the template and an error if its syntax is invalid
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("response").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+}
+
+// RenderTemplate renders a response template with ctx.
+// It returns the rendered body and an error
+func RenderTemplate(t *template.Template, ctx TemplateContext) (string, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, ctx); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate(`{"user": {{.Param "user" | json}}, "method": "{{.Method}}", "path": "{{.Path}}", ` +
+		`"agent": {{.Header "user-agent" | json}}, "missing": "{{.Param "none"}}{{index .Query "none"}}"}`)
+	assert.Equal(t, err, nil)
+
+	ctx := NewTemplateContext("GET", "https://example.com/api/me?user=al%22ice&user=bob",
+		map[string]interface{}{"User-Agent": "Mozilla/5.0", "X-Count": 1}, `{"user": "alice"}`)
+	assert.Equal(t, ctx.Headers, map[string]string{"User-Agent": "Mozilla/5.0"})
+
+	body, err := RenderTemplate(tmpl, ctx)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, `{"user": "al\"ice", "method": "GET", "path": "/api/me", "agent": "Mozilla/5.0", "missing": ""}`)
+}
+
+func TestRenderTemplateWithBody(t *testing.T) {
+	tmpl, err := ParseTemplate(`{{if eq (.Param "debug") "1"}}/* {{.Url}} */{{end}}{{.Body}}`)
+	assert.Equal(t, err, nil)
+	body, err := RenderTemplate(tmpl, NewTemplateContext("GET", "https://example.com/app.js?debug=1", nil, "var a;"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, "/* https://example.com/app.js?debug=1 */var a;")
+
+	_, err = ParseTemplate(`{{.Param "user"`)
+	assert.Equal(t, err != nil, true)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"strings"
+)
+
+// RewriteWebSocketUrls replaces the WebSocket url prefix from with to in the string literals of a script, so
+// that "wss://prod.example.com/socket" can be sent to "ws://localhost:8080/socket". Only urls starting a quoted
+// or template string are rewritten, and only when the prefix ends at a url boundary, so that
+// "wss://prod.example.com.evil" is left alone. Urls built at runtime, such as "wss://" + host, cannot be
+// matched and are left unchanged. Prefixes written with escaped slashes, as found in inline JSON, are matched too.
+// It returns the new body and the number of urls rewritten
+func RewriteWebSocketUrls(body string, from string, to string) (string, int) {
+	if !strings.HasPrefix(from, "ws://") && !strings.HasPrefix(from, "wss://") {
+		return body, 0
+	}
+	count := 0
+	body, n := rewriteUrlPrefix(body, from, to)
+	count += n
+	escapedFrom := strings.Replace(from, "/", `\/`, -1)
+	body, n = rewriteUrlPrefix(body, escapedFrom, strings.Replace(to, "/", `\/`, -1))
+	count += n
+	return body, count
+}
+
+func rewriteUrlPrefix(body string, from string, to string) (string, int) {
+	var b strings.Builder
+	count := 0
+	last := 0
+	for i := 0; ; {
+		j := strings.Index(body[i:], from)
+		if j == -1 {
+			break
+		}
+		start := i + j
+		end := start + len(from)
+		i = end
+		if start == 0 || !strings.ContainsRune("\"'`", rune(body[start-1])) || !isUrlBoundary(body, end) {
+			continue
+		}
+		b.WriteString(body[last:start])
+		b.WriteString(to)
+		last = end
+		count++
+	}
+	b.WriteString(body[last:])
+	return b.String(), count
+}
+
+// isUrlBoundary reports whether the host or path of a url can end at index i of body
+func isUrlBoundary(body string, i int) bool {
+	if i == len(body) {
+		return true
+	}
+	return strings.ContainsRune("/?#:\"'`\\$", rune(body[i]))
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestRewriteWebSocketUrls(t *testing.T) {
+	script := `var a = new WebSocket("wss://prod.example.com/socket?v=2");
+var b = new WebSocket('wss://prod.example.com');
+var c = new WebSocket(` + "`wss://prod.example.com/rooms/${room}`" + `);
+var d = new WebSocket("wss://prod.example.com.evil.net/socket");
+var e = new WebSocket("wss://" + "prod.example.com/socket");
+var f = "see wss://prod.example.com/docs";
+var g = JSON.parse('{"ws":"wss:\/\/prod.example.com\/live"}');`
+
+	result, n := RewriteWebSocketUrls(script, "wss://prod.example.com", "ws://localhost:8080")
+	assert.Equal(t, n, 4)
+	assert.Equal(t, result, `var a = new WebSocket("ws://localhost:8080/socket?v=2");
+var b = new WebSocket('ws://localhost:8080');
+var c = new WebSocket(`+"`ws://localhost:8080/rooms/${room}`"+`);
+var d = new WebSocket("wss://prod.example.com.evil.net/socket");
+var e = new WebSocket("wss://" + "prod.example.com/socket");
+var f = "see wss://prod.example.com/docs";
+var g = JSON.parse('{"ws":"ws:\/\/localhost:8080\/live"}');`)
+}
+
+func TestRewriteWebSocketUrlsNotWebSocket(t *testing.T) {
+	script := `fetch("https://prod.example.com/api")`
+	result, n := RewriteWebSocketUrls(script, "https://prod.example.com", "http://localhost")
+	assert.Equal(t, n, 0)
+	assert.Equal(t, result, script)
+}
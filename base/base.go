@@ -1,20 +1,162 @@
 // Package base provides primitives for running gorp from the command line
 package base
 
+import "time"
+
 // Configuration holds the configuration of gorp and it is used
 // when parsing the yaml config file
 type Configuration struct {
-	Scope       	string
-	Script	 		*Script
-	Flags       	[]string
-	XHRBreakPoints  []string
-	Modules     	ModulesList
-	Verbose     	bool
+	Scope                string
+	Script               *Script
+	Flags                []string
+	Device               string
+	CPUThrottle          float64
+	DialogAction         string
+	DialogPromptText     string
+	XHRBreakPoints       []string
+	Breakpoints          []Breakpoint
+	Modules              ModulesList
+	Verbose              bool
+	Color                bool
+	InterceptRequests    bool
+	AnswerPreflights     bool
+	FrameFilter          []string
+	FirstPartyOnly       bool
+	FirstPartySubdomains bool
+	InitiatorFilter      []string
+	ProtocolFilter       []string
+	MaxInflight          int
+	MaxRequests          int
+	MaxDuration          time.Duration
+	SampleRate           *float64
+	SampleSeed           int64
+	InterceptionWatchdog time.Duration
+	ScriptReplacements   []ScriptReplacement
+	OverlayDir           string
+	OverlayHost          string
+	Screenshots          *Screenshots
+	QueryOverrides       []QueryOverride
+	ContentTypeOverrides []ContentTypeOverride
+	PostBodyRules        []PostBodyRule
+	BodyMatchRules       []BodyMatchRule
+	ProcessWorkerScripts bool
+	AllowHeaders         []string
+	DenyHeaders          []string
+	ProcessorTimeout     time.Duration
+	SafeMode             bool
+	SafeModeScripts      bool
+	ProcessorInputDir    string
+	DumpProcessorInputs  bool
+	InterceptBinary      bool
+	ProcessDataURIs      bool
+	ProcessInlineScripts bool
+	HookEval             bool
+	HookStorage          bool
+	CSPViolations        bool
+	DumpScripts          string
+	HeaderConditions     []HeaderCondition
+	FaultRules           []FaultRule
+	Latency              map[string]LatencyRange
+	Credentials          []Credentials
+	RecordFixtures       string
+	StateFile            string
+	OpenAPIFile          string
+	RecordRawResponses   bool
+	UpstreamProxy        string
+	ClientCert           string
+	ClientKey            string
+	DedupFindings        bool
+	TargetId             string
+	RecompressResponse   bool
+	KeepDateHeader       bool
+	InterceptManifests   bool
+	StartUrl             string
+	NavigationRetries    int
+	NavigationTimeout    time.Duration
+
+	ResetFindingsOnNavigation bool
+	NavigationProcessors      []string
 }
 
 type Script struct {
-	Path   string
-	Watch  bool
+	Path  string
+	Watch bool
+}
+
+// Screenshots holds the settings for capturing screenshots on page loads
+type Screenshots struct {
+	Dir      string
+	FullPage bool
+}
+
+// ScriptReplacement holds a url pattern and the local file to serve for scripts matching it
+type ScriptReplacement struct {
+	Url  string
+	Path string
+}
+
+// QueryOverride holds the query string parameters to set or remove for requests matching a url pattern.
+// Parameters to set are given as name=value pairs, as yaml keys would lose their case
+type QueryOverride struct {
+	Url    string
+	Set    []string
+	Remove []string
+}
+
+// ContentTypeOverride holds a url pattern and the Content-Type matching responses are served with
+type ContentTypeOverride struct {
+	Url         string
+	ContentType string
+}
+
+// PostBodyRule holds a regular expression to replace in the post data of requests matching a url pattern
+type PostBodyRule struct {
+	Url     string
+	Match   string
+	Replace string
+}
+
+// BodyMatchRule holds a regular expression response bodies must match for the listed processors to run on them
+type BodyMatchRule struct {
+	Match      string
+	Processors []string
+}
+
+// HeaderCondition holds a request header, and optionally its value, that processors require before they run.
+// It applies to all processors when Processors is empty
+type HeaderCondition struct {
+	Processors []string
+	Header     string
+	Value      string
+}
+
+// FaultRule holds a url pattern and the fault to inject into matching responses, such as "error" or "drop"
+type FaultRule struct {
+	Url         string
+	Fault       string
+	Probability float64
+	Status      int
+	Delay       time.Duration
+}
+
+// LatencyRange holds the shortest and longest delay added to the responses of a resource type
+type LatencyRange struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Credentials holds the username and password provided when a server matching a url pattern asks for
+// HTTP authentication
+type Credentials struct {
+	Url      string
+	Username string
+	Password string
+}
+
+// Breakpoint holds a script url and the 1-based line to break on, capturing the variables in scope
+type Breakpoint struct {
+	Url  string
+	Line int
 }
 
 // ModuleConfig holds the path and options for gorp modules
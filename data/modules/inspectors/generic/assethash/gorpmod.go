@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+)
+
+type assetHash struct {
+	Registry modules.Registry
+	Options  []modules.Option
+	store    *api.HashStore
+	path     string
+}
+
+func (a *assetHash) Init() {
+	a.Registry = modules.Registry{
+		Name:        "AssetHash",
+		DocTypes:    []string{"Document", "Script"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/assethash/gorpmod.go",
+		Description: "Records the SHA-256 hash of every response body and reports assets that changed since the baseline",
+		Notes:       "The baseline is updated as new hashes are seen unless UpdateBaseline is false",
+	}
+
+	a.Options = []modules.Option{
+		{
+			Name:        "Baseline",
+			Value:       "./hashes.json",
+			Required:    true,
+			Description: "Path of the file holding the hashes from previous sessions",
+		},
+		{
+			Name:        "UpdateBaseline",
+			Value:       "true",
+			Required:    true,
+			Description: "Save new hashes to the baseline file as they are seen",
+		},
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When an asset changes, print it to console",
+		},
+	}
+}
+
+func (a *assetHash) Configure(options []modules.Option) error {
+	path, err := modules.GetModuleOption(options, "Baseline")
+	if err != nil {
+		return err
+	}
+	store, err := api.LoadHashStore(path)
+	if err != nil {
+		return err
+	}
+	a.store, a.path = store, path
+	return nil
+}
+
+func (a *assetHash) Inspect(webData modules.WebData) error {
+	if webData.Type != "Document" && webData.Type != "Script" {
+		return nil
+	}
+
+	seen := len(a.store.History(webData.Url))
+	change := a.store.Record(webData.Url, webData.Body)
+	if change != nil {
+		o, err := modules.GetModuleOption(a.Options, "Print")
+		if err != nil {
+			return err
+		}
+		if o == "true" {
+			log.Println("[+] Asset changed: " + change.Url + " (" + change.Previous + " -> " + change.Current + ")")
+		}
+		webData.Findings.Report(modules.Finding{
+			Rule:   a.Registry.Name + "/changed",
+			Url:    change.Url,
+			Detail: change.Previous + " -> " + change.Current,
+		})
+	}
+
+	update, err := modules.GetModuleOption(a.Options, "UpdateBaseline")
+	if err != nil {
+		return err
+	}
+	if update == "true" && len(a.store.History(webData.Url)) != seen {
+		return a.store.Save(a.path)
+	}
+	return nil
+}
+
+// MarshalState returns the latest hash of every asset seen, so that the next session detects changes even
+// when the baseline file is not updated
+func (a *assetHash) MarshalState() ([]byte, error) {
+	return json.Marshal(a.store.Latest())
+}
+
+// UnmarshalState adds the hashes saved by MarshalState to the baseline
+func (a *assetHash) UnmarshalState(data []byte) error {
+	var hashes map[string]string
+	if err := json.Unmarshal(data, &hashes); err != nil {
+		return err
+	}
+	a.store.AddBaseline(hashes)
+	return nil
+}
+
+func (a *assetHash) GetRegistry() modules.Registry {
+	return a.Registry
+}
+
+func (a *assetHash) GetOptions() []modules.Option {
+	return a.Options
+}
+
+var Inspector assetHash
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+)
+
+type clientStorage struct {
+	Registry modules.Registry
+	Options  []modules.Option
+}
+
+func (c *clientStorage) Init() {
+	c.Registry = modules.Registry{
+		Name:        "ClientStorage",
+		DocTypes:    []string{"Document", "Script", "Storage"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/clientstorage/gorpmod.go",
+		Description: "Finds tokens, passwords and other secrets stored in localStorage or sessionStorage",
+		Notes:       "Set hookStorage: true in the config file to also catch the writes made at runtime",
+	}
+
+	c.Options = []modules.Option{
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When a secret is written to the browser storage, print it to console",
+		},
+	}
+}
+
+func (c *clientStorage) Inspect(webData modules.WebData) error {
+	if webData.Type != "Document" && webData.Type != "Script" && webData.Type != "Storage" {
+		return nil
+	}
+	o, err := modules.GetModuleOption(c.Options, "Print")
+	if err != nil {
+		return err
+	}
+	stdOut := o == "true"
+
+	for _, write := range api.FindSensitiveStorageWrites(webData.Body) {
+		if stdOut {
+			log.Println("[+] " + write.Key + " stored in " + write.Storage + " by " + webData.Url + ": " + write.Snippet)
+		}
+		webData.Findings.Report(modules.Finding{
+			Rule:   c.Registry.Name + "/" + write.Storage,
+			Url:    webData.Url,
+			Detail: write.Snippet,
+		})
+	}
+	return nil
+}
+
+func (c *clientStorage) GetRegistry() modules.Registry {
+	return c.Registry
+}
+
+func (c *clientStorage) GetOptions() []modules.Option {
+	return c.Options
+}
+
+var Inspector clientStorage
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"strings"
+)
+
+type cors struct {
+	Registry modules.Registry
+	Options  []modules.Option
+}
+
+func (c *cors) Init() {
+	c.Registry = modules.Registry{
+		Name:        "CORS",
+		DocTypes:    []string{"Document", "XHR", "Fetch", "Script"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/cors/gorpmod.go",
+		Description: "Flags responses whose CORS headers let other sites read them, such as a reflected Origin allowed with credentials",
+		Notes:       "A reflected origin is informational until confirmed by sending the request again with an arbitrary Origin header",
+	}
+
+	c.Options = []modules.Option{
+		{
+			Name:        "TrustedOrigins",
+			Value:       "",
+			Required:    false,
+			Description: "Comma separated origins the application is known to share its responses with, which are not reported",
+		},
+		{
+			Name:        "ReportInformational",
+			Value:       "false",
+			Required:    true,
+			Description: "Also report issues that need confirming, such as the Origin of the page reflected with credentials",
+		},
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When a risky CORS configuration is found, print it to console",
+		},
+	}
+}
+
+func (c *cors) Inspect(webData modules.WebData) error {
+	if webData.Type == "Request" {
+		return nil
+	}
+	allowOrigin := header(webData.Headers, "Access-Control-Allow-Origin")
+	if allowOrigin == "" {
+		return nil
+	}
+	trusted, err := modules.GetModuleOption(c.Options, "TrustedOrigins")
+	if err != nil {
+		return err
+	}
+	for _, o := range strings.Split(trusted, ",") {
+		if o = strings.TrimSpace(o); o != "" && strings.EqualFold(o, allowOrigin) {
+			return nil
+		}
+	}
+
+	p, err := modules.GetModuleOption(c.Options, "Print")
+	if err != nil {
+		return err
+	}
+	informational, err := modules.GetModuleOption(c.Options, "ReportInformational")
+	if err != nil {
+		return err
+	}
+	origin := header(webData.RequestHeaders, "Origin")
+	credentials := header(webData.Headers, "Access-Control-Allow-Credentials")
+	for _, issue := range api.CheckCORS(webData.Url, origin, allowOrigin, credentials) {
+		if issue.Informational && informational != "true" {
+			continue
+		}
+		if p == "true" {
+			log.Println("[+] Risky CORS configuration (" + issue.Kind + ") in " + webData.Url + ": " + issue.Detail)
+		}
+		webData.Findings.Report(modules.Finding{
+			Rule:   c.Registry.Name + "/" + issue.Kind,
+			Url:    webData.Url,
+			Detail: issue.Detail,
+		})
+	}
+	return nil
+}
+
+// header returns the value of a header regardless of the casing used for its name
+func header(headers map[string]interface{}, name string) string {
+	for k, v := range headers {
+		if s, ok := v.(string); ok && strings.EqualFold(k, name) {
+			return s
+		}
+	}
+	return ""
+}
+
+func (c *cors) GetRegistry() modules.Registry {
+	return c.Registry
+}
+
+func (c *cors) GetOptions() []modules.Option {
+	return c.Options
+}
+
+var Inspector cors
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"regexp"
+	"strconv"
+)
+
+type exfiltration struct {
+	Registry modules.Registry
+	Options  []modules.Option
+	rules    api.ExfilRules
+}
+
+func (e *exfiltration) Init() {
+	e.Registry = modules.Registry{
+		Name:        "Exfiltration",
+		DocTypes:    []string{"Request"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/exfiltration/gorpmod.go",
+		Description: "Flags outgoing requests with unusually large bodies or bodies carrying many emails or phone numbers",
+		Notes:       "Requires interceptRequests to be enabled. Clear a regex option to stop looking for that kind of data",
+	}
+
+	e.Options = []modules.Option{
+		{
+			Name:        "MaxBodySize",
+			Value:       "102400",
+			Required:    true,
+			Description: "Flag request bodies larger than this many bytes, 0 for no limit",
+		},
+		{
+			Name:        "MinMatches",
+			Value:       "3",
+			Required:    true,
+			Description: "Number of distinct emails or phone numbers a body must contain to be flagged",
+		},
+		{
+			Name:        "EmailRegex",
+			Value:       api.EmailPattern.String(),
+			Required:    false,
+			Description: "Regex matching email addresses",
+		},
+		{
+			Name:        "PhoneRegex",
+			Value:       api.PhonePattern.String(),
+			Required:    false,
+			Description: "Regex matching phone numbers",
+		},
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When a suspicious request is found, print it to console",
+		},
+	}
+}
+
+func (e *exfiltration) Inspect(webData modules.WebData) error {
+	if webData.Type != "Request" || webData.Body == "" {
+		return nil
+	}
+	o, err := modules.GetModuleOption(e.Options, "Print")
+	if err != nil {
+		return err
+	}
+	for _, s := range api.FindExfiltration(webData.Body, e.rules) {
+		if o == "true" {
+			log.Println("[+] Possible exfiltration (" + s.Kind + ") to " + webData.Url + ": " + s.Detail)
+		}
+		webData.Findings.Report(modules.Finding{
+			Rule:   e.Registry.Name + "/" + s.Kind,
+			Url:    webData.Url,
+			Detail: s.Detail,
+		})
+	}
+	return nil
+}
+
+func (e *exfiltration) Configure(options []modules.Option) error {
+	rules := api.ExfilRules{Patterns: make(map[string]*regexp.Regexp)}
+	o, err := modules.GetModuleOption(options, "MaxBodySize")
+	if err != nil {
+		return err
+	}
+	if rules.MaxBodySize, err = strconv.Atoi(o); err != nil {
+		return err
+	}
+	o, err = modules.GetModuleOption(options, "MinMatches")
+	if err != nil {
+		return err
+	}
+	if rules.MinMatches, err = strconv.Atoi(o); err != nil {
+		return err
+	}
+
+	for name, option := range map[string]string{"email": "EmailRegex", "phone": "PhoneRegex"} {
+		o, err = modules.GetModuleOption(options, option)
+		if err != nil || o == "" {
+			continue
+		}
+		p, err := regexp.Compile(o)
+		if err != nil {
+			return err
+		}
+		rules.Patterns[name] = p
+	}
+	e.rules = rules
+	return nil
+}
+
+func (e *exfiltration) GetRegistry() modules.Registry {
+	return e.Registry
+}
+
+func (e *exfiltration) GetOptions() []modules.Option {
+	return e.Options
+}
+
+var Inspector exfiltration
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+)
+
+type inlineSinks struct {
+	Registry modules.Registry
+	Options  []modules.Option
+}
+
+func (s *inlineSinks) Init() {
+	s.Registry = modules.Registry{
+		Name:        "InlineSinks",
+		DocTypes:    []string{"Document"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/inlinesinks/gorpmod.go",
+		Description: "Reports inline event handlers, javascript: urls and iframe srcdoc attributes of HTML documents",
+		Notes:       "Useful for DOM-XSS triage, as these attributes turn injected markup into script execution",
+	}
+
+	s.Options = []modules.Option{
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When an inline sink is found, print it to console",
+		},
+	}
+}
+
+func (s *inlineSinks) Inspect(webData modules.WebData) error {
+	if webData.Type != "Document" {
+		return nil
+	}
+	p, err := modules.GetModuleOption(s.Options, "Print")
+	if err != nil {
+		return err
+	}
+	for _, sink := range api.FindInlineSinks(webData.Body) {
+		detail := "<" + sink.Tag + " " + sink.Attribute + "=\"" + sink.Value + "\">"
+		if p == "true" {
+			log.Println("[+] Inline " + sink.Kind + " in " + webData.Url + ": " + detail)
+		}
+		webData.Findings.Report(modules.Finding{
+			Rule:   s.Registry.Name + "/" + sink.Kind,
+			Url:    webData.Url,
+			Detail: detail,
+		})
+	}
+	return nil
+}
+
+func (s *inlineSinks) GetRegistry() modules.Registry {
+	return s.Registry
+}
+
+func (s *inlineSinks) GetOptions() []modules.Option {
+	return s.Options
+}
+
+var Inspector inlineSinks
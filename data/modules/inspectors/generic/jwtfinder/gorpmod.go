@@ -0,0 +1,98 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"sync"
+)
+
+type jwtfinder struct {
+	Registry modules.Registry
+	Options  []modules.Option
+
+	mu       sync.Mutex
+	reported map[string]bool // Tokens already reported, as the same token is sent with every request
+}
+
+func (j *jwtfinder) Init() {
+	j.Registry = modules.Registry{
+		Name:        "JWTFinder",
+		DocTypes:    []string{"Document", "Script", "XHR", "Request"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/jwtfinder/gorpmod.go",
+		Description: "Finds JWTs in request and response headers and bodies and decodes their header and claims",
+		Notes:       "Tokens are decoded but their signatures are not verified. Each token is reported once, for the first url it was seen on",
+	}
+
+	j.Options = []modules.Option{
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When a JWT is found, print its decoded claims to console",
+		},
+	}
+}
+
+func (j *jwtfinder) Inspect(webData modules.WebData) error {
+	o, err := modules.GetModuleOption(j.Options, "Print")
+	if err != nil {
+		return err
+	}
+	stdOut := o == "true"
+
+	// tokens are mostly sent by the browser, in the Authorization header of requests
+	headers := []map[string]interface{}{webData.Headers}
+	if webData.Type != "Request" {
+		headers = append(headers, webData.RequestHeaders)
+	}
+	for _, h := range headers {
+		for k, v := range h {
+			if s, ok := v.(string); ok {
+				for _, jwt := range api.FindJwts(s) {
+					j.report(webData, jwt, "header "+k, stdOut)
+				}
+			}
+		}
+	}
+	for _, jwt := range api.FindJwts(webData.Body) {
+		j.report(webData, jwt, "body", stdOut)
+	}
+	return nil
+}
+
+// report reports the claims of a token found in webData, unless the token was already reported
+func (j *jwtfinder) report(webData modules.WebData, jwt *api.Jwt, where string, stdOut bool) {
+	j.mu.Lock()
+	if j.reported == nil {
+		j.reported = make(map[string]bool)
+	}
+	seen := j.reported[jwt.Raw]
+	j.reported[jwt.Raw] = true
+	j.mu.Unlock()
+	if seen {
+		return
+	}
+
+	claims := jwt.PrettyClaims()
+	if stdOut {
+		log.Println("[+] JWT found in " + where + " for " + webData.Url)
+		log.Println("[+] JWT claims for " + webData.Url + ": " + claims)
+	}
+	webData.Findings.Report(modules.Finding{
+		Rule:   j.Registry.Name,
+		Url:    webData.Url,
+		Detail: claims,
+	})
+}
+
+func (j *jwtfinder) GetRegistry() modules.Registry {
+	return j.Registry
+}
+
+func (j *jwtfinder) GetOptions() []modules.Option {
+	return j.Options
+}
+
+var Inspector jwtfinder
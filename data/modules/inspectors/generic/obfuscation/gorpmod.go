@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type obfuscation struct {
+	Registry     modules.Registry
+	Options      []modules.Option
+	threshold    float64
+	thresholdErr error
+	once         sync.Once
+}
+
+func (o *obfuscation) Init() {
+	o.Registry = modules.Registry{
+		Name:        "Obfuscation",
+		DocTypes:    []string{"Script"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/obfuscation/gorpmod.go",
+		Description: "Reports scripts that are likely obfuscated or packed, along with a score, to help decide which scripts to look at first",
+		Notes:       "The score is based on heuristics such as eval packers, _0x style identifiers, escape sequences, line length and entropy. Minified code alone does not get reported",
+	}
+
+	o.Options = []modules.Option{
+		{
+			Name:        "Threshold",
+			Value:       strconv.FormatFloat(api.DefaultObfuscationThreshold, 'f', -1, 64),
+			Required:    true,
+			Description: "Report scripts scoring above this value, between 0 and 1",
+		},
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When an obfuscated script is found, print it to console",
+		},
+	}
+}
+
+func (o *obfuscation) Inspect(webData modules.WebData) error {
+	if webData.Type != "Script" || webData.Body == "" {
+		return nil
+	}
+	o.once.Do(func() {
+		var t string
+		t, o.thresholdErr = modules.GetModuleOption(o.Options, "Threshold")
+		if o.thresholdErr == nil {
+			o.threshold, o.thresholdErr = strconv.ParseFloat(t, 64)
+		}
+	})
+	if o.thresholdErr != nil {
+		return o.thresholdErr
+	}
+
+	result := api.ScoreObfuscation(webData.Body)
+	if !result.Obfuscated(o.threshold) {
+		return nil
+	}
+	detail := fmt.Sprintf("score %.2f: %s", result.Score, strings.Join(result.Reasons, ", "))
+	if p, _ := modules.GetModuleOption(o.Options, "Print"); p == "true" {
+		log.Println("[+] Likely obfuscated script " + webData.Url + " (" + detail + ")")
+	}
+	webData.Findings.Report(modules.Finding{
+		Rule:   o.Registry.Name + "/obfuscated",
+		Url:    webData.Url,
+		Detail: detail,
+	})
+	return nil
+}
+
+func (o *obfuscation) GetRegistry() modules.Registry {
+	return o.Registry
+}
+
+func (o *obfuscation) GetOptions() []modules.Option {
+	return o.Options
+}
+
+var Inspector obfuscation
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+)
+
+type postMessage struct {
+	Registry modules.Registry
+	Options  []modules.Option
+}
+
+func (p *postMessage) Init() {
+	p.Registry = modules.Registry{
+		Name:        "PostMessage",
+		DocTypes:    []string{"Document", "Script"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/postmessage/gorpmod.go",
+		Description: "Finds postMessage calls targeting any origin and message listeners that do not check the origin of messages",
+		Notes:       "Listeners passed by reference are not analyzed",
+	}
+
+	p.Options = []modules.Option{
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When an insecure use of postMessage is found, print it to console",
+		},
+	}
+}
+
+func (p *postMessage) Inspect(webData modules.WebData) error {
+	if webData.Type != "Document" && webData.Type != "Script" {
+		return nil
+	}
+	o, err := modules.GetModuleOption(p.Options, "Print")
+	if err != nil {
+		return err
+	}
+	stdOut := o == "true"
+
+	for _, issue := range api.FindInsecurePostMessage(webData.Body) {
+		if stdOut {
+			log.Println("[+] Insecure postMessage usage (" + issue.Kind + ") in " + webData.Url + ": " + issue.Snippet)
+		}
+		webData.Findings.Report(modules.Finding{
+			Rule:   p.Registry.Name + "/" + issue.Kind,
+			Url:    webData.Url,
+			Detail: issue.Snippet,
+		})
+	}
+	return nil
+}
+
+func (p *postMessage) GetRegistry() modules.Registry {
+	return p.Registry
+}
+
+func (p *postMessage) GetOptions() []modules.Option {
+	return p.Options
+}
+
+var Inspector postMessage
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"github.com/DharmaOfCode/gorp/modules"
+	"io/ioutil"
+	"log"
+)
+
+type protobuf struct {
+	Registry modules.Registry
+	Options  []modules.Option
+
+	print   bool
+	schema  *modules.ProtoSchema
+	message string
+}
+
+func (p *protobuf) Init() {
+	p.Registry = modules.Registry{
+		Name:        "Protobuf",
+		DocTypes:    []string{"Request", "XHR", "Fetch", "Other"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/protobuf/gorpmod.go",
+		Description: "Decodes the protobuf and gRPC-Web messages sent and received by the page",
+		Notes:       "Without a .proto file fields are shown by number, like protoc --decode_raw does",
+	}
+
+	p.Options = []modules.Option{
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "Print decoded messages to console",
+		},
+		{
+			Name:        "Proto",
+			Value:       "",
+			Required:    false,
+			Description: "Path to a .proto file defining the messages, to name their fields",
+		},
+		{
+			Name:        "Message",
+			Value:       "",
+			Required:    false,
+			Description: "Name of the message of the .proto file the bodies hold",
+		},
+	}
+}
+
+func (p *protobuf) Configure(options []modules.Option) error {
+	var err error
+	if p.print, err = modules.GetModuleBool(options, "Print"); err != nil {
+		return err
+	}
+	path, err := modules.GetModuleOption(options, "Proto")
+	if err != nil || path == "" {
+		return err
+	}
+	if p.message, err = modules.GetRequiredModuleOption(options, "Message"); err != nil {
+		return errors.New("option Message is required with option Proto")
+	}
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	p.schema, err = modules.ParseProtoSchema(string(src))
+	return err
+}
+
+func (p *protobuf) Inspect(webData modules.WebData) error {
+	for _, m := range webData.Protobuf {
+		if p.schema != nil {
+			named, err := p.schema.Name(m, p.message)
+			if err != nil {
+				return err
+			}
+			m = named
+		}
+		if p.print {
+			log.Println("[+] Protobuf message in " + webData.Type + " " + webData.Url + ":\n" + m.String())
+		}
+		webData.Findings.Report(modules.Finding{
+			Rule:   p.Registry.Name + "/message",
+			Url:    webData.Url,
+			Detail: m.String(),
+		})
+	}
+	return nil
+}
+
+func (p *protobuf) GetRegistry() modules.Registry {
+	return p.Registry
+}
+
+func (p *protobuf) GetOptions() []modules.Option {
+	return p.Options
+}
+
+var Inspector protobuf
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"strconv"
+	"strings"
+)
+
+type reflections struct {
+	Registry modules.Registry
+	Options  []modules.Option
+	tracker  *api.ReflectionTracker
+}
+
+func (r *reflections) Init() {
+	r.Registry = modules.Registry{
+		Name:        "Reflections",
+		DocTypes:    []string{"Document", "XHR", "Request"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/reflections/gorpmod.go",
+		Description: "Finds request parameters whose value is reflected verbatim in the response, a good place to look for XSS",
+		Notes:       "Form parameters are only checked when interceptRequests is enabled",
+	}
+
+	r.Options = []modules.Option{
+		{
+			Name:        "MinLength",
+			Value:       "4",
+			Required:    true,
+			Description: "Ignore values shorter than this, as they are found in almost any response",
+		},
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When a reflected parameter is found, print it to console",
+		},
+	}
+}
+
+func (r *reflections) Configure(options []modules.Option) error {
+	o, err := modules.GetModuleOption(options, "MinLength")
+	if err != nil {
+		return err
+	}
+	minLength, err := strconv.Atoi(o)
+	if err != nil {
+		return err
+	}
+	r.tracker = api.NewReflectionTracker(minLength)
+	return nil
+}
+
+func (r *reflections) Inspect(webData modules.WebData) error {
+	if webData.Type == "Request" {
+		contentType := ""
+		for k, v := range webData.Headers {
+			if s, ok := v.(string); ok && strings.EqualFold(k, "Content-Type") {
+				contentType = s
+			}
+		}
+		r.tracker.Request(webData.RequestId, contentType, webData.Body)
+		return nil
+	}
+
+	o, err := modules.GetModuleOption(r.Options, "Print")
+	if err != nil {
+		return err
+	}
+	for _, reflection := range r.tracker.Response(webData.RequestId, webData.Url, webData.Body) {
+		if o == "true" {
+			log.Println("[+] Parameter " + reflection.Param + " reflected in " + reflection.Url + ": " + reflection.Value)
+		}
+		webData.Findings.Report(modules.Finding{
+			Rule:   r.Registry.Name + "/" + reflection.Param,
+			Url:    reflection.Url,
+			Detail: reflection.Value,
+		})
+	}
+	return nil
+}
+
+func (r *reflections) GetRegistry() modules.Registry {
+	return r.Registry
+}
+
+func (r *reflections) GetOptions() []modules.Option {
+	return r.Options
+}
+
+var Inspector reflections
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+)
+
+type resourceHints struct {
+	Registry modules.Registry
+	Options  []modules.Option
+}
+
+func (r *resourceHints) Init() {
+	r.Registry = modules.Registry{
+		Name:        "ResourceHints",
+		DocTypes:    []string{"Document"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/resourcehints/gorpmod.go",
+		Description: "Reports the origins and urls documents hint at with preconnect, dns-prefetch, preload and prefetch links",
+		Notes:       "Hinted origins and urls may never be requested, they map what the page expects to talk to",
+	}
+
+	r.Options = []modules.Option{
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When a resource hint is found, print it to console",
+		},
+	}
+}
+
+func (r *resourceHints) Inspect(webData modules.WebData) error {
+	if webData.Type != "Document" {
+		return nil
+	}
+	p, err := modules.GetModuleOption(r.Options, "Print")
+	if err != nil {
+		return err
+	}
+	for _, h := range api.FindResourceHints(webData.Body, webData.Url) {
+		detail := h.Url
+		if h.Rel == "dns-prefetch" || h.Rel == "preconnect" {
+			detail = h.Origin
+		}
+		if h.As != "" {
+			detail += " as " + h.As
+		}
+		if p == "true" {
+			log.Println("[+] Resource hint " + h.Rel + " in " + webData.Url + ": " + detail)
+		}
+		webData.Findings.Report(modules.Finding{
+			Rule:   r.Registry.Name + "/" + h.Rel,
+			Url:    webData.Url,
+			Detail: detail,
+		})
+	}
+	return nil
+}
+
+func (r *resourceHints) GetRegistry() modules.Registry {
+	return r.Registry
+}
+
+func (r *resourceHints) GetOptions() []modules.Option {
+	return r.Options
+}
+
+var Inspector resourceHints
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+)
+
+type routeMapper struct {
+	Registry modules.Registry
+	Options  []modules.Option
+	routes   *api.RouteMap
+}
+
+func (r *routeMapper) Init() {
+	r.Registry = modules.Registry{
+		Name:        "RouteMapper",
+		DocTypes:    []string{"Script", "Navigation"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/routemapper/gorpmod.go",
+		Description: "Rebuilds the client side routing table of single page applications from Vue Router, React Router and Angular route definitions, and tracks which routes were navigated to",
+		Notes:       "Routes are found heuristically and only in scripts that include a known router. Routes found so far are part of the module state, read it with Debugger.ModuleState(\"RouteMapper\")",
+	}
+
+	r.routes = api.NewRouteMap()
+	r.Options = []modules.Option{
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When a route is found, print it to console",
+		},
+	}
+}
+
+func (r *routeMapper) Inspect(webData modules.WebData) error {
+	if webData.Type == "Navigation" {
+		r.routes.Visit(webData.Url)
+		return nil
+	}
+	if webData.Type != "Script" {
+		return nil
+	}
+
+	o, err := modules.GetModuleOption(r.Options, "Print")
+	if err != nil {
+		return err
+	}
+	for _, route := range r.routes.AddScript(webData.Url, webData.Body) {
+		if o == "true" {
+			log.Println("[+] Found " + route.Framework + " route " + route.Path + " in " + webData.Url)
+		}
+		webData.Findings.Report(modules.Finding{
+			Rule:   r.Registry.Name + "/route",
+			Url:    webData.Url,
+			Detail: route.Framework + " route " + route.Path,
+		})
+	}
+	return nil
+}
+
+// MarshalState returns the routes found so far, sorted by path, and whether they were visited
+func (r *routeMapper) MarshalState() ([]byte, error) {
+	return json.Marshal(r.routes.Routes())
+}
+
+// UnmarshalState restores the routes found in a previous session
+func (r *routeMapper) UnmarshalState(data []byte) error {
+	var routes []api.Route
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return err
+	}
+	r.routes.Restore(routes)
+	return nil
+}
+
+func (r *routeMapper) GetRegistry() modules.Registry {
+	return r.Registry
+}
+
+func (r *routeMapper) GetOptions() []modules.Option {
+	return r.Options
+}
+
+var Inspector routeMapper
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"strings"
+)
+
+type vulnLibs struct {
+	Registry modules.Registry
+	Options  []modules.Option
+	db       *api.LibraryDatabase
+}
+
+func (v *vulnLibs) Init() {
+	v.Registry = modules.Registry{
+		Name:        "VulnerableLibraries",
+		DocTypes:    []string{"Script"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/inspectors/generic/vulnlibs/gorpmod.go",
+		Description: "Fingerprints the JavaScript libraries included in scripts and reports versions with known vulnerabilities, in the spirit of Retire.js",
+		Notes:       "Libraries are recognized from their license banners and file names. The built in signatures only cover a few popular libraries, more can be added with a JSON file in the format of api.LibrarySignature",
+	}
+
+	v.Options = []modules.Option{
+		{
+			Name:        "Signatures",
+			Value:       "",
+			Required:    false,
+			Description: "Path of a JSON file with signatures to use on top of the built in ones",
+		},
+		{
+			Name:        "ReportAll",
+			Value:       "false",
+			Required:    true,
+			Description: "Report every library found, not only vulnerable ones",
+		},
+		{
+			Name:        "Print",
+			Value:       "true",
+			Required:    true,
+			Description: "When a vulnerable library is found, print it to console",
+		},
+	}
+}
+
+func (v *vulnLibs) Configure(options []modules.Option) error {
+	var err error
+	path, _ := modules.GetModuleOption(options, "Signatures")
+	if path == "" {
+		v.db, err = api.NewLibraryDatabase(api.DefaultLibrarySignatures)
+	} else {
+		v.db, err = api.LoadLibraryDatabase(path)
+	}
+	return err
+}
+
+func (v *vulnLibs) Inspect(webData modules.WebData) error {
+	if webData.Type != "Script" {
+		return nil
+	}
+
+	reportAll, err := modules.GetModuleOption(v.Options, "ReportAll")
+	if err != nil {
+		return err
+	}
+	p, err := modules.GetModuleOption(v.Options, "Print")
+	if err != nil {
+		return err
+	}
+	for _, m := range v.db.Fingerprint(webData.Url, webData.Body) {
+		library := m.Library + " " + m.Version
+		if !m.Vulnerable() {
+			if reportAll == "true" {
+				webData.Findings.Report(modules.Finding{
+					Rule:   v.Registry.Name + "/library",
+					Url:    webData.Url,
+					Detail: library,
+				})
+			}
+			continue
+		}
+		for _, vuln := range m.Vulnerabilities {
+			detail := library + ": " + vuln.Summary
+			if len(vuln.Identifiers) > 0 {
+				detail += " (" + strings.Join(vuln.Identifiers, ", ") + ")"
+			}
+			if p == "true" {
+				log.Println("[+] Vulnerable library in " + webData.Url + ", " + detail)
+			}
+			webData.Findings.Report(modules.Finding{
+				Rule:   v.Registry.Name + "/vulnerable",
+				Url:    webData.Url,
+				Detail: detail,
+			})
+		}
+	}
+	return nil
+}
+
+func (v *vulnLibs) GetRegistry() modules.Registry {
+	return v.Registry
+}
+
+func (v *vulnLibs) GetOptions() []modules.Option {
+	return v.Options
+}
+
+var Inspector vulnLibs
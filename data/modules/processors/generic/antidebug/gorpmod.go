@@ -0,0 +1,94 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type antiDebug struct {
+	Registry modules.Registry
+	Options  []modules.Option
+	opts     api.AntiDebugOptions
+}
+
+func (a *antiDebug) Init() {
+	a.Registry = modules.Registry{
+		Name:        "AntiDebug",
+		DocTypes:    []string{"Document", "Script"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/processors/generic/antidebug/gorpmod.go",
+		Description: "Neutralizes debugger statements and timers used to make debugging with DevTools painful",
+		Notes:       "Only debugger statements that can safely be replaced are touched. Use Patterns for anything else",
+	}
+	a.Options = []modules.Option{
+		{
+			Name:        "Statements",
+			Value:       "true",
+			Required:    true,
+			Description: "neutralize standalone debugger statements",
+		},
+		{
+			Name:        "Timers",
+			Value:       "true",
+			Required:    true,
+			Description: "replace setInterval and setTimeout callbacks that only run debugger statements",
+		},
+		{
+			Name:        "Patterns",
+			Value:       "",
+			Required:    false,
+			Description: "additional regular expressions, one per line. Matches are removed from the body",
+		},
+	}
+}
+
+func (a *antiDebug) Configure(options []modules.Option) error {
+	opts := api.AntiDebugOptions{}
+	for _, o := range options {
+		switch o.Name {
+		case "Statements":
+			opts.Statements = o.Value == "true"
+		case "Timers":
+			opts.Timers = o.Value == "true"
+		case "Patterns":
+			for _, p := range strings.Split(o.Value, "\n") {
+				if strings.TrimSpace(p) == "" {
+					continue
+				}
+				r, err := regexp.Compile(strings.TrimSpace(p))
+				if err != nil {
+					return err
+				}
+				opts.Patterns = append(opts.Patterns, r)
+			}
+		}
+	}
+	a.opts = opts
+	return nil
+}
+
+func (a *antiDebug) Process(webData modules.WebData) (string, error) {
+	if webData.Type != "Document" && webData.Type != "Script" {
+		return webData.Body, nil
+	}
+
+	body, n := api.NeutralizeAntiDebugging(webData.Body, a.opts)
+	if n > 0 {
+		log.Println("[+] antidebug: Neutralized " + strconv.Itoa(n) + " anti-debugging construct(s) in " + webData.Url)
+	}
+	return body, nil
+}
+
+func (a *antiDebug) GetRegistry() modules.Registry {
+	return a.Registry
+}
+
+func (a *antiDebug) GetOptions() []modules.Option {
+	return a.Options
+}
+
+var Processor antiDebug
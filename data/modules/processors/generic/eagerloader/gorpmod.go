@@ -0,0 +1,99 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"strconv"
+	"strings"
+)
+
+type eagerLoader struct {
+	Registry modules.Registry
+	Options  []modules.Option
+}
+
+func (e *eagerLoader) Init() {
+	e.Registry = modules.Registry{
+		Name:        "EagerLoader",
+		DocTypes:    []string{"Document"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/processors/generic/eagerloader/gorpmod.go",
+		Description: "Removes defer and async from script tags and loading=\"lazy\" from images and iframes, so that everything loads synchronously",
+		Notes:       "Responses that are not served as text/html are left untouched. Module scripts are deferred by default and keep running after the document is parsed",
+	}
+	e.Options = []modules.Option{
+		{
+			Name:        "Scripts",
+			Value:       "true",
+			Required:    true,
+			Description: "Remove defer and async from script tags. Either true or false",
+		},
+		{
+			Name:        "Lazy",
+			Value:       "true",
+			Required:    true,
+			Description: "Remove loading=\"lazy\" from img and iframe tags. Either true or false",
+		},
+		{
+			Name:        "URL",
+			Value:       "",
+			Required:    false,
+			Description: "URL of the documents you are targeting. All documents will be processed when left empty",
+		},
+	}
+}
+
+func (e *eagerLoader) Process(webData modules.WebData) (string, error) {
+	if webData.Type != "Document" || !isHTML(webData.Headers) {
+		return webData.Body, nil
+	}
+	url, err := modules.GetModuleOption(e.Options, "URL")
+	if err != nil {
+		return webData.Body, err
+	}
+	if url != "" && !strings.Contains(webData.Url, url) {
+		return webData.Body, nil
+	}
+
+	opts := api.EagerLoadOptions{}
+	if opts.Scripts, err = boolOption(e.Options, "Scripts"); err != nil {
+		return webData.Body, err
+	}
+	if opts.Lazy, err = boolOption(e.Options, "Lazy"); err != nil {
+		return webData.Body, err
+	}
+
+	body, n := api.ForceEagerLoading(webData.Body, opts)
+	if n > 0 {
+		log.Println("[+] eagerloader: Removed " + strconv.Itoa(n) + " attributes from " + webData.Url)
+	}
+	return body, nil
+}
+
+func boolOption(options []modules.Option, name string) (bool, error) {
+	v, err := modules.GetModuleOption(options, name)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(v)
+}
+
+func isHTML(headers map[string]interface{}) bool {
+	for k, v := range headers {
+		if s, ok := v.(string); ok && strings.EqualFold(k, "Content-Type") {
+			return strings.HasPrefix(strings.ToLower(strings.TrimSpace(s)), "text/html")
+		}
+	}
+	return false
+}
+
+func (e *eagerLoader) GetRegistry() modules.Registry {
+	return e.Registry
+}
+
+func (e *eagerLoader) GetOptions() []modules.Option {
+	return e.Options
+}
+
+var Processor eagerLoader
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"strings"
+	"time"
+)
+
+type extFilter struct {
+	Registry modules.Registry
+	Options  []modules.Option
+	opts     filterOptions
+}
+
+type filterOptions struct {
+	command []string
+	types   map[string]bool
+	url     string
+	timeout time.Duration
+}
+
+func (e *extFilter) Init() {
+	e.Registry = modules.Registry{
+		Name:        "ExternalFilter",
+		DocTypes:    []string{"Document", "Script"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/processors/generic/extfilter/gorpmod.go",
+		Description: "Pipes response bodies through an external program, such as a deobfuscator or a beautifier, and serves what it writes to its standard output",
+		Notes:       "The command is run directly, not through a shell, so pipes and redirections are not available. Responses are left untouched when the command fails or times out",
+	}
+	e.Options = []modules.Option{
+		{
+			Name:        "Command",
+			Value:       "",
+			Required:    true,
+			Description: "Command to run for every response, with its arguments. Quotes group words as in a shell",
+		},
+		{
+			Name:        "Types",
+			Value:       "Script",
+			Required:    true,
+			Description: "Comma separated types of the responses to filter, Document, Script or both",
+		},
+		{
+			Name:        "URL",
+			Value:       "",
+			Required:    false,
+			Description: "URL of the responses you are targeting. All responses will be filtered when left empty",
+		},
+		{
+			Name:        "Timeout",
+			Value:       api.DefaultFilterTimeout.String(),
+			Required:    true,
+			Description: "How long the command may run on a single response, such as 10s",
+		},
+	}
+}
+
+func (e *extFilter) Process(webData modules.WebData) (string, error) {
+	if !e.opts.types[webData.Type] || (e.opts.url != "" && !strings.Contains(webData.Url, e.opts.url)) {
+		return webData.Body, nil
+	}
+
+	body, err := api.RunFilter(e.opts.command[0], e.opts.command[1:], webData.Body, e.opts.timeout)
+	if err != nil {
+		log.Println("[-] extfilter: Leaving " + webData.Url + " untouched, " + err.Error())
+		return webData.Body, nil
+	}
+	return body, nil
+}
+
+func (e *extFilter) Configure(options []modules.Option) error {
+	opts := filterOptions{types: make(map[string]bool)}
+	command, err := modules.GetModuleOption(options, "Command")
+	if err != nil {
+		return err
+	}
+	if opts.command, err = api.SplitCommand(command); err != nil {
+		return err
+	}
+	if len(opts.command) == 0 {
+		return errors.New("no command to filter responses through")
+	}
+	types, err := modules.GetModuleOption(options, "Types")
+	if err != nil {
+		return err
+	}
+	for _, t := range strings.Split(types, ",") {
+		opts.types[strings.TrimSpace(t)] = true
+	}
+	if opts.url, err = modules.GetModuleOption(options, "URL"); err != nil {
+		return err
+	}
+	timeout, err := modules.GetModuleOption(options, "Timeout")
+	if err != nil {
+		return err
+	}
+	if opts.timeout, err = time.ParseDuration(timeout); err != nil {
+		return err
+	}
+	e.opts = opts
+	return nil
+}
+
+func (e *extFilter) GetRegistry() modules.Registry {
+	return e.Registry
+}
+
+func (e *extFilter) GetOptions() []modules.Option {
+	return e.Options
+}
+
+var Processor extFilter
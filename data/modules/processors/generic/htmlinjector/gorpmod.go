@@ -0,0 +1,103 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"strings"
+)
+
+type htmlInjector struct {
+	Registry modules.Registry
+	Options  []modules.Option
+}
+
+func (h *htmlInjector) Init() {
+	h.Registry = modules.Registry{
+		Name:        "HTMLInjector",
+		DocTypes:    []string{"Document"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/processors/generic/htmlinjector/gorpmod.go",
+		Description: "Injects CSS and JS snippets into the head or body of HTML documents",
+		Notes:       "Responses that are not served as text/html are left untouched",
+	}
+	h.Options = []modules.Option{
+		{
+			Name:        "CSS",
+			Value:       "",
+			Required:    false,
+			Description: "CSS to inject in a style element",
+		},
+		{
+			Name:        "JS",
+			Value:       "",
+			Required:    false,
+			Description: "JS to inject in a script element",
+		},
+		{
+			Name:        "Position",
+			Value:       "head",
+			Required:    true,
+			Description: "Inject right before the end of the head or body element. Either head or body",
+		},
+		{
+			Name:        "URL",
+			Value:       "",
+			Required:    false,
+			Description: "URL of the documents you are targeting. All documents will be processed when left empty",
+		},
+	}
+}
+
+func (h *htmlInjector) Process(webData modules.WebData) (string, error) {
+	if webData.Type != "Document" || !isHTML(webData.Headers) {
+		return webData.Body, nil
+	}
+	url, err := modules.GetModuleOption(h.Options, "URL")
+	if err != nil {
+		return webData.Body, err
+	}
+	if url != "" && !strings.Contains(webData.Url, url) {
+		return webData.Body, nil
+	}
+
+	snippet := ""
+	if css, _ := modules.GetModuleOption(h.Options, "CSS"); css != "" {
+		snippet += "<style>" + css + "</style>"
+	}
+	if js, _ := modules.GetModuleOption(h.Options, "JS"); js != "" {
+		snippet += "<script>" + js + "</script>"
+	}
+	if snippet == "" {
+		return webData.Body, nil
+	}
+
+	position, err := modules.GetModuleOption(h.Options, "Position")
+	if err != nil {
+		return webData.Body, err
+	}
+	body, ok := api.InjectHTML(webData.Body, snippet, position)
+	if ok {
+		log.Println("[+] htmlinjector: Injected snippets into " + webData.Url)
+	}
+	return body, nil
+}
+
+func isHTML(headers map[string]interface{}) bool {
+	for k, v := range headers {
+		if s, ok := v.(string); ok && strings.EqualFold(k, "Content-Type") {
+			return strings.HasPrefix(strings.ToLower(strings.TrimSpace(s)), "text/html")
+		}
+	}
+	return false
+}
+
+func (h *htmlInjector) GetRegistry() modules.Registry {
+	return h.Registry
+}
+
+func (h *htmlInjector) GetOptions() []modules.Option {
+	return h.Options
+}
+
+var Processor htmlInjector
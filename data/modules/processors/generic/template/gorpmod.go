@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"io/ioutil"
+	"strings"
+	"text/template"
+)
+
+type responseTemplate struct {
+	Registry modules.Registry
+	Options  []modules.Option
+
+	tmpl  *template.Template
+	types map[string]bool
+	url   string
+}
+
+func (r *responseTemplate) Init() {
+	r.Registry = modules.Registry{
+		Name:        "ResponseTemplate",
+		DocTypes:    []string{"Document", "Script", "XHR"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/processors/generic/template/gorpmod.go",
+		Description: "Serves response bodies rendered from a Go text/template, which can use the method, url, query parameters and headers of the request",
+		Notes:       "Values are inserted as they are, pipe them to json, html or js to escape them. {{.Body}} is the original body",
+	}
+	r.Options = []modules.Option{
+		{
+			Name:        "Template",
+			Value:       "",
+			Required:    false,
+			Description: "Template of the response body, such as {\"user\": {{.Param \"user\" | json}}}",
+		},
+		{
+			Name:        "File",
+			Value:       "",
+			Required:    false,
+			Description: "Path to a file holding the template, used when Template is empty",
+		},
+		{
+			Name:        "Types",
+			Value:       "XHR",
+			Required:    true,
+			Description: "Comma separated types of the responses to replace, such as Document, Script or XHR",
+		},
+		{
+			Name:        "URL",
+			Value:       "",
+			Required:    false,
+			Description: "URL of the responses you are targeting. All responses will be replaced when left empty",
+		},
+	}
+}
+
+func (r *responseTemplate) Configure(options []modules.Option) error {
+	text, err := modules.GetModuleOption(options, "Template")
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		path, err := modules.GetModuleOption(options, "File")
+		if err != nil {
+			return err
+		}
+		if path == "" {
+			return errors.New("either Template or File is required")
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		text = string(b)
+	}
+	if r.tmpl, err = api.ParseTemplate(text); err != nil {
+		return err
+	}
+
+	types, err := modules.GetModuleOption(options, "Types")
+	if err != nil {
+		return err
+	}
+	r.types = make(map[string]bool)
+	for _, t := range strings.Split(types, ",") {
+		r.types[strings.TrimSpace(t)] = true
+	}
+	r.url, err = modules.GetModuleOption(options, "URL")
+	return err
+}
+
+func (r *responseTemplate) Process(webData modules.WebData) (string, error) {
+	if !r.types[webData.Type] || (r.url != "" && !strings.Contains(webData.Url, r.url)) {
+		return webData.Body, nil
+	}
+	ctx := api.NewTemplateContext(webData.Method, webData.Url, webData.RequestHeaders, webData.Body)
+	return api.RenderTemplate(r.tmpl, ctx)
+}
+
+func (r *responseTemplate) GetRegistry() modules.Registry {
+	return r.Registry
+}
+
+func (r *responseTemplate) GetOptions() []modules.Option {
+	return r.Options
+}
+
+var Processor responseTemplate
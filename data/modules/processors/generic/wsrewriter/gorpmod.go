@@ -0,0 +1,79 @@
+package main
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"strconv"
+	"strings"
+)
+
+type wsRewriter struct {
+	Registry modules.Registry
+	Options  []modules.Option
+}
+
+func (w *wsRewriter) Init() {
+	w.Registry = modules.Registry{
+		Name:        "WSRewriter",
+		DocTypes:    []string{"Script", "Document"},
+		Author:      []string{"codedharma", "hex0punk"},
+		Path:        "./data/modules/processors/generic/wsrewriter/gorpmod.go",
+		Description: "Rewrites WebSocket urls found in scripts and documents, to send the socket to a server of your own",
+		Notes:       "Only urls written out in string literals are rewritten, urls pieced together at runtime are left alone",
+	}
+	w.Options = []modules.Option{
+		{
+			Name:        "From",
+			Value:       "",
+			Required:    true,
+			Description: "WebSocket url prefix to replace, such as wss://prod.example.com",
+		},
+		{
+			Name:        "To",
+			Value:       "ws://localhost:8080",
+			Required:    true,
+			Description: "Url prefix to replace it with",
+		},
+		{
+			Name:        "URL",
+			Value:       "",
+			Required:    false,
+			Description: "URL of the scripts or documents you are targeting. All of them will be processed when left empty",
+		},
+	}
+}
+
+func (w *wsRewriter) Process(webData modules.WebData) (string, error) {
+	url, err := modules.GetModuleOption(w.Options, "URL")
+	if err != nil {
+		return webData.Body, err
+	}
+	if url != "" && !strings.Contains(webData.Url, url) {
+		return webData.Body, nil
+	}
+	from, err := modules.GetModuleOption(w.Options, "From")
+	if err != nil || from == "" {
+		return webData.Body, err
+	}
+	to, err := modules.GetModuleOption(w.Options, "To")
+	if err != nil {
+		return webData.Body, err
+	}
+
+	body, n := api.RewriteWebSocketUrls(webData.Body, from, to)
+	if n > 0 {
+		log.Println("[+] wsrewriter: Rewrote " + strconv.Itoa(n) + " WebSocket urls in " + webData.Url)
+	}
+	return body, nil
+}
+
+func (w *wsRewriter) GetRegistry() modules.Registry {
+	return w.Registry
+}
+
+func (w *wsRewriter) GetOptions() []modules.Option {
+	return w.Options
+}
+
+var Processor wsRewriter
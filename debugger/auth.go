@@ -0,0 +1,111 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd/gcdapi"
+	"net/http"
+)
+
+// Credentials are provided when a server or proxy whose url matches Url asks for HTTP authentication. Chrome
+// answers the challenge itself, so every scheme it supports works, Basic and Digest included
+type Credentials struct {
+	Url      string // Url pattern using Chrome interception wildcards, matched against the url of the request
+	Username string
+	Password string
+}
+
+// answerAuthChallenge passes an authentication challenge to inspectors and answers it. Chrome retries the
+// request with the configured credentials matching its url, if any. Challenges the credentials did not get
+// past, and those without credentials, are left to the browser, which prompts for them
+func (d *Debugger) answerAuthChallenge(msg *gcdapi.NetworkRequestInterceptedEvent) {
+	iid := msg.Params.InterceptionId
+	url := msg.Params.Request.Url
+	c := msg.Params.AuthChallenge
+	challenge := &modules.AuthChallenge{
+		Scheme: c.Scheme,
+		Realm:  c.Realm,
+		Origin: c.Origin,
+		Proxy:  c.Source == "Proxy",
+	}
+	if parsed := modules.ParseAuthenticate(authenticateHeader(msg.Params.ResponseHeaders, challenge.Proxy)); parsed != nil {
+		challenge.Scheme = parsed.Scheme
+	}
+	d.logAs(msgBlocked, "[+] "+challenge.Scheme+" authentication challenge for realm \""+c.Realm+"\" from "+c.Origin, nil)
+
+	go d.CallInspectors(modules.WebData{
+		Headers:        msg.Params.ResponseHeaders,
+		RequestHeaders: msg.Params.Request.Headers,
+		Type:           "AuthChallenge",
+		Url:            url,
+		Method:         msg.Params.Request.Method,
+		RequestId:      msg.Params.RequestId,
+		FrameId:        msg.Params.FrameId,
+		Challenge:      challenge,
+		Findings:       d.Findings,
+	})
+
+	response := &gcdapi.NetworkAuthChallengeResponse{Response: "Default"}
+	if creds := d.credentialsFor(url); creds != nil && d.firstChallenge(msg.Params.RequestId) {
+		d.log("[+] Providing credentials of "+creds.Username+" for "+url, nil)
+		response = &gcdapi.NetworkAuthChallengeResponse{
+			Response: "ProvideCredentials",
+			Username: creds.Username,
+			Password: creds.Password,
+		}
+	}
+	d.continueAuthChallenge(iid, response)
+}
+
+// credentialsFor returns the first credentials whose url pattern matches url, nil if there are none
+func (d *Debugger) credentialsFor(url string) *Credentials {
+	for i, c := range d.Options.Credentials {
+		if wildcardRegexp(c.Url).MatchString(url) {
+			return &d.Options.Credentials[i]
+		}
+	}
+	return nil
+}
+
+// maxChallenged bounds the number of requests remembered by firstChallenge, like maxInitiators
+const maxChallenged = 5000
+
+// firstChallenge reports whether this is the first challenge for the request, so that credentials that were
+// turned down are not sent over and over again
+func (d *Debugger) firstChallenge(requestId string) bool {
+	d.challengesLock.Lock()
+	defer d.challengesLock.Unlock()
+	if d.challenged == nil {
+		d.challenged = make(map[string]bool)
+	}
+	if d.challenged[requestId] {
+		return false
+	}
+	d.challenged[requestId] = true
+	d.challengeOrder = append(d.challengeOrder, requestId)
+	for len(d.challengeOrder) > maxChallenged {
+		delete(d.challenged, d.challengeOrder[0])
+		d.challengeOrder = d.challengeOrder[1:]
+	}
+	return true
+}
+
+// authenticateHeader returns the WWW-Authenticate header of a response, or Proxy-Authenticate for proxies
+func authenticateHeader(headers map[string]interface{}, proxy bool) string {
+	if proxy {
+		return headerValue(headers, "proxy-authenticate")
+	}
+	return headerValue(headers, "www-authenticate")
+}
+
+// responseChallenge parses the authentication challenge of 401 and 407 responses, nil for any other response
+func responseChallenge(status int, headers map[string]interface{}) *modules.AuthChallenge {
+	if status != http.StatusUnauthorized && status != http.StatusProxyAuthRequired {
+		return nil
+	}
+	proxy := status == http.StatusProxyAuthRequired
+	c := modules.ParseAuthenticate(authenticateHeader(headers, proxy))
+	if c != nil {
+		c.Proxy = proxy
+	}
+	return c
+}
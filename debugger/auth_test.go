@@ -0,0 +1,107 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func authChallenge(t *testing.T, iid string, requestId string, url string) *gcdapi.NetworkRequestInterceptedEvent {
+	return interceptedEvent(t, `{"interceptionId":"`+iid+`","requestId":"`+requestId+`","resourceType":"Document",
+		"request":{"url":"`+url+`","method":"GET"},
+		"authChallenge":{"source":"Server","origin":"https://example.com","scheme":"basic","realm":"Admin area"},
+		"responseStatusCode":401,"responseHeaders":{"WWW-Authenticate":"Basic realm=\"Admin area\""}}`)
+}
+
+// challengeSpy records the web data passed to inspectors
+type challengeSpy struct {
+	mu   sync.Mutex
+	seen []modules.WebData
+	wg   sync.WaitGroup
+}
+
+func (s *challengeSpy) module() modules.InspectorModule {
+	return modules.InspectorModule{
+		Registry: modules.Registry{Name: "spy"},
+		Inspect: func(webData modules.WebData) error {
+			defer s.wg.Done()
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.seen = append(s.seen, webData)
+			return nil
+		},
+	}
+}
+
+func TestAuthChallengeCredentials(t *testing.T) {
+	spy := &challengeSpy{}
+	net := &mockNetwork{}
+	d := Debugger{
+		net:     net,
+		Options: Options{Credentials: []Credentials{{Url: "https://example.com/admin*", Username: "admin", Password: "hunter2"}}},
+		Modules: modules.Modules{Inspectors: []modules.InspectorModule{spy.module()}},
+	}
+
+	spy.wg.Add(2)
+	d.handleInterception(authChallenge(t, "1", "r1", "https://example.com/admin/"))
+	// the credentials were turned down, the browser gets to prompt for others
+	d.handleInterception(authChallenge(t, "2", "r1", "https://example.com/admin/"))
+	spy.wg.Wait()
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 2)
+	assert.Equal(t, calls[0].Auth, &gcdapi.NetworkAuthChallengeResponse{
+		Response: "ProvideCredentials",
+		Username: "admin",
+		Password: "hunter2",
+	})
+	assert.Equal(t, calls[0].RawResponse, "")
+	assert.Equal(t, calls[1].Auth, &gcdapi.NetworkAuthChallengeResponse{Response: "Default"})
+
+	assert.Equal(t, spy.seen[0].Type, "AuthChallenge")
+	assert.Equal(t, spy.seen[0].Challenge, &modules.AuthChallenge{Scheme: "basic", Realm: "Admin area", Origin: "https://example.com"})
+}
+
+func TestAuthChallengeWithoutCredentials(t *testing.T) {
+	net := &mockNetwork{}
+	d := Debugger{
+		net:     net,
+		Options: Options{Credentials: []Credentials{{Url: "https://other.example.com/*", Username: "admin"}}},
+	}
+
+	d.handleInterception(authChallenge(t, "1", "r1", "https://example.com/admin/"))
+	assert.Equal(t, net.calls()[0].Auth, &gcdapi.NetworkAuthChallengeResponse{Response: "Default"})
+}
+
+func TestUnauthorizedResponseChallenge(t *testing.T) {
+	spy := &challengeSpy{}
+	net := &mockNetwork{bodies: map[string]string{"1": `{"error":"unauthorized"}`}}
+	d := Debugger{net: net, Modules: modules.Modules{Inspectors: []modules.InspectorModule{spy.module()}}}
+
+	spy.wg.Add(1)
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/me","method":"GET"},
+		"responseStatusCode":401,"responseHeaders":{"WWW-Authenticate":"Bearer realm=\"api\", error=\"invalid_token\""}}`))
+	spy.wg.Wait()
+	assert.Equal(t, spy.seen[0].Challenge, &modules.AuthChallenge{Scheme: "bearer", Realm: "api"})
+}
+
+func TestValidateCredentials(t *testing.T) {
+	err := Options{Credentials: []Credentials{{Url: "*", Username: "admin"}, {Url: "https://example.com/*"}}}.Validate()
+	assert.Equal(t, err.Error(), "invalid options: credentials 1 must be limited to a url pattern; credentials 2 have no username")
+}
+
+func TestChallengedRequestsPruned(t *testing.T) {
+	d := Debugger{}
+	assert.Equal(t, d.firstChallenge("r0"), true)
+	assert.Equal(t, d.firstChallenge("r0"), false)
+	for i := 1; i <= maxChallenged; i++ {
+		d.firstChallenge("r" + strconv.Itoa(i))
+	}
+	assert.Equal(t, len(d.challenged), maxChallenged)
+	// the oldest request was forgotten
+	assert.Equal(t, d.firstChallenge("r0"), true)
+}
@@ -0,0 +1,137 @@
+package debugger
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd/gcdapi"
+	"log"
+	"mime"
+	"strings"
+)
+
+// binaryTypes are content types that hold binary data, matched by prefix
+var binaryTypes = []string{
+	"image/",
+	"audio/",
+	"video/",
+	"font/",
+	"application/wasm",
+	"application/octet-stream",
+	"application/font-",
+	"application/pdf",
+	"application/zip",
+	"application/x-protobuf",
+	"application/protobuf",
+	"application/x-protobuffer",
+	"application/vnd.google.protobuf",
+	"application/grpc-web",
+}
+
+// isBinaryContent reports whether a response holds binary data, based on its resource type and content type
+func isBinaryContent(resourceType string, headers map[string]interface{}) bool {
+	contentType, _, _ := mime.ParseMediaType(headerValue(headers, "content-type"))
+	if contentType == "image/svg+xml" {
+		return false
+	}
+	for _, t := range binaryTypes {
+		if strings.HasPrefix(contentType, t) {
+			return true
+		}
+	}
+	switch resourceType {
+	case "Image", "Media", "Font":
+		return contentType == ""
+	}
+	return false
+}
+
+// interceptBinary passes a binary response to inspectors and binary processors. Bodies are kept as raw bytes
+// from the moment they are decoded, and the original response is forwarded untouched unless a processor changed it.
+func (d *Debugger) interceptBinary(msg *gcdapi.NetworkRequestInterceptedEvent, res string, encoded bool) {
+	iid := msg.Params.InterceptionId
+	reason := msg.Params.ResponseErrorReason
+	raw := d.rawCapture(msg, res, encoded)
+	body := []byte(res)
+	if encoded {
+		var err error
+		body, err = base64.StdEncoding.DecodeString(res)
+		if err != nil {
+			d.log("[-] Unable to decode binary body for "+msg.Params.Request.Url, err)
+			d.continueRequest(iid, reason, "", "", "")
+			return
+		}
+	}
+
+	conn := d.connectionFor(msg.Params.RequestId, msg.Params.Request.Url)
+	webData := modules.WebData{
+		Body:            string(body),
+		Headers:         msg.Params.ResponseHeaders,
+		RequestHeaders:  msg.Params.Request.Headers,
+		Type:            responseType(msg.Params.ResourceType, msg.Params.ResponseHeaders),
+		Url:             msg.Params.Request.Url,
+		Method:          msg.Params.Request.Method,
+		Status:          msg.Params.ResponseStatusCode,
+		Protocol:        conn.protocol,
+		TLSVersion:      conn.tlsVersion,
+		RequestId:       msg.Params.RequestId,
+		FrameId:         msg.Params.FrameId,
+		WorkerScript:    d.isWorkerScript(msg),
+		Initiator:       d.initiatorFor(msg.Params.RequestId),
+		Redirects:       d.redirectsFor(msg.Params.RequestId),
+		ResponseCookies: modules.ParseResponseCookies(msg.Params.ResponseHeaders),
+		Raw:             raw,
+		Findings:        d.Findings,
+	}
+	if contentType := headerValue(msg.Params.ResponseHeaders, "content-type"); modules.IsProtobuf(contentType) {
+		webData.Protobuf = d.decodeProtobuf(webData.Url, contentType, body)
+	}
+	go d.CallInspectors(webData)
+
+	altered, err := d.processBinary(webData, body)
+	if err != nil {
+		log.Println("[-] Unable to alter binary body")
+	}
+	if err != nil || (bytes.Equal(altered, body) && d.contentTypeFor(webData, webData.Body) == "") {
+		d.pinSnapshot(webData, "")
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
+
+	response := d.buildResponse(webData, string(altered))
+	d.pinSnapshot(webData, response)
+	d.continueRequest(iid, reason, response, "", "")
+}
+
+// decodeProtobuf decodes the protobuf messages of a body, logging the body as undecodable rather than failing
+func (d *Debugger) decodeProtobuf(url, contentType string, body []byte) []modules.ProtoMessage {
+	messages, err := modules.DecodeProtobufBody(contentType, body)
+	if err != nil {
+		d.log("[-] Unable to decode protobuf body for "+url, err)
+		return nil
+	}
+	return messages
+}
+
+// processBinary runs the binary processors on body, one after the other
+func (d *Debugger) processBinary(data modules.WebData, body []byte) ([]byte, error) {
+	result := data
+	result.Body = string(body)
+	var err error
+	for _, v := range d.Modules.Processors {
+		if v.ProcessBinary == nil {
+			continue
+		}
+		// the processor runs like any other, for timeouts and the change log, but only ever sees bytes
+		p := v
+		p.Process = func(webData modules.WebData) (string, error) {
+			b, err := v.ProcessBinary(webData, []byte(webData.Body))
+			return string(b), err
+		}
+		result.Body, err = d.runProcessor(p, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return []byte(result.Body), nil
+}
@@ -0,0 +1,148 @@
+package debugger
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func encodePNG(t *testing.T, c color.Color) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for x := 0; x < 2; x++ {
+		for y := 0; y < 2; y++ {
+			img.Set(x, y, c)
+		}
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestBinaryProcessor(t *testing.T) {
+	original := encodePNG(t, color.White)
+	replacement := encodePNG(t, color.RGBA{R: 255, A: 255})
+
+	var received []byte
+	var textCalls int
+	net := &mockNetwork{bodies: map[string]string{"1": string(original)}, encoded: true}
+	d := Debugger{
+		net: net,
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{
+			{
+				Registry: modules.Registry{Name: "Text"},
+				Process: func(webData modules.WebData) (string, error) {
+					textCalls++
+					return webData.Body + "<!-- gorp -->", nil
+				},
+			},
+			{
+				Registry: modules.Registry{Name: "ImageSwap", DocTypes: []string{"Image"}},
+				ProcessBinary: func(webData modules.WebData, body []byte) ([]byte, error) {
+					received = body
+					return replacement, nil
+				},
+			},
+		}},
+	}
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"Image",
+		"request":{"url":"http://example.com/logo.png","method":"GET"},"responseStatusCode":200,
+		"responseHeaders":{"Content-Type":"image/png","Content-Length":"`+strconv.Itoa(len(original))+`"}}`))
+
+	assert.Equal(t, textCalls, 0)
+	assert.Equal(t, received, original)
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	sent := decodeRaw(t, calls[0].RawResponse)
+	parts := strings.SplitN(sent, "\r\n\r\n", 2)
+	assert.Equal(t, strings.Contains(parts[0], "Content-Length: "+strconv.Itoa(len(replacement))), true)
+	assert.Equal(t, []byte(parts[1]), replacement)
+	_, err := png.Decode(bytes.NewReader([]byte(parts[1])))
+	assert.Equal(t, err, nil)
+}
+
+func TestIsBinaryContent(t *testing.T) {
+	assert.Equal(t, isBinaryContent("Fetch", map[string]interface{}{"content-type": "application/wasm"}), true)
+	assert.Equal(t, isBinaryContent("Image", map[string]interface{}{"Content-Type": "image/svg+xml"}), false)
+	assert.Equal(t, isBinaryContent("Image", nil), true)
+	assert.Equal(t, isBinaryContent("Script", map[string]interface{}{"Content-Type": "application/javascript"}), false)
+	assert.Equal(t, isBinaryContent("Fetch", map[string]interface{}{"Content-Type": "application/x-protobuf"}), true)
+}
+
+func TestProtobufResponse(t *testing.T) {
+	// a gRPC-Web frame holding {1: "testing", 2: 150}
+	message := []byte{0x0a, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g', 0x10, 0x96, 0x01}
+	body := append([]byte{0x00, 0x00, 0x00, 0x00, byte(len(message))}, message...)
+
+	received := make(chan modules.WebData, 1)
+	net := &mockNetwork{bodies: map[string]string{"1": string(body)}, encoded: true}
+	d := Debugger{
+		net: net,
+		Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+			Registry: modules.Registry{Name: "Protobuf"},
+			Inspect: func(webData modules.WebData) error {
+				received <- webData
+				return nil
+			},
+		}}},
+	}
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"Fetch",
+		"request":{"url":"https://example.com/search.Search/Query","method":"POST"},"responseStatusCode":200,
+		"responseHeaders":{"Content-Type":"application/grpc-web+proto"}}`))
+
+	webData := <-received
+	assert.Equal(t, len(webData.Protobuf), 1)
+	assert.Equal(t, webData.Protobuf[0].String(), "1: \"testing\"\n2: 150\n")
+	assert.Equal(t, []byte(webData.Body), body)
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	assert.Equal(t, calls[0].RawResponse, "")
+}
+
+// protobufRequest returns a request intercepted before it was sent, whose post data is body as Chrome passes it
+func protobufRequest(t *testing.T, iid string, body []byte) *gcdapi.NetworkRequestInterceptedEvent {
+	postData, err := json.Marshal(string(body))
+	assert.Equal(t, err, nil)
+	return interceptedEvent(t, `{"interceptionId":"`+iid+`","resourceType":"XHR",
+		"request":{"url":"https://example.com/search","method":"POST","postData":`+string(postData)+`,
+			"headers":{"Content-Type":"application/x-protobuf"}}}`)
+}
+
+func TestProtobufRequest(t *testing.T) {
+	received := make(chan modules.WebData, 2)
+	d := Debugger{
+		net:     &mockNetwork{},
+		Options: Options{InterceptRequests: true},
+		Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+			Registry: modules.Registry{Name: "Protobuf"},
+			Inspect: func(webData modules.WebData) error {
+				received <- webData
+				return nil
+			},
+		}}},
+	}
+
+	// {1: "testing", 2: 5} is valid UTF-8 and makes it through untouched
+	d.handleInterception(protobufRequest(t, "1", []byte{0x0a, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g', 0x10, 0x05}))
+	webData := <-received
+	assert.Equal(t, len(webData.Protobuf), 1)
+	assert.Equal(t, webData.Protobuf[0].String(), "1: \"testing\"\n2: 5\n")
+
+	// the varint 150 is not, and Chrome replaces its bytes
+	d.handleInterception(protobufRequest(t, "2", []byte{0x0a, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g', 0x10, 0x96, 0x01}))
+	webData = <-received
+	assert.Equal(t, len(webData.Protobuf), 0)
+}
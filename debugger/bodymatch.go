@@ -0,0 +1,56 @@
+package debugger
+
+import (
+	"regexp"
+)
+
+// BodyMatchRule restricts a chain of processors to responses whose body matches a pattern, so that processors
+// can be routed by content rather than by url or type
+type BodyMatchRule struct {
+	Match      string   // Regular expression the response body must match
+	Processors []string // Names of the processors only run on matching bodies, in the order they are registered
+}
+
+// compiledBodyRule is a body match rule whose pattern was compiled
+type compiledBodyRule struct {
+	match      *regexp.Regexp
+	processors []string
+}
+
+// compiledBodyRules compiles the body match rules the first time they are needed, leaving out invalid ones, so
+// that their patterns are not compiled again for every response
+func (d *Debugger) compiledBodyRules() []compiledBodyRule {
+	d.bodyRulesOnce.Do(func() {
+		for _, r := range d.Options.BodyMatchRules {
+			p, err := regexp.Compile(r.Match)
+			if err != nil {
+				d.log("[-] Invalid body match rule "+r.Match, err)
+				continue
+			}
+			d.bodyRules = append(d.bodyRules, compiledBodyRule{match: p, processors: r.Processors})
+		}
+	})
+	return d.bodyRules
+}
+
+// bodyGates returns, for every processor named by a body match rule, whether a rule naming it matches body.
+// Processors named by several rules run when any of them matches
+func (d *Debugger) bodyGates(body string) map[string]bool {
+	if len(d.Options.BodyMatchRules) == 0 {
+		return nil
+	}
+	gates := make(map[string]bool)
+	for _, r := range d.compiledBodyRules() {
+		matched := r.match.MatchString(body)
+		for _, name := range r.processors {
+			gates[name] = gates[name] || matched
+		}
+	}
+	return gates
+}
+
+// gateAllows reports whether the body gates let the named processor run
+func gateAllows(gates map[string]bool, processor string) bool {
+	allowed, gated := gates[processor]
+	return !gated || allowed
+}
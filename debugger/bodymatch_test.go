@@ -0,0 +1,60 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestBodyMatchRules(t *testing.T) {
+	var processed []string
+	d := Debugger{
+		Options: Options{BodyMatchRules: []BodyMatchRule{
+			{Match: `__APP_CONFIG__`, Processors: []string{"config", "unpack"}},
+			{Match: `eval\(function\(p,a,c,k,e`, Processors: []string{"unpack"}},
+		}},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{
+			countingProcessor("config", &processed),
+			countingProcessor("unpack", &processed),
+			countingProcessor("always", &processed),
+		}},
+	}
+
+	body, err := d.processBody(modules.WebData{Body: "window.__APP_CONFIG__ = {};"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, "window.__APP_CONFIG__ = {};/*config*//*unpack*//*always*/")
+
+	body, _ = d.processBody(modules.WebData{Body: "eval(function(p,a,c,k,e,d){})"})
+	assert.Equal(t, body, "eval(function(p,a,c,k,e,d){})/*unpack*//*always*/")
+
+	body, _ = d.processBody(modules.WebData{Body: "console.log(1)"})
+	assert.Equal(t, body, "console.log(1)/*always*/")
+}
+
+func TestBodyMatchRulesSeeOriginalBody(t *testing.T) {
+	var processed []string
+	d := Debugger{
+		Options: Options{BodyMatchRules: []BodyMatchRule{
+			{Match: `/\*first\*/`, Processors: []string{"second"}},
+		}},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{
+			countingProcessor("first", &processed),
+			countingProcessor("second", &processed),
+		}},
+	}
+
+	// the marker added by the first processor does not trigger the second one
+	body, _ := d.processBody(modules.WebData{Body: "x"})
+	assert.Equal(t, body, "x/*first*/")
+}
+
+func TestBodyMatchRulesCompiledOnce(t *testing.T) {
+	d := Debugger{Options: Options{BodyMatchRules: []BodyMatchRule{
+		{Match: `(`, Processors: []string{"broken"}},
+		{Match: `config`, Processors: []string{"config"}},
+	}}}
+	rules := d.compiledBodyRules()
+	assert.Equal(t, len(rules), 1)
+	assert.Equal(t, d.compiledBodyRules()[0].match == rules[0].match, true)
+	assert.Equal(t, d.bodyGates("config"), map[string]bool{"config": true})
+}
@@ -0,0 +1,157 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"github.com/wirepair/gcd/gcdmessage"
+	"log"
+)
+
+// maxScopeVariables bounds the number of variables captured for a single scope
+const maxScopeVariables = 100
+
+// breakpointDomain is the subset of the Chrome Dev Tools Debugger domain used to set breakpoints.
+// It is implemented by gcdapi.Debugger.
+type breakpointDomain interface {
+	SetBreakpointByUrlWithParams(v *gcdapi.DebuggerSetBreakpointByUrlParams) (string, []*gcdapi.DebuggerLocation, error)
+	ResumeWithParams(v *gcdapi.DebuggerResumeParams) (*gcdmessage.ChromeResponse, error)
+}
+
+// SetBreakpointByURL sets a breakpoint on a 1-based line of every script loaded from url, including scripts
+// loaded later on. When it is hit, the call frames and the variables in their scopes are passed to inspectors
+// as web data of type "Paused", whose Paused field holds the captured state and body its JSON encoding, and
+// the page is resumed. Pauses caused by anything else, such as XHR breakpoints, are left to the user.
+// It returns the id of the breakpoint and an error
+func (d *Debugger) SetBreakpointByURL(url string, line int) (string, error) {
+	if line < 1 {
+		return "", fmt.Errorf("invalid line %d, lines start at 1", line)
+	}
+	if d.Target != nil {
+		d.pausedOnce.Do(d.trackPauses)
+	}
+	id, _, err := d.breakpoints().SetBreakpointByUrlWithParams(&gcdapi.DebuggerSetBreakpointByUrlParams{
+		Url:        url,
+		LineNumber: line - 1,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to set breakpoint: %s", err)
+	}
+	d.breakpointsLock.Lock()
+	defer d.breakpointsLock.Unlock()
+	if d.breakpointIds == nil {
+		d.breakpointIds = make(map[string]bool)
+	}
+	d.breakpointIds[id] = true
+	return id, nil
+}
+
+// trackPauses passes the pauses of the page to handlePaused
+func (d *Debugger) trackPauses() {
+	d.Target.Subscribe("Debugger.paused", func(target *gcd.ChromeTarget, v []byte) {
+		msg := &gcdapi.DebuggerPausedEvent{}
+		err := json.Unmarshal(v, msg)
+		if err != nil {
+			log.Println("[-] Unable to read paused event", err)
+			return
+		}
+		d.handlePaused(msg)
+	})
+}
+
+// handlePaused captures the state of the page when it pauses on a breakpoint set with SetBreakpointByURL,
+// passes it to inspectors and resumes the page
+func (d *Debugger) handlePaused(msg *gcdapi.DebuggerPausedEvent) {
+	if !d.ownsBreakpoint(msg.Params.HitBreakpoints) {
+		return
+	}
+	state := &modules.PausedState{Reason: msg.Params.Reason, Breakpoints: msg.Params.HitBreakpoints}
+	for _, f := range msg.Params.CallFrames {
+		state.CallFrames = append(state.CallFrames, d.captureFrame(f))
+	}
+
+	webData := modules.WebData{Type: "Paused", Paused: state, Findings: d.Findings}
+	if len(state.CallFrames) > 0 {
+		webData.Url = state.CallFrames[0].Url
+	}
+	if b, err := json.Marshal(state); err == nil {
+		webData.Body = string(b)
+	}
+	d.log(fmt.Sprintf("[+] Breakpoint hit in %s, %d frames captured", webData.Url, len(state.CallFrames)), nil)
+	d.CallInspectors(webData)
+
+	if _, err := d.breakpoints().ResumeWithParams(&gcdapi.DebuggerResumeParams{}); err != nil {
+		d.log("[-] Unable to resume after breakpoint", err)
+	}
+}
+
+func (d *Debugger) ownsBreakpoint(hit []string) bool {
+	d.breakpointsLock.RLock()
+	defer d.breakpointsLock.RUnlock()
+	for _, id := range hit {
+		if d.breakpointIds[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// captureFrame reads the variables of every scope of a call frame but the global one, which holds the
+// whole window object
+func (d *Debugger) captureFrame(f *gcdapi.DebuggerCallFrame) modules.CallFrame {
+	frame := modules.CallFrame{FunctionName: f.FunctionName, Url: f.Url}
+	if f.Location != nil {
+		frame.Line, frame.Column = f.Location.LineNumber+1, f.Location.ColumnNumber+1
+	}
+	for _, s := range f.ScopeChain {
+		if s.Type == "global" || s.Object == nil || s.Object.ObjectId == "" {
+			continue
+		}
+		scope := modules.Scope{Type: s.Type, Name: s.Name, Variables: make(map[string]interface{})}
+		props, _, _, _, err := d.runtime().GetPropertiesWithParams(&gcdapi.RuntimeGetPropertiesParams{
+			ObjectId:      s.Object.ObjectId,
+			OwnProperties: true,
+		})
+		if err != nil {
+			d.log("[-] Unable to read "+s.Type+" scope variables", err)
+		}
+		for _, p := range props {
+			if len(scope.Variables) == maxScopeVariables {
+				break
+			}
+			scope.Variables[p.Name] = variableValue(p.Value)
+		}
+		frame.Scopes = append(frame.Scopes, scope)
+	}
+	return frame
+}
+
+// variableValue converts a variable to a Go value, describing objects rather than reading them
+func variableValue(r *gcdapi.RuntimeRemoteObject) interface{} {
+	if r == nil {
+		return nil
+	}
+	switch r.Type {
+	case "object":
+		if r.Subtype == "null" {
+			return nil
+		}
+		return r.Description
+	case "function", "symbol":
+		return r.Description
+	}
+	v, err := remoteValue(r)
+	if err != nil {
+		return r.Description
+	}
+	return v
+}
+
+func (d *Debugger) breakpoints() breakpointDomain {
+	if d.bp == nil {
+		return d.Target.Debugger
+	}
+	return d.bp
+}
@@ -0,0 +1,105 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"github.com/wirepair/gcd/gcdmessage"
+	"testing"
+)
+
+// mockBreakpoints stands in for the Chrome Debugger domain, handing out breakpoint ids in order
+type mockBreakpoints struct {
+	set     []*gcdapi.DebuggerSetBreakpointByUrlParams
+	resumed int
+}
+
+func (m *mockBreakpoints) SetBreakpointByUrlWithParams(v *gcdapi.DebuggerSetBreakpointByUrlParams) (string, []*gcdapi.DebuggerLocation, error) {
+	m.set = append(m.set, v)
+	return "1:" + v.Url, nil, nil
+}
+
+func (m *mockBreakpoints) ResumeWithParams(v *gcdapi.DebuggerResumeParams) (*gcdmessage.ChromeResponse, error) {
+	m.resumed++
+	return &gcdmessage.ChromeResponse{}, nil
+}
+
+func pausedEvent(t *testing.T, params string) *gcdapi.DebuggerPausedEvent {
+	msg := &gcdapi.DebuggerPausedEvent{}
+	if err := json.Unmarshal([]byte(`{"method":"Debugger.paused","Params":`+params+`}`), msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+const checkoutPaused = `{"reason":"other","hitBreakpoints":["1:https://example.com/checkout.js"],"callFrames":[
+	{"callFrameId":"f1","functionName":"sign","url":"https://example.com/checkout.js",
+	 "location":{"scriptId":"12","lineNumber":41,"columnNumber":4},
+	 "scopeChain":[
+		{"type":"local","object":{"type":"object","objectId":"local-1"}},
+		{"type":"closure","name":"init","object":{"type":"object","objectId":"closure-1"}},
+		{"type":"global","object":{"type":"object","objectId":"global-1"}}]}]}`
+
+func TestBreakpointScopeCapture(t *testing.T) {
+	var captured []modules.WebData
+	bp := &mockBreakpoints{}
+	rt := &mockRuntime{properties: map[string][]*gcdapi.RuntimePropertyDescriptor{
+		"local-1": {
+			{Name: "amount", Value: &gcdapi.RuntimeRemoteObject{Type: "number", Value: float64(42)}},
+			{Name: "key", Value: &gcdapi.RuntimeRemoteObject{Type: "string", Value: "s3cr3t"}},
+			{Name: "items", Value: &gcdapi.RuntimeRemoteObject{Type: "object", Subtype: "array", Description: "Array(3)"}},
+			{Name: "token", Value: &gcdapi.RuntimeRemoteObject{Type: "object", Subtype: "null"}},
+		},
+		"closure-1": {
+			{Name: "endpoint", Value: &gcdapi.RuntimeRemoteObject{Type: "string", Value: "/api/pay"}},
+		},
+		"global-1": {
+			{Name: "window", Value: &gcdapi.RuntimeRemoteObject{Type: "object", Description: "Window"}},
+		},
+	}}
+	d := Debugger{bp: bp, rt: rt, Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+		Registry: modules.Registry{Name: "state"},
+		Inspect: func(webData modules.WebData) error {
+			captured = append(captured, webData)
+			return nil
+		},
+	}}}}
+
+	id, err := d.SetBreakpointByURL("https://example.com/checkout.js", 42)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, bp.set[0].LineNumber, 41)
+
+	d.handlePaused(pausedEvent(t, checkoutPaused))
+
+	assert.Equal(t, len(captured), 1)
+	assert.Equal(t, captured[0].Type, "Paused")
+	assert.Equal(t, captured[0].Url, "https://example.com/checkout.js")
+	assert.Equal(t, captured[0].Paused, &modules.PausedState{
+		Reason:      "other",
+		Breakpoints: []string{id},
+		CallFrames: []modules.CallFrame{{
+			FunctionName: "sign",
+			Url:          "https://example.com/checkout.js",
+			Line:         42,
+			Column:       5,
+			Scopes: []modules.Scope{
+				{Type: "local", Variables: map[string]interface{}{"amount": float64(42), "key": "s3cr3t", "items": "Array(3)", "token": nil}},
+				{Type: "closure", Name: "init", Variables: map[string]interface{}{"endpoint": "/api/pay"}},
+			},
+		}},
+	})
+	assert.Equal(t, bp.resumed, 1)
+}
+
+func TestForeignPauseLeftAlone(t *testing.T) {
+	bp := &mockBreakpoints{}
+	d := Debugger{bp: bp, rt: &mockRuntime{}}
+
+	// an XHR breakpoint set by the user
+	d.handlePaused(pausedEvent(t, `{"reason":"XHR","callFrames":[]}`))
+	assert.Equal(t, bp.resumed, 0)
+
+	_, err := d.SetBreakpointByURL("https://example.com/app.js", 0)
+	assert.Equal(t, err.Error(), "invalid line 0, lines start at 1")
+}
@@ -0,0 +1,129 @@
+package debugger
+
+import (
+	"crypto/tls"
+	"fmt"
+	"github.com/wirepair/gcd/gcdapi"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	bridgeQueueSize = 256              // Requests waiting to be sent before new ones are dropped
+	bridgeTimeout   = 10 * time.Second // Time allowed for the proxy to answer a single request
+)
+
+// proxyBridge sends a copy of intercepted requests through an upstream proxy such as Burp or mitmproxy, so
+// that they show up in its history. Since the copy reaches the server too, only requests with safe methods are
+// sent, so that nothing changes state twice. Requests are queued and sent in the background, interception never
+// waits on the proxy: when it falls behind or cannot be reached, requests are dropped.
+type proxyBridge struct {
+	client  *http.Client
+	queue   chan *http.Request
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	dropped int
+	failed  int
+	skipped int // Requests left out because of their method
+}
+
+// safeMethods are the methods of requests the bridge sends again, which should not change state on the server
+var safeMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions}
+
+// newProxyBridge returns a bridge sending requests through proxy, presenting certs to servers asking for a
+// client certificate
+func newProxyBridge(proxy string, certs []tls.Certificate) (*proxyBridge, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
+	}
+	b := &proxyBridge{
+		client: &http.Client{
+			Timeout: bridgeTimeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyURL(u),
+				// proxies intercepting TLS use a certificate authority of their own
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true, Certificates: certs},
+			},
+			// the proxy should see the request as the browser sent it, not the requests it leads to
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		queue: make(chan *http.Request, bridgeQueueSize),
+	}
+	b.wg.Add(1)
+	go b.run()
+	return b, nil
+}
+
+// send queues a copy of the request, dropping it if the queue is full. Requests with unsafe methods are skipped
+func (b *proxyBridge) send(r *gcdapi.NetworkRequest) {
+	if r == nil || !strings.HasPrefix(r.Url, "http") {
+		return
+	}
+	if !containsFold(safeMethods, r.Method) {
+		b.mu.Lock()
+		b.skipped++
+		b.mu.Unlock()
+		return
+	}
+	req, err := http.NewRequest(r.Method, r.Url, strings.NewReader(r.PostData))
+	if err != nil {
+		return
+	}
+	for k, v := range r.Headers {
+		if containsFold(hopByHopHeaders, k) || strings.EqualFold(k, "content-length") || strings.HasPrefix(k, ":") {
+			continue
+		}
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	select {
+	case b.queue <- req:
+	default:
+		b.mu.Lock()
+		b.dropped++
+		b.mu.Unlock()
+	}
+}
+
+func (b *proxyBridge) run() {
+	defer b.wg.Done()
+	for req := range b.queue {
+		res, err := b.client.Do(req)
+		if err != nil {
+			b.mu.Lock()
+			b.failed++
+			first := b.failed == 1
+			b.mu.Unlock()
+			if first {
+				// a proxy that is down fails every request, once is enough to tell
+				log.Println("[-] Unable to send request to upstream proxy", err)
+			}
+			continue
+		}
+		io.Copy(ioutil.Discard, res.Body)
+		res.Body.Close()
+	}
+}
+
+// close sends the requests left in the queue and stops the bridge
+func (b *proxyBridge) close() {
+	close(b.queue)
+	b.wg.Wait()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.dropped > 0 || b.failed > 0 {
+		log.Printf("[-] Upstream proxy missed %d requests, %d dropped and %d failed\n", b.dropped+b.failed,
+			b.dropped, b.failed)
+	}
+	if b.skipped > 0 {
+		log.Printf("[+] %d requests with unsafe methods were not sent to the upstream proxy\n", b.skipped)
+	}
+}
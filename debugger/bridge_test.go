@@ -0,0 +1,77 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// proxied records a request received by the mock upstream proxy
+type proxied struct {
+	Method string
+	Url    string
+	Header string
+	Body   string
+}
+
+func TestUpstreamProxy(t *testing.T) {
+	var mu sync.Mutex
+	var received []proxied
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, proxied{r.Method, r.URL.String(), r.Header.Get("X-Csrf-Token"), string(body)})
+		mu.Unlock()
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer upstream.Close()
+
+	net := &mockNetwork{bodies: map[string]string{"1": `{"ok":true}`, "2": `{"id":1}`}}
+	d, err := New(Options{UpstreamProxy: upstream.URL})
+	assert.Equal(t, err, nil)
+	d.net = net
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"XHR",
+		"request":{"url":"http://example.com/api/login","method":"POST","postData":"user=admin&pass=hunter2",
+			"headers":{"X-CSRF-Token":"abc","Connection":"keep-alive"}},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"application/json"}}`))
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"2","resourceType":"XHR",
+		"request":{"url":"http://example.com/api/user?id=1","method":"GET",
+			"headers":{"X-CSRF-Token":"abc","Connection":"keep-alive"}},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"application/json"}}`))
+	d.bridge.close()
+
+	// the response sent back to the browser is not affected by the proxy
+	assert.Equal(t, len(net.calls()), 2)
+	// the login is not made a second time
+	assert.Equal(t, received, []proxied{{
+		Method: "GET",
+		Url:    "http://example.com/api/user?id=1",
+		Header: "abc",
+	}})
+	assert.Equal(t, d.bridge.skipped, 1)
+}
+
+func TestUpstreamProxyDown(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	proxy := upstream.URL
+	upstream.Close()
+
+	net := &mockNetwork{bodies: map[string]string{"1": "var a;"}}
+	d, err := New(Options{UpstreamProxy: proxy})
+	assert.Equal(t, err, nil)
+	d.net = net
+
+	d.handleInterception(scriptResponse(t, "1", "", "http://example.com/app.js"))
+	assert.Equal(t, len(net.calls()), 1)
+	d.bridge.close()
+	assert.Equal(t, d.bridge.failed, 1)
+}
+
+func TestValidateUpstreamProxy(t *testing.T) {
+	err := Options{UpstreamProxy: "localhost:8080"}.Validate()
+	assert.Equal(t, err.Error(), `invalid options: upstreamProxy "localhost:8080" is not an http or https proxy url`)
+}
@@ -0,0 +1,20 @@
+package debugger
+
+import "fmt"
+
+// ClearBrowserState clears the Chrome cache, cookies or both, for sessions that must start from a clean slate.
+// It can be called once the debugger is started, before navigating or between navigations.
+// It returns an error if Chrome fails to clear either of them
+func (d *Debugger) ClearBrowserState(clearCache, clearCookies bool) error {
+	if clearCache {
+		if _, err := d.network().ClearBrowserCache(); err != nil {
+			return fmt.Errorf("unable to clear browser cache: %s", err)
+		}
+	}
+	if clearCookies {
+		if _, err := d.network().ClearBrowserCookies(); err != nil {
+			return fmt.Errorf("unable to clear browser cookies: %s", err)
+		}
+	}
+	return nil
+}
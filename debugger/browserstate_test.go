@@ -0,0 +1,30 @@
+package debugger
+
+import (
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestClearBrowserState(t *testing.T) {
+	for _, test := range []struct {
+		cache, cookies bool
+		cleared        []string
+	}{
+		{true, true, []string{"cache", "cookies"}},
+		{true, false, []string{"cache"}},
+		{false, true, []string{"cookies"}},
+		{false, false, nil},
+	} {
+		net := &mockNetwork{}
+		d := Debugger{net: net}
+		assert.Equal(t, d.ClearBrowserState(test.cache, test.cookies), nil)
+		assert.Equal(t, net.cleared, test.cleared)
+	}
+
+	net := &mockNetwork{clearErr: errors.New("websocket closed")}
+	d := Debugger{net: net}
+	err := d.ClearBrowserState(true, true)
+	assert.Equal(t, err.Error(), "unable to clear browser cache: websocket closed")
+	assert.Equal(t, net.cleared, []string{"cache"})
+}
@@ -0,0 +1,83 @@
+package debugger
+
+// Edit regions separated by fewer equal bytes than this are counted as a single replacement, since a byte
+// level diff tends to split a substituted word around the letters both versions have in common
+const mergeGap = 4
+
+// Bodies needing more single byte edits than this are not diffed, only their size delta is reported
+const maxEdits = 1000
+
+// countReplacements returns the number of distinct regions that differ between a and b, or -1 if the
+// bodies differ by more than maxEdits bytes. It uses the Myers diff algorithm, which is fast when the
+// number of edits is small compared to the size of the bodies as is the case for most processors.
+func countReplacements(a, b string) int {
+	if a == b {
+		return 0
+	}
+	// trimming the common prefix and suffix keeps the diff small for large bodies
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 1
+	}
+
+	n, m := len(a), len(b)
+	offset := maxEdits + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= maxEdits; d++ {
+		// only keep the diagonals step d can read from
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return countRegions(trace, n, m, d)
+			}
+		}
+	}
+	return -1
+}
+
+// countRegions walks the diff back from the end of both strings and counts the edit regions,
+// merging those separated by short runs of equal bytes
+func countRegions(trace [][]int, x, y, d int) int {
+	regions := 0
+	equal := mergeGap
+	for ; d > 0; d-- {
+		// trace[d] holds the diagonals -d-1 through d+1
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[k-1+d+1] < v[k+1+d+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[prevK+d+1]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			equal++
+		}
+		if equal >= mergeGap {
+			regions++
+		}
+		equal = 0
+		x, y = prevX, prevY
+	}
+	return regions
+}
@@ -0,0 +1,17 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+func TestCountReplacements(t *testing.T) {
+	body := `var a={isAdmin=false};function b(){return isAdmin=false}if(isAdmin=false){}`
+	assert.Equal(t, countReplacements(body, body), 0)
+	assert.Equal(t, countReplacements(body, strings.Replace(body, "isAdmin=false", "isAdmin=true", -1)), 3)
+	assert.Equal(t, countReplacements(body, strings.Replace(body, "isAdmin=false", "isAdmin=true", 1)), 1)
+	assert.Equal(t, countReplacements(body, body+"console.log(1)"), 1)
+	assert.Equal(t, countReplacements("abc", ""), 1)
+	assert.Equal(t, countReplacements(strings.Repeat("a", 3000), strings.Repeat("b", 3000)), -1)
+}
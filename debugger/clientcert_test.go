@@ -0,0 +1,94 @@
+package debugger
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeClientCert writes a self-signed client certificate and its key to dir, as PEM files
+func writeClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Equal(t, err, nil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "gorp"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Equal(t, err, nil)
+	cert, err := x509.ParseCertificate(der)
+	assert.Equal(t, err, nil)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Equal(t, err, nil)
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	assert.Equal(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600), nil)
+	assert.Equal(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600), nil)
+	return cert, certFile, keyFile
+}
+
+func TestResendWithClientCert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-mtls")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	cert, certFile, keyFile := writeClientCert(t, dir)
+
+	clients := x509.NewCertPool()
+	clients.AddCert(cert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello " + r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clients}
+	server.StartTLS()
+	defer server.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	captured := RequestRecord{Url: server.URL + "/api/me", Method: "GET"}
+	d := Debugger{ck: &mockCookies{}, resendRoots: roots, Options: Options{ClientCert: certFile, ClientKey: keyFile}}
+	res, err := d.ResendRequest(captured, RequestMods{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, res.Body, "hello gorp")
+
+	d.Options = Options{}
+	_, err = d.ResendRequest(captured, RequestMods{})
+	assert.Equal(t, err != nil, true)
+}
+
+func TestInvalidClientCert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-mtls")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	certFile := filepath.Join(dir, "client.pem")
+	assert.Equal(t, ioutil.WriteFile(certFile, []byte("not a certificate"), 0600), nil)
+
+	d := Debugger{ck: &mockCookies{}, Options: Options{ClientCert: certFile, ClientKey: certFile}}
+	_, err = d.ResendRequest(RequestRecord{Url: "https://example.com/", Method: "GET"}, RequestMods{})
+	assert.Equal(t, strings.HasPrefix(err.Error(), "unable to load client certificate "+certFile+" with key "+certFile+": "), true)
+
+	_, err = New(Options{ClientCert: certFile, ClientKey: certFile})
+	assert.Equal(t, strings.HasPrefix(err.Error(), "unable to load client certificate"), true)
+
+	err = Options{ClientCert: certFile}.Validate()
+	assert.Equal(t, err.Error(), "invalid options: clientCert and clientKey must be set together")
+}
@@ -0,0 +1,56 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"strings"
+)
+
+// HeaderCondition restricts processors to requests carrying a given header, so that modified responses are
+// only served to requests marked for it and regular traffic is left alone
+type HeaderCondition struct {
+	Processors []string // Names of the processors the condition applies to, all processors when empty
+	Header     string   // Name of the request header, matched regardless of case
+	Value      string   // Value the header must match, with '*' and '?' wildcards. Any value when empty
+}
+
+// appliesTo reports whether the condition gates the named processor
+func (c HeaderCondition) appliesTo(processor string) bool {
+	if len(c.Processors) == 0 {
+		return true
+	}
+	for _, p := range c.Processors {
+		if p == processor {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the request headers satisfy the condition
+func (c HeaderCondition) matches(headers map[string]interface{}) bool {
+	for k, v := range headers {
+		if !strings.EqualFold(k, c.Header) {
+			continue
+		}
+		s, ok := v.(string)
+		return ok && (c.Value == "" || wildcardRegexp(c.Value).MatchString(s))
+	}
+	return false
+}
+
+// conditionsMet reports whether every header condition applying to the processor holds for the request, and
+// whether the processor runs on navigations when the request is one and its matcher selects the web data
+func (d *Debugger) conditionsMet(p modules.ProcessorModule, data modules.WebData) bool {
+	if data.Navigation && !d.runsOnNavigations(p.Registry.Name) {
+		return false
+	}
+	if !d.processorMatches(p.Registry.Name, data) {
+		return false
+	}
+	for _, c := range d.Options.HeaderConditions {
+		if c.appliesTo(p.Registry.Name) && !c.matches(data.RequestHeaders) {
+			return false
+		}
+	}
+	return true
+}
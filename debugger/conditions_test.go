@@ -0,0 +1,43 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestHeaderConditions(t *testing.T) {
+	var processed []string
+	d := Debugger{
+		Options: Options{HeaderConditions: []HeaderCondition{
+			{Processors: []string{"marked"}, Header: "X-RE", Value: "1"},
+		}},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{
+			countingProcessor("marked", &processed),
+			countingProcessor("always", &processed),
+		}},
+	}
+
+	body, err := d.processBody(modules.WebData{
+		Body:           "<html></html>",
+		Url:            "http://example.com/marked",
+		RequestHeaders: map[string]interface{}{"x-re": "1"},
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, "<html></html>/*marked*//*always*/")
+
+	body, err = d.processBody(modules.WebData{
+		Body:           "<html></html>",
+		Url:            "http://example.com/regular",
+		RequestHeaders: map[string]interface{}{"User-Agent": "Mozilla/5.0"},
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, "<html></html>/*always*/")
+
+	body, _ = d.processBody(modules.WebData{
+		Body:           "<html></html>",
+		Url:            "http://example.com/other",
+		RequestHeaders: map[string]interface{}{"X-RE": "2"},
+	})
+	assert.Equal(t, body, "<html></html>/*always*/")
+}
@@ -0,0 +1,63 @@
+package debugger
+
+import (
+	"github.com/fatih/color"
+	"os"
+)
+
+// msgKind tells apart the messages printed to the console, so that they can be colored
+type msgKind int
+
+const (
+	msgInfo msgKind = iota
+	msgRequest
+	msgNavigation
+	msgRedirect
+	msgBlocked
+	msgError
+)
+
+// console colors messages printed to the console by kind. Colors are left out unless they were asked for
+// with Options.Color and the console is a terminal, so that log files and pipes never get escape codes
+type console struct {
+	colors map[msgKind]*color.Color
+}
+
+func newConsole(enabled bool, out *os.File) console {
+	if !enabled || !isTerminal(out) {
+		return console{}
+	}
+	c := console{colors: map[msgKind]*color.Color{
+		msgRequest:    color.New(color.FgCyan),
+		msgNavigation: color.New(color.Bold, color.FgCyan),
+		msgRedirect:   color.New(color.FgYellow),
+		msgBlocked:    color.New(color.FgMagenta),
+		msgError:      color.New(color.FgRed),
+	}}
+	for _, v := range c.colors {
+		// the color package only checks whether stdout is a terminal, but logs go to stderr
+		v.EnableColor()
+	}
+	return c
+}
+
+// format returns l colored according to its kind
+func (c console) format(kind msgKind, l string) string {
+	v, ok := c.colors[kind]
+	if !ok {
+		return l
+	}
+	return v.Sprint(l)
+}
+
+// isTerminal reports whether f is a terminal rather than a file or a pipe
+func isTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
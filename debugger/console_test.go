@@ -0,0 +1,61 @@
+package debugger
+
+import (
+	"bytes"
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureLog returns what fn logged
+func captureLog(fn func()) string {
+	var b bytes.Buffer
+	log.SetOutput(&b)
+	defer log.SetOutput(ioutil.Discard)
+	fn()
+	return b.String()
+}
+
+func TestConsoleColors(t *testing.T) {
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if !isTerminal(f) {
+		t.Skip("no character device to stand in for a terminal")
+	}
+
+	d := Debugger{console: newConsole(true, f)}
+	out := captureLog(func() {
+		d.logAs(msgRequest, "[+] Request intercepted for https://example.com/", nil)
+		d.log("[-] Unable to read eval call", nil)
+		d.log("[+] Screenshot saved to /tmp/1.png", nil)
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Equal(t, strings.HasSuffix(lines[0], "\x1b[36m[+] Request intercepted for https://example.com/\x1b[0m"), true)
+	assert.Equal(t, strings.HasSuffix(lines[1], "\x1b[31m[-] Unable to read eval call\x1b[0m"), true)
+	assert.Equal(t, strings.Contains(lines[2], "\x1b["), false)
+}
+
+func TestConsoleWithoutColors(t *testing.T) {
+	f, err := ioutil.TempFile("", "gorp-console-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	for _, c := range []console{newConsole(false, os.Stderr), newConsole(true, f), newConsole(true, nil)} {
+		d := Debugger{console: c}
+		out := captureLog(func() {
+			d.logAs(msgRequest, "[+] Request intercepted for https://example.com/", nil)
+			d.log("[-] Unable to read eval call", nil)
+		})
+		assert.Equal(t, strings.Contains(out, "\x1b["), false)
+		assert.Equal(t, strings.Contains(out, "[-] Unable to read eval call"), true)
+	}
+}
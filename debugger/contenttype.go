@@ -0,0 +1,34 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+)
+
+// ContentTypeOverride serves responses whose url matches Url with a different Content-Type, such as JSON as
+// text/plain to read it in the browser
+type ContentTypeOverride struct {
+	Url         string // Url pattern using Chrome interception wildcards
+	ContentType string // Content-Type header value to serve, such as "text/plain; charset=utf-8"
+}
+
+// contentTypeFor returns the Content-Type a rebuilt response is served with when it is overridden. The first
+// override matching the url wins over what processors asked for, and an empty string keeps the original one
+func (d *Debugger) contentTypeFor(data modules.WebData, body string) string {
+	for _, o := range d.Options.ContentTypeOverrides {
+		if wildcardRegexp(o.Url).MatchString(data.Url) {
+			return o.ContentType
+		}
+	}
+	contentType := ""
+	result := data
+	result.Body = body
+	for _, p := range d.Modules.Processors {
+		if p.ContentType == nil || !d.conditionsMet(p, data) {
+			continue
+		}
+		if c := p.ContentType(result); c != "" {
+			contentType = c
+		}
+	}
+	return contentType
+}
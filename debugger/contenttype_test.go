@@ -0,0 +1,74 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+// headerLines returns the header lines of a raw response with the given name, regardless of case
+func headerLines(raw string, name string) []string {
+	var lines []string
+	head := strings.SplitN(raw, "\r\n\r\n", 2)[0]
+	for _, l := range strings.Split(head, "\r\n")[1:] {
+		if strings.HasPrefix(strings.ToLower(l), strings.ToLower(name)+":") {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+func TestContentTypeOverride(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"secret":true}`, "2": `{"id":2}`}}
+	d := Debugger{
+		net: net,
+		Options: Options{ContentTypeOverrides: []ContentTypeOverride{
+			{Url: "*/api/r1", ContentType: "text/plain; charset=utf-8"},
+		}},
+	}
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/r1","method":"GET"},"responseStatusCode":200,
+		"responseHeaders":{"Content-Type":"application/json","content-type":"application/json","X-Id":"1"}}`))
+	d.handleInterception(xhrResponse(t, "2", "r2"))
+
+	calls := net.calls()
+	sent := decodeRaw(t, calls[0].RawResponse)
+	assert.Equal(t, headerLines(sent, "content-type"), []string{"Content-Type: text/plain; charset=utf-8"})
+	assert.Equal(t, headerLines(sent, "x-id"), []string{"X-Id: 1"})
+	assert.Equal(t, strings.HasSuffix(sent, `{"secret":true}`), true)
+
+	// other responses are continued untouched
+	assert.Equal(t, calls[1].RawResponse, "")
+}
+
+// htmlViewer serves JSON responses as HTML
+type htmlViewer struct{}
+
+func (htmlViewer) Init()                              {}
+func (htmlViewer) GetOptions() []modules.Option       { return nil }
+func (htmlViewer) GetRegistry() modules.Registry      { return modules.Registry{Name: "viewer"} }
+func (htmlViewer) ContentType(modules.WebData) string { return "text/html" }
+func (htmlViewer) Process(webData modules.WebData) (string, error) {
+	return "<pre>" + webData.Body + "</pre>", nil
+}
+
+func TestProcessorContentType(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`}}
+	d := Debugger{net: net}
+	d.AddProcessor(htmlViewer{})
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/r1","method":"GET"},"responseStatusCode":200,"responseHeaders":{}}`))
+
+	sent := decodeRaw(t, net.calls()[0].RawResponse)
+	assert.Equal(t, headerLines(sent, "content-type"), []string{"Content-Type: text/html"})
+	assert.Equal(t, strings.HasSuffix(sent, `<pre>{"id":1}</pre>`), true)
+}
+
+func TestValidateContentTypeOverrides(t *testing.T) {
+	err := Options{ContentTypeOverrides: []ContentTypeOverride{{Url: "*", ContentType: "text/plain;;="}, {ContentType: "text/html"}}}.Validate()
+	assert.Equal(t, err.Error(), `invalid options: content type override 1 has an invalid content type "text/plain;;="; `+
+		"content type override 2 has no url pattern")
+}
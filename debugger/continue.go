@@ -0,0 +1,40 @@
+package debugger
+
+import "github.com/wirepair/gcd/gcdapi"
+
+// continueRequest lets Chrome carry on with an intercepted request, optionally with a raw response to serve,
+// or a new url and post data to send. A request that is never continued stays pending in the browser, so a
+// failed attempt is retried once, and a modified request that still cannot be sent is forwarded unchanged.
+func (d *Debugger) continueRequest(iid string, reason string, rawResponse string, url string, postData string) {
+	d.continueIntercepted(iid, reason, rawResponse, url, postData, nil)
+}
+
+// continueAuthChallenge answers an authentication challenge the way continueRequest continues other
+// interceptions. Credentials that still cannot be sent leave the challenge to the browser
+func (d *Debugger) continueAuthChallenge(iid string, response *gcdapi.NetworkAuthChallengeResponse) {
+	d.continueIntercepted(iid, "", "", "", "", response)
+}
+
+func (d *Debugger) continueIntercepted(iid string, reason string, rawResponse string, url string, postData string,
+	auth *gcdapi.NetworkAuthChallengeResponse) {
+	_, err := d.network().ContinueInterceptedRequest(iid, reason, rawResponse, url, "", postData, nil, auth)
+	if err == nil {
+		return
+	}
+	d.log("[-] Unable to continue intercepted request "+iid+", retrying", err)
+	_, err = d.network().ContinueInterceptedRequest(iid, reason, rawResponse, url, "", postData, nil, auth)
+	if err == nil {
+		return
+	}
+	if rawResponse != "" || url != "" || postData != "" || auth != nil && auth.Response != "Default" {
+		d.log("[-] Unable to send modified request "+iid+", forwarding it unchanged", err)
+		if auth != nil {
+			auth = &gcdapi.NetworkAuthChallengeResponse{Response: "Default"}
+		}
+		_, err = d.network().ContinueInterceptedRequest(iid, reason, "", "", "", "", nil, auth)
+		if err == nil {
+			return
+		}
+	}
+	d.log("[-] Giving up on intercepted request "+iid+", it may be left pending in the browser", err)
+}
@@ -0,0 +1,58 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"testing"
+)
+
+func TestContinueRequestRetries(t *testing.T) {
+	net := &mockNetwork{failures: 1}
+	d := Debugger{net: net}
+
+	d.continueRequest("1", "", "cmF3", "", "")
+	calls := net.calls()
+	assert.Equal(t, len(calls), 2)
+	assert.Equal(t, calls[0].RawResponse, "cmF3")
+	assert.Equal(t, calls[1].InterceptionId, "1")
+	assert.Equal(t, calls[1].RawResponse, "cmF3")
+}
+
+func TestContinueRequestForwardsUnchanged(t *testing.T) {
+	net := &mockNetwork{failures: 2}
+	d := Debugger{net: net}
+
+	d.continueRequest("1", "", "", "https://example.com/?debug=1", "a=1")
+	calls := net.calls()
+	assert.Equal(t, len(calls), 3)
+	assert.Equal(t, calls[1].Url, "https://example.com/?debug=1")
+	// the modified request could not be sent, so the original one goes through
+	assert.Equal(t, calls[2], continued{InterceptionId: "1"})
+}
+
+func TestContinueRequestThroughInterception(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`}, failures: 1}
+	d := Debugger{net: net}
+	d.SetBodyTransform(func(webData modules.WebData) (string, error) {
+		return webData.Body + "//altered", nil
+	})
+
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	calls := net.calls()
+	assert.Equal(t, len(calls), 2)
+	assert.Equal(t, calls[0].RawResponse != "", true)
+	assert.Equal(t, calls[1].RawResponse, calls[0].RawResponse)
+}
+
+func TestContinueAuthChallengeFallsBack(t *testing.T) {
+	net := &mockNetwork{failures: 2}
+	d := Debugger{net: net}
+
+	d.continueAuthChallenge("1", &gcdapi.NetworkAuthChallengeResponse{Response: "ProvideCredentials", Username: "admin"})
+	calls := net.calls()
+	assert.Equal(t, len(calls), 3)
+	assert.Equal(t, calls[1].Auth.Response, "ProvideCredentials")
+	// the credentials could not be sent, so the browser gets to prompt for them
+	assert.Equal(t, calls[2], continued{InterceptionId: "1", Auth: &gcdapi.NetworkAuthChallengeResponse{Response: "Default"}})
+}
@@ -0,0 +1,88 @@
+package debugger
+
+import (
+	"fmt"
+	"github.com/wirepair/gcd/gcdapi"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cookieDomain is the subset of the Chrome Dev Tools Network domain used to read the cookies of the browser.
+// It is implemented by gcdapi.Network.
+type cookieDomain interface {
+	GetAllCookies() ([]*gcdapi.NetworkCookie, error)
+}
+
+// ExportCookies writes every cookie held by the browser to path in the Netscape cookie file format, as read
+// by curl with -b, so that the session can be reused from other tools. Cookies are grouped by domain. The file
+// is only readable by the current user, since it holds session tokens.
+func (d *Debugger) ExportCookies(path string) error {
+	cookies, err := d.cookies().GetAllCookies()
+	if err != nil {
+		return fmt.Errorf("unable to get cookies: %s", err)
+	}
+	return ioutil.WriteFile(path, []byte(netscapeCookies(cookies)), 0600)
+}
+
+// netscapeCookies formats cookies as a Netscape cookie file, sorted by domain, path and name
+func netscapeCookies(cookies []*gcdapi.NetworkCookie) string {
+	sorted := append([]*gcdapi.NetworkCookie(nil), cookies...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if da, db := strings.TrimPrefix(a.Domain, "."), strings.TrimPrefix(b.Domain, "."); da != db {
+			return da < db
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Name < b.Name
+	})
+
+	var b strings.Builder
+	b.WriteString("# Netscape HTTP Cookie File\n")
+	b.WriteString("# Exported by gorp\n\n")
+	for _, c := range sorted {
+		domain := c.Domain
+		if c.HttpOnly {
+			// curl's convention for HttpOnly cookies, tools unaware of it skip these lines as comments
+			domain = "#HttpOnly_" + domain
+		}
+		// domain cookies are stored with a leading dot, host-only cookies without
+		subdomains := strings.HasPrefix(c.Domain, ".")
+		path := c.Path
+		if path == "" {
+			path = "/"
+		}
+		expires := int64(0)
+		if !c.Session && c.Expires > 0 {
+			expires = int64(c.Expires)
+		}
+		fields := []string{
+			domain,
+			netscapeBool(subdomains),
+			path,
+			netscapeBool(c.Secure),
+			strconv.FormatInt(expires, 10),
+			c.Name,
+			c.Value,
+		}
+		b.WriteString(strings.Join(fields, "\t") + "\n")
+	}
+	return b.String()
+}
+
+func netscapeBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (d *Debugger) cookies() cookieDomain {
+	if d.ck == nil {
+		return d.Target.Network
+	}
+	return d.ck
+}
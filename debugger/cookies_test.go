@@ -0,0 +1,63 @@
+package debugger
+
+import (
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mockCookies stands in for the Chrome Network domain when reading cookies
+type mockCookies struct {
+	cookies []*gcdapi.NetworkCookie
+	err     error
+}
+
+func (m *mockCookies) GetAllCookies() ([]*gcdapi.NetworkCookie, error) {
+	return m.cookies, m.err
+}
+
+func TestExportCookies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-cookies")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cookies.txt")
+
+	d := Debugger{ck: &mockCookies{cookies: []*gcdapi.NetworkCookie{
+		{Name: "theme", Value: "dark", Domain: "www.example.com", Path: "/", Session: true, Expires: -1},
+		{Name: "sid", Value: "s3cr3t", Domain: ".example.com", Path: "/", Expires: 1893456000.25, HttpOnly: true, Secure: true},
+		{Name: "_ga", Value: "GA1.2.3", Domain: ".analytics.net", Path: "/collect", Expires: 1893456000},
+	}}}
+	assert.Equal(t, d.ExportCookies(path), nil)
+
+	info, err := os.Stat(path)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0600))
+
+	data, _ := ioutil.ReadFile(path)
+	var records [][]string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(line, "# ") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		assert.Equal(t, len(fields), 7, line)
+		records = append(records, fields)
+	}
+	assert.Equal(t, records, [][]string{
+		{".analytics.net", "TRUE", "/collect", "FALSE", "1893456000", "_ga", "GA1.2.3"},
+		{"#HttpOnly_.example.com", "TRUE", "/", "TRUE", "1893456000", "sid", "s3cr3t"},
+		{"www.example.com", "FALSE", "/", "FALSE", "0", "theme", "dark"},
+	})
+	assert.Equal(t, strings.HasPrefix(string(data), "# Netscape HTTP Cookie File\n"), true)
+}
+
+func TestExportCookiesError(t *testing.T) {
+	d := Debugger{ck: &mockCookies{err: errors.New("target closed")}}
+	err := d.ExportCookies(filepath.Join(os.TempDir(), "gorp-cookies-never-written.txt"))
+	assert.Equal(t, err.Error(), "unable to get cookies: target closed")
+}
@@ -0,0 +1,45 @@
+package debugger
+
+import (
+	"github.com/wirepair/gcd/gcdapi"
+	"net/http"
+	"strings"
+)
+
+// preflightMaxAge is how long, in seconds, browsers may cache the answers to preflights
+const preflightMaxAge = "86400"
+
+// isPreflight reports whether the request is a CORS preflight, an OPTIONS request asking for permission to
+// send the real request
+func isPreflight(req *gcdapi.NetworkRequest) bool {
+	return req != nil && strings.EqualFold(req.Method, http.MethodOptions) &&
+		headerValue(req.Headers, "access-control-request-method") != ""
+}
+
+// preflightHeaders returns permissive CORS headers granting whatever the preflight asked for. The origin is
+// echoed rather than using a wildcard, so that requests with credentials are allowed as well
+func preflightHeaders(req *gcdapi.NetworkRequest) map[string]string {
+	headers := map[string]string{
+		"Access-Control-Allow-Methods": headerValue(req.Headers, "access-control-request-method"),
+		"Access-Control-Max-Age":       preflightMaxAge,
+		"Vary":                         "Origin",
+	}
+	if origin := headerValue(req.Headers, "origin"); origin != "" && origin != "null" {
+		headers["Access-Control-Allow-Origin"] = origin
+		headers["Access-Control-Allow-Credentials"] = "true"
+	} else {
+		headers["Access-Control-Allow-Origin"] = "*"
+	}
+	if h := headerValue(req.Headers, "access-control-request-headers"); h != "" {
+		headers["Access-Control-Allow-Headers"] = h
+	}
+	return headers
+}
+
+// answerPreflight answers a CORS preflight intercepted at the "Request" stage with permissive CORS headers,
+// without sending it to the server
+func (d *Debugger) answerPreflight(msg *gcdapi.NetworkRequestInterceptedEvent) {
+	d.log("[+] Answering preflight for "+msg.Params.Request.Url, nil)
+	raw := rawResponse(http.StatusNoContent, preflightHeaders(msg.Params.Request), "")
+	d.continueRequest(msg.Params.InterceptionId, "", raw, "", "")
+}
@@ -0,0 +1,82 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+const preflightEvent = `{"interceptionId":"1","frameId":"top-frame","resourceType":"Preflight",
+	"request":{"url":"https://api.example.com/v1/orders","method":"OPTIONS","headers":{
+		"Origin":"http://localhost:3000",
+		"Access-Control-Request-Method":"PUT",
+		"Access-Control-Request-Headers":"authorization,content-type"}}}`
+
+func TestAnswerPreflight(t *testing.T) {
+	var processed []string
+	net := &mockNetwork{}
+	d := Debugger{
+		net:     net,
+		Options: Options{AnswerPreflights: true},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{countingProcessor("p", &processed)}},
+	}
+
+	d.handleInterception(interceptedEvent(t, preflightEvent))
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	assert.Equal(t, decodeRaw(t, calls[0].RawResponse), "HTTP/1.1 204 No Content\r\n"+
+		"Access-Control-Allow-Credentials: true\r\n"+
+		"Access-Control-Allow-Headers: authorization,content-type\r\n"+
+		"Access-Control-Allow-Methods: PUT\r\n"+
+		"Access-Control-Allow-Origin: http://localhost:3000\r\n"+
+		"Access-Control-Max-Age: 86400\r\n"+
+		"Vary: Origin\r\n"+
+		"Content-Length: 0\r\n\r\n")
+	assert.Equal(t, len(processed), 0)
+}
+
+func TestOtherRequestsForwarded(t *testing.T) {
+	var processed []string
+	net := &mockNetwork{}
+	d := Debugger{
+		net:     net,
+		Options: Options{AnswerPreflights: true},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{{
+			Registry: modules.Registry{Name: "p", DocTypes: []string{"Request"}},
+			Process: func(webData modules.WebData) (string, error) {
+				processed = append(processed, webData.Url)
+				return webData.Body + "&p=1", nil
+			},
+		}}},
+	}
+
+	// a beacon caught by the pattern for preflights reported as "Other" by older versions of Chrome
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","frameId":"top-frame","resourceType":"Other",
+		"request":{"url":"https://api.example.com/v1/stats","method":"POST","postData":"a=1"}}`))
+
+	assert.Equal(t, net.calls(), []continued{{InterceptionId: "1"}})
+	assert.Equal(t, len(processed), 0)
+}
+
+func TestPreflightForwardedByDefault(t *testing.T) {
+	net := &mockNetwork{}
+	d := Debugger{net: net}
+
+	d.handleInterception(interceptedEvent(t, preflightEvent))
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	assert.Equal(t, calls[0].RawResponse, "")
+}
+
+func TestInterceptionPatternsPreflights(t *testing.T) {
+	patterns := InterceptionPatterns(Options{Scope: "example.com", AnswerPreflights: true})
+	found := map[string]bool{}
+	for _, p := range patterns {
+		if p.InterceptionStage == "Request" {
+			found[p.ResourceType] = true
+		}
+	}
+	assert.Equal(t, found, map[string]bool{"Preflight": true, "Other": true})
+}
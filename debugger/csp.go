@@ -0,0 +1,63 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"log"
+)
+
+// SetupCSPViolations passes the Content Security Policy violations logged by the browser to inspectors, as web
+// data of type "CSPViolation", and reports them as findings. No violation is ever logged for a policy that is
+// stripped from responses, such as with Options.DenyHeaders.
+func (d *Debugger) SetupCSPViolations() error {
+	if containsFold(d.Options.DenyHeaders, "Content-Security-Policy") {
+		d.log("[-] Content-Security-Policy headers are stripped, only policies set by meta tags report violations", nil)
+	}
+	if _, err := d.Target.Log.Enable(); err != nil {
+		return err
+	}
+	d.Target.Subscribe("Log.entryAdded", func(target *gcd.ChromeTarget, v []byte) {
+		msg := &gcdapi.LogEntryAddedEvent{}
+		err := json.Unmarshal(v, msg)
+		if err != nil {
+			log.Println("[-] Unable to read log entry", err)
+			return
+		}
+		d.handleLogEntry(msg)
+	})
+	return nil
+}
+
+// handleLogEntry reports the log entries that are CSP violations, and ignores any other
+func (d *Debugger) handleLogEntry(msg *gcdapi.LogEntryAddedEvent) {
+	entry := msg.Params.Entry
+	// violations are logged as "security" entries, or as "other" ones by older versions of Chrome
+	if entry == nil || (entry.Source != "security" && entry.Source != "other") {
+		return
+	}
+	violation, ok := modules.ParseCSPViolation(entry.Text)
+	if !ok {
+		return
+	}
+	d.log("[+] CSP violation on "+entry.Url+": "+entry.Text, nil)
+
+	rule := "CSP/" + violation.DirectiveName()
+	if violation.ReportOnly {
+		rule += "/report-only"
+	}
+	d.Findings.Report(modules.Finding{
+		Rule:   rule,
+		Url:    entry.Url,
+		Detail: entry.Text,
+	})
+	d.CallInspectors(modules.WebData{
+		Body:      entry.Text,
+		Type:      "CSPViolation",
+		Url:       entry.Url,
+		RequestId: entry.NetworkRequestId,
+		Violation: violation,
+		Findings:  d.Findings,
+	})
+}
@@ -0,0 +1,63 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"testing"
+)
+
+func logEntry(source string, text string) *gcdapi.LogEntryAddedEvent {
+	msg := &gcdapi.LogEntryAddedEvent{}
+	msg.Params.Entry = &gcdapi.LogLogEntry{
+		Source:           source,
+		Level:            "error",
+		Text:             text,
+		Url:              "https://example.com/account",
+		NetworkRequestId: "r1",
+	}
+	return msg
+}
+
+func TestCSPViolationReported(t *testing.T) {
+	var received []modules.WebData
+	d := Debugger{
+		Findings: &modules.Findings{},
+		Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+			Registry: modules.Registry{Name: "csp"},
+			Inspect: func(webData modules.WebData) error {
+				received = append(received, webData)
+				return nil
+			},
+		}}},
+	}
+
+	text := `Refused to load the script 'https://cdn.evil.com/x.js' because it violates the following ` +
+		`Content Security Policy directive: "script-src 'self'".`
+	d.handleLogEntry(logEntry("security", text))
+	// other log entries are left alone
+	d.handleLogEntry(logEntry("network", "Failed to load resource: the server responded with a status of 404"))
+	d.handleLogEntry(logEntry("other", "Uncaught ReferenceError: x is not defined"))
+
+	assert.Equal(t, len(received), 1)
+	assert.Equal(t, received[0].Type, "CSPViolation")
+	assert.Equal(t, received[0].Url, "https://example.com/account")
+	assert.Equal(t, received[0].RequestId, "r1")
+	assert.Equal(t, received[0].Violation.BlockedUrl, "https://cdn.evil.com/x.js")
+	assert.Equal(t, received[0].Violation.Directive, "script-src 'self'")
+
+	findings := d.Findings.All()
+	assert.Equal(t, len(findings), 1)
+	assert.Equal(t, findings[0].Rule, "CSP/script-src")
+	assert.Equal(t, findings[0].Detail, text)
+}
+
+func TestCSPReportOnlyViolation(t *testing.T) {
+	d := Debugger{Findings: &modules.Findings{}}
+	d.handleLogEntry(logEntry("security", `[Report Only] Refused to connect to 'https://api.other.com/' because `+
+		`it violates the following Content Security Policy directive: "connect-src 'self'".`))
+
+	findings := d.Findings.All()
+	assert.Equal(t, len(findings), 1)
+	assert.Equal(t, findings[0].Rule, "CSP/connect-src/report-only")
+}
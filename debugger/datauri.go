@@ -0,0 +1,64 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+)
+
+// processDataURIs passes the resources embedded as data: URIs in body to the processors that declare the
+// "DataURI" doc type, as web data whose body is the decoded resource and Content-Type header its media type.
+// Resources that were changed are encoded back into the body. A failing processor leaves its resource as it was.
+func (d *Debugger) processDataURIs(data modules.WebData, body string) string {
+	var processors []modules.ProcessorModule
+	for _, v := range d.Modules.Processors {
+		if !handlesDocType(v.Registry, "DataURI") {
+			continue
+		}
+		p := v
+		if p.Process == nil && p.ProcessBinary != nil {
+			p.Process = func(webData modules.WebData) (string, error) {
+				b, err := v.ProcessBinary(webData, []byte(webData.Body))
+				return string(b), err
+			}
+		}
+		if p.Process != nil {
+			processors = append(processors, p)
+		}
+	}
+	if len(processors) == 0 {
+		return body
+	}
+
+	result, n := api.ReplaceDataURIs(body, func(uri *api.DataURI) bool {
+		webData := modules.WebData{
+			Body:           string(uri.Data),
+			Headers:        map[string]interface{}{"Content-Type": uri.MediaType},
+			RequestHeaders: data.RequestHeaders,
+			Type:           "DataURI",
+			Url:            data.Url,
+			RequestId:      data.RequestId,
+			FrameId:        data.FrameId,
+			Initiator:      data.Initiator,
+			Redirects:      data.Redirects,
+			Findings:       d.Findings,
+		}
+		original := webData.Body
+		for _, p := range processors {
+			altered, err := d.runProcessor(p, webData)
+			if err != nil {
+				d.log("[-] Unable to process data uri in "+data.Url, err)
+				return false
+			}
+			webData.Body = altered
+		}
+		if webData.Body == original {
+			return false
+		}
+		uri.Data = []byte(webData.Body)
+		return true
+	})
+	if n > 0 {
+		d.log("[+] Replaced data uris in "+data.Url, nil)
+	}
+	return result
+}
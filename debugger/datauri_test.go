@@ -0,0 +1,39 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+// svgPatcher removes onload handlers from embedded SVG images
+var svgPatcher = modules.ProcessorModule{
+	Registry: modules.Registry{Name: "svg", DocTypes: []string{"DataURI"}},
+	Process: func(webData modules.WebData) (string, error) {
+		if webData.Type != "DataURI" || webData.Headers["Content-Type"] != "image/svg+xml" {
+			return webData.Body, nil
+		}
+		return strings.Replace(webData.Body, ` onload="track()"`, "", -1), nil
+	},
+}
+
+// <img src="data:image/svg+xml;base64,<svg onload="track()"></svg>">
+const dataURIPage = `<img src="data:image/svg+xml;base64,PHN2ZyBvbmxvYWQ9InRyYWNrKCkiPjwvc3ZnPg==">`
+
+func TestProcessDataURIs(t *testing.T) {
+	d := Debugger{
+		Options: Options{ProcessDataURIs: true},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{svgPatcher}},
+	}
+	raw, err := d.CallProcessors(modules.WebData{Body: dataURIPage, Type: "Document"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, raw), `<img src="data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=">`), true)
+}
+
+func TestDataURIsUntouchedByDefault(t *testing.T) {
+	d := Debugger{Modules: modules.Modules{Processors: []modules.ProcessorModule{svgPatcher}}}
+	raw, err := d.CallProcessors(modules.WebData{Body: dataURIPage, Type: "Document"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, raw), dataURIPage), true)
+}
@@ -2,23 +2,34 @@
 package debugger
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/DharmaOfCode/gorp/modules"
 	"github.com/fsnotify/fsnotify"
 	"github.com/wirepair/gcd"
 	"github.com/wirepair/gcd/gcdapi"
+	"github.com/wirepair/gcd/gcdmessage"
 	"io/ioutil"
 	"log"
+	"math/rand"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 var(
 	runtimeScriptParams *gcdapi.RuntimeCompileScriptParams
+
+	errProcessorTimeout = errors.New("processor timed out")
 )
 
 
@@ -32,8 +43,134 @@ type Debugger struct {
 	Target      	*gcd.ChromeTarget
 	Modules     	modules.Modules
 	XHRBreakPoints  []string
+	Findings        *modules.Findings
 
 	MessageChan     chan string
+
+	beforeSend   func(modules.WebData, string) string
+	onNavigation func(url string)
+	console      console
+	transform    func(modules.WebData) (string, error)
+	net          networkDomain    // Network domain of the target, replaceable for testing
+	pg           pageDomain       // Page domain of the target, replaceable for testing
+	dbg          debuggerDomain   // Debugger domain of the target, replaceable for testing
+	rt           runtimeDomain    // Runtime domain of the target, replaceable for testing
+	bp           breakpointDomain // Debugger domain of the target used for breakpoints, replaceable for testing
+	ck           cookieDomain     // Network domain of the target used for cookies, replaceable for testing
+	em           emulationDomain  // Emulation domain of the target, replaceable for testing
+	ev           eventSource      // Events of the target, replaceable for testing
+	frames       map[string]*gcdapi.PageFrame
+	framesLock   sync.RWMutex
+	framesOnce   sync.Once
+	mocks        []mockResponse
+	mocksLock    sync.RWMutex
+	patterns     []*gcdapi.NetworkRequestPattern // Patterns intercepted on top of those of mocks, nil until interception is set up
+	pins         []*pinnedResponses
+	pinsLock     sync.Mutex
+	resendRoots  *x509.CertPool // Authorities trusted by ResendRequest, the system ones when nil, replaceable for testing
+	inflight     chan struct{}
+	inflightOnce sync.Once
+	workers      map[string]bool
+	workersLock  sync.RWMutex
+	stats        map[string]*moduleStats
+	statsLock    sync.Mutex
+	scripts      []ParsedScript
+	scriptsLock  sync.Mutex
+	scriptsOnce  sync.Once
+	evalHooks    bool
+
+	initiators     map[string]modules.Initiator
+	initiatorOrder []string
+	initiatorsLock sync.RWMutex
+
+	redirects     map[string][]string // Urls each request was redirected from, by request id
+	redirectOrder []string
+	redirectsLock sync.RWMutex
+
+	sentUrls     map[string]string // Urls of the requests sent, by request id, for loading failures
+	sentOrder    []string
+	failedByUs   map[string]string // Why gorp failed requests on purpose, by request id
+	failuresLock sync.Mutex
+
+	connections       map[string]connection // Connections responses were served over, by request id
+	connectionOrder   []string
+	originConnections map[string]connection // Connection of the latest response from each origin
+	connectionsLock   sync.RWMutex
+
+	breakpointIds   map[string]bool
+	breakpointsLock sync.RWMutex
+	pausedOnce      sync.Once
+	bindingsOnce    sync.Once
+
+	bodyRules         []compiledBodyRule // Body match rules compiled once, see compiledBodyRules
+	bodyRulesOnce     sync.Once
+	postBodyRules     []compiledPostBodyRule // Post body rules compiled once, see compiledPostBodyRules
+	postBodyRulesOnce sync.Once
+
+	onStall       func(idle time.Duration)
+	pendingSince  time.Time // When the oldest document request not followed by an interception was sent
+	stallReported bool      // Whether the current stall was reported, so that it is reported once
+	watchdogLock  sync.Mutex
+
+	loadWaiter  chan struct{} // Closed by the next load event, for the navigation waiting on it
+	screenshots bool          // Whether a screenshot is captured on every load event
+	loadsLock   sync.Mutex
+	loadsOnce   sync.Once
+
+	requests     recentRequests
+	recent       recentRequests
+	requestsLock sync.Mutex
+
+	endpoints     endpoints // API endpoints observed in XHR and fetch requests, for ExportOpenAPI
+	endpointsLock sync.Mutex
+
+	challenged     map[string]bool // Requests credentials were provided for
+	challengeOrder []string
+	challengesLock sync.Mutex
+
+	store    modules.SessionStore // Store web data is recorded to, nil when the session is not recorded
+	bridge   *proxyBridge
+	targets  targetSource // Source of the Chrome targets, replaceable for testing
+	stopOnce sync.Once
+
+	sampler     *rand.Rand // Draws the requests processed when Options.Sampling is set
+	samplerLock sync.Mutex
+
+	intercepted  int32 // Number of interceptions counted towards Options.MaxRequests
+	dumpedInputs int32 // Number of processor inputs saved to Options.ProcessorInputDir
+}
+
+// networkDomain is the subset of the Chrome Dev Tools Network domain used to handle intercepted requests and
+// to clear the browser state. It is implemented by gcdapi.Network.
+type networkDomain interface {
+	GetResponseBodyForInterception(interceptionId string) (string, bool, error)
+	ContinueInterceptedRequest(interceptionId string, errorReason string, rawResponse string, url string,
+		method string, postData string, headers map[string]interface{},
+		authChallengeResponse *gcdapi.NetworkAuthChallengeResponse) (*gcdmessage.ChromeResponse, error)
+	ClearBrowserCache() (*gcdmessage.ChromeResponse, error)
+	ClearBrowserCookies() (*gcdmessage.ChromeResponse, error)
+	SetRequestInterceptionWithParams(v *gcdapi.NetworkSetRequestInterceptionParams) (*gcdmessage.ChromeResponse, error)
+}
+
+// pageDomain is the subset of the Chrome Dev Tools Page domain used by the debugger.
+// It is implemented by gcdapi.Page.
+type pageDomain interface {
+	CaptureScreenshotWithParams(v *gcdapi.PageCaptureScreenshotParams) (string, error)
+	GetLayoutMetrics() (*gcdapi.PageLayoutViewport, *gcdapi.PageVisualViewport, *gcdapi.DOMRect, error)
+	NavigateWithParams(v *gcdapi.PageNavigateParams) (string, string, string, error)
+	HandleJavaScriptDialogWithParams(v *gcdapi.PageHandleJavaScriptDialogParams) (*gcdmessage.ChromeResponse, error)
+}
+
+// eventSource delivers the events of the target to subscribers.
+// It is implemented by gcd.ChromeTarget.
+type eventSource interface {
+	Subscribe(method string, callback func(*gcd.ChromeTarget, []byte))
+}
+
+// debuggerDomain is the subset of the Chrome Dev Tools Debugger domain used by the debugger.
+// It is implemented by gcdapi.Debugger.
+type debuggerDomain interface {
+	GetScriptSource(scriptId string) (string, string, error)
 }
 
 // Options defines the options used with the debugger, which is responsible for using the Chrome Dev Tools
@@ -41,17 +178,180 @@ type Debugger struct {
 type Options struct {
 	EnableConsole bool
 	Verbose       bool
+	Color         bool // Color console messages by kind, when the console is a terminal
 	Scope         string
-	LogFile		  string
+	LogFile       string
+
+	// TargetId is the id of an open tab to attach to, as returned by ListTargets, rather than opening a new one.
+	// This allows working on a tab that was set up by hand, such as a logged in session
+	TargetId string
+
+	ChromePath        string   // Path to the Chrome executable
+	UserDir           string   // Chrome user data directory
+	Port              string   // Chrome remote debugging port
+	Flags             []string // Additional Chrome command line flags
+	Device            string   // Name of one of the DevicePresets emulated once the target is set up, such as "iPhone"
+	CPUThrottle       float64  // Factor the CPU is slowed down by once the target is set up, such as 4. Off when 0
+	DialogAction      string   // DialogAccept or DialogDismiss JavaScript dialogs as they open, left to the page when empty
+	DialogPromptText  string   // Text entered into prompt dialogs accepted with DialogAction, their default text when empty
+	InterceptRequests bool     // Also intercept documents, XHR and fetch requests before they are sent
+	AnswerPreflights  bool     // Answer CORS preflights with permissive CORS headers instead of sending them
+	InterceptBinary   bool     // Also intercept images, media, fonts and fetches, for binary processors
+	ProcessDataURIs   bool     // Pass resources embedded as data: URIs to processors declaring the "DataURI" doc type
+	HookEval          bool     // Pass code given to eval and the Function constructor to inspectors
+	HookStorage       bool     // Pass writes to localStorage and sessionStorage to inspectors
+	CSPViolations     bool     // Pass CSP violations logged by the browser to inspectors and report them as findings
+	ScriptDumpDir     string   // Directory the sources of parsed scripts are saved to when the session stops
+	RecordFixtures    string   // Path of a fixture file every intercepted request and response is recorded to
+	StateFile         string   // Path of the file module states are restored from on Start and saved to on Stop
+	OpenAPIFile       string   // Path of the OpenAPI document of the observed API endpoints written on Stop
+	UpstreamProxy     string   // Url of a proxy, such as Burp or mitmproxy, a copy of every intercepted request is sent through
+	ClientCert        string   // Path of a PEM client certificate presented by ResendRequest and the upstream proxy bridge
+	ClientKey         string   // Path of the PEM private key of ClientCert
+	DedupFindings     bool     // Report each finding once, with the number of times it was found
+	ClearRequestLog   bool     // Clear the log returned by RequestLog every time the top frame navigates
+	RequestLogSize    int      // Number of requests kept for RequestLog, 10000 when 0
+	RecentRequests    int      // Number of requests kept for RecentRequests, 100 when 0
+	NavigationRetries int      // Number of times Navigate retries a navigation that failed or did not load in time
+
+	// NavigationTimeout is how long Navigate waits for a page to load before the attempt counts as failed.
+	// 30 seconds when 0
+	NavigationTimeout time.Duration
+
+	// ResetFindingsOnNavigation discards the findings and changes collected so far every time the top frame
+	// navigates, so that they only cover the current page
+	ResetFindingsOnNavigation bool
+
+	// NavigationProcessors are the names of the only processors run on documents loaded by navigations, and on
+	// their requests. All processors run on them when empty
+	NavigationProcessors []string
+
+	// RecordRawResponses keeps responses as returned by Chrome in WebData.Raw, so that fixtures hold them
+	// alongside the decoded bodies
+	RecordRawResponses bool
+
+	// SessionStore is the store every intercepted request and response is recorded to, in place of the fixture
+	// file of RecordFixtures, so that exporters can read the whole session back from it. Stop closes it
+	SessionStore modules.SessionStore
+
+	// RecompressResponse gzips rebuilt response bodies before they are sent back to Chrome, which helps with
+	// large bodies over a slow connection. Callbacks registered with OnBeforeSend see the compressed body
+	RecompressResponse bool
+
+	// KeepDateHeader forwards the Date header of rebuilt responses as sent by the server, rather than setting it
+	// to the time the response was rebuilt
+	KeepDateHeader bool
+
+	// InterceptManifests also intercepts web app manifests, which are passed to modules as "Manifest" so that
+	// processors can rewrite them, to change their start_url or scope for instance
+	InterceptManifests bool
+
+	// ProcessInlineScripts passes the inline scripts of HTML documents to processors declaring the "Script" doc
+	// type. Processors that also declare "Document" only see them as part of the document
+	ProcessInlineScripts bool
+
+	FrameFilter     []string // Only process requests made by frames matching these frame ids, frame names or "top"
+	InitiatorFilter []string // Only process requests with these initiator types, such as "script" or "parser"
+	ProtocolFilter  []string // Only process requests to origins served over these protocols, such as "http/1.1", "h2" or "unknown"
+	MaxInflight     int      // Maximum number of intercepted requests handled at once, others wait their turn. 0 for no limit
+
+	// FirstPartyOnly forwards requests to other origins than that of the document loaded in the top frame
+	// untouched. With FirstPartySubdomains, requests to the domain of the document, without its www. prefix, and
+	// to its subdomains are first party as well, whatever their scheme and port
+	FirstPartyOnly       bool
+	FirstPartySubdomains bool
+
+	// MaxRequests stops the session once this many requests were intercepted. Requests intercepted both before
+	// they are sent and once the response arrives count twice. 0 for no limit
+	MaxRequests int
+	MaxDuration time.Duration // Stops the session once it has been running for this long. 0 for no limit
+
+	// Sampling only processes a random SampleRate fraction, between 0 and 1, of the requests that would be
+	// processed, forwarding the others untouched. They are still logged and counted towards MaxRequests.
+	// SampleSeed seeds the random draws so that a sample can be reproduced, a random seed is used when 0
+	Sampling   bool
+	SampleRate float64
+	SampleSeed int64
+
+	// InterceptionWatchdog warns when a document request was sent this long ago and nothing has been
+	// intercepted since, which happens when interception stopped working or its patterns miss the page.
+	// Callbacks registered with OnInterceptionStall are run as well. 0 disables the watchdog
+	InterceptionWatchdog time.Duration
+
+	// ProcessorInputDir is a directory the web data a processor fails on is saved to, as a fixture file, so that
+	// the failure can be reproduced in a unit test. DumpProcessorInputs saves the input of every processor run
+	ProcessorInputDir   string
+	DumpProcessorInputs bool
+
+	// ProcessorTimeout is how long a processor may take on a single body before it is skipped and the body
+	// passed on unchanged. 0 for no limit
+	ProcessorTimeout time.Duration
+
+	// SafeMode discards the output of a processor when it turns a body that parsed as HTML or JSON into one that
+	// no longer does, so that a buggy processor cannot break the page. SafeModeScripts also runs a basic syntax
+	// check on scripts
+	SafeMode        bool
+	SafeModeScripts bool
+
+	// ScriptReplacements maps url patterns to local files served in place of matching scripts. Files are
+	// read on every request so that edits are picked up live
+	ScriptReplacements map[string]string
+
+	// OverlayDir is a directory whose files are served in place of the responses to the urls with a matching
+	// path, as a static file server would, for urls in Scope. Files are read on every request
+	OverlayDir string
+	// OverlayHost restricts the overlay to the urls of a single host, such as "example.com", rather than to
+	// those in Scope
+	OverlayHost string
+
+	Screenshots         bool   // Capture a screenshot every time a page loads
+	ScreenshotDir       string // Directory where screenshots are saved
+	FullPageScreenshots bool   // Capture the whole page rather than the viewport
+
+	ContentTypeOverrides []ContentTypeOverride // Content-Type changes applied to rebuilt responses
+
+	QueryOverrides []QueryOverride // Query string changes applied to requests intercepted at the "Request" stage
+	PostBodyRules  []PostBodyRule  // Post data changes applied to requests intercepted at the "Request" stage
+
+	HeaderConditions []HeaderCondition // Request headers processors require before they run
+	BodyMatchRules   []BodyMatchRule   // Response body patterns processors require before they run
+
+	// ScopeMatcher, BlockMatcher and ProcessorMatchers select requests with arbitrary logic, for programs
+	// embedding the debugger. Requests not matching ScopeMatcher are forwarded untouched, requests matching
+	// BlockMatcher are failed, and processors named in ProcessorMatchers only run on web data their matcher
+	// selects. Scope and block matchers see requests before their body is fetched
+	ScopeMatcher      modules.Matcher
+	BlockMatcher      modules.Matcher
+	ProcessorMatchers map[string]modules.Matcher
+
+	FaultRules []FaultRule // Failures injected into matching responses, to test how the application handles them
+
+	// Latency holds the range of the random delay added to the responses of each resource type, such as "XHR",
+	// to simulate a real network. Responses of other types are not delayed
+	Latency map[string]LatencyRange
+
+	Credentials []Credentials // Credentials provided when asked for HTTP authentication, rather than prompting for them
+
+	AllowHeaders []string // Only forward these response headers when rebuilding responses, all when empty
+	DenyHeaders  []string // Never forward these response headers when rebuilding responses
+
+	// ProcessWorkerScripts passes service worker scripts to modules. They are forwarded untouched by default,
+	// since a broken worker keeps controlling the site until it is unregistered. Requests a service worker makes
+	// or answers are handled like any other
+	ProcessWorkerScripts bool
 }
 
 // StartTarget initializes  Chrome and sets up the Chrome Dev Tools protocol targets so that events can be intercepted
-func (d *Debugger) StartTarget() {
+func (d *Debugger) StartTarget() error {
 	target, err := d.ChromeProxy.NewTab()
 	if err != nil {
-		log.Fatalf("error getting new tab: %s\n", err)
+		return fmt.Errorf("error getting new tab: %s", err)
 	}
+	return d.enableTarget(target)
+}
 
+// enableTarget enables the domains used by the debugger on target and makes it the debugger target
+func (d *Debugger) enableTarget(target *gcd.ChromeTarget) error {
 	target.DebugEvents(d.Options.Verbose)
 	target.DOM.Enable()
 	target.Console.Enable()
@@ -63,84 +363,488 @@ func (d *Debugger) StartTarget() {
 		MaxResourceBufferSize: -1,
 	}
 	if _, err := target.Network.EnableWithParams(networkParams); err != nil {
-		log.Fatal("[-] Error enabling network!")
+		return fmt.Errorf("error enabling network: %s", err)
 	}
 	d.Target = target
+	return nil
 }
 
 // SetupRequestInterception enables request interception using the specific params
-func (d *Debugger) SetupRequestInterception(params *gcdapi.NetworkSetRequestInterceptionParams) {
+func (d *Debugger) SetupRequestInterception(params *gcdapi.NetworkSetRequestInterceptionParams) error {
 	log.Println("[+] Setting up request interception")
-	if _, err := d.Target.Network.SetRequestInterceptionWithParams(params); err != nil {
-		log.Println("[-] Unable to setup request interception!", err)
+	d.mocksLock.Lock()
+	d.patterns = params.Patterns
+	patterns := &gcdapi.NetworkSetRequestInterceptionParams{Patterns: d.withMockPatterns(params.Patterns)}
+	d.mocksLock.Unlock()
+	if _, err := d.network().SetRequestInterceptionWithParams(patterns); err != nil {
+		return fmt.Errorf("unable to setup request interception: %s", err)
 	}
 
-	d.Target.Subscribe("Network.requestIntercepted", func(target *gcd.ChromeTarget, v []byte) {
+	if len(d.Options.FrameFilter) > 0 || d.Options.ClearRequestLog || d.Options.ResetFindingsOnNavigation ||
+		d.Options.FirstPartyOnly || d.onNavigation != nil {
+		d.trackFrames()
+	}
+	d.trackServiceWorkers()
+	d.trackInitiators()
+	d.trackProtocols()
 
+	d.Target.Subscribe("Network.requestIntercepted", func(target *gcd.ChromeTarget, v []byte) {
 		msg := &gcdapi.NetworkRequestInterceptedEvent{}
 		err := json.Unmarshal(v, msg)
 		if err != nil {
 			log.Fatalf("error unmarshalling event data: %v\n", err)
 		}
-		iid := msg.Params.InterceptionId
-		reason := msg.Params.ResponseErrorReason
-		rtype := msg.Params.ResourceType
-		responseHeaders := msg.Params.ResponseHeaders
-		url := msg.Params.Request.Url
-		method := msg.Params.Request.Method
+		d.handleInterception(msg)
+	})
+	return nil
+}
+
+// handleInterception passes intercepted requests and responses to modules and sends the result back to Chrome
+func (d *Debugger) handleInterception(msg *gcdapi.NetworkRequestInterceptedEvent) {
+	// delayed responses must not hold a slot other requests could use
+	d.addLatency(msg)
+	fault := d.drawFault(msg)
+	release := d.acquire()
+	defer release()
+
+	iid := msg.Params.InterceptionId
+	reason := msg.Params.ResponseErrorReason
+	rtype := msg.Params.ResourceType
+	responseHeaders := msg.Params.ResponseHeaders
+	url := msg.Params.Request.Url
+	method := msg.Params.Request.Method
+	if iid != "" {
+		d.interceptionSeen()
+		defer d.countInterception()
+	}
 
-		if msg.Params.IsNavigationRequest {
-			d.log("\n\n\n\n", nil)
-			d.log("[?] Navigation REQUEST", nil)
+	if msg.Params.IsNavigationRequest {
+		d.log("\n\n\n\n", nil)
+		d.logAs(msgNavigation, "[?] Navigation REQUEST", nil)
+	}
+	d.logAs(msgRequest, "[+] Request intercepted for "+url, nil)
+	if msg.Params.RedirectUrl != "" {
+		d.logAs(msgRedirect, "[+] Redirect from "+url+" to "+msg.Params.RedirectUrl, nil)
+	}
+	if reason != "" {
+		d.logAs(msgBlocked, "[-] Abort with reason "+reason, nil)
+	}
+	if iid != "" {
+		if d.startsNavigation(msg) {
+			d.navigated(url)
 		}
-		d.log("[+] Request intercepted for " + url, nil)
-		if reason != "" {
-			d.log("[-] Abort with reason " + reason, nil)
+		d.logRequest(msg)
+	}
+
+	// challenges can only be answered with credentials, or by leaving them to the browser
+	if iid != "" && msg.Params.AuthChallenge != nil {
+		d.answerAuthChallenge(msg)
+		return
+	}
+
+	// the next leg is intercepted with the same interception id, wherever it goes
+	if iid != "" && msg.Params.RedirectUrl != "" {
+		d.addRedirect(msg.Params.RequestId, url)
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
+
+	// mocked requests never reach the server, whichever filters would forward them
+	if mock := d.mockFor(url); iid != "" && mock != nil {
+		d.log("[+] Serving mocked response for " + url, nil)
+		d.continueRequest(iid, "", rawResponse(mock.status, mock.headers, mock.body), "", "")
+		return
+	}
+
+	if iid != "" && (d.Options.BlockMatcher != nil || d.Options.ScopeMatcher != nil) {
+		data := d.matchData(msg)
+		if d.blocked(data) {
+			d.logAs(msgBlocked, "[+] Blocking "+url, nil)
+			d.failOnPurpose(msg.Params.RequestId, "blocked")
+			d.continueRequest(iid, "BlockedByClient", "", "", "")
+			return
+		}
+		if !d.inMatcherScope(data) {
+			d.log("[+] Out of scope, forwarding "+url, nil)
+			d.continueRequest(iid, reason, "", "", "")
+			return
 		}
+	}
 
-		if iid != "" {
-			res, encoded, err := d.Target.Network.GetResponseBodyForInterception(iid)
-			if err != nil {
-				log.Println("[-] Unable to get intercepted response body!", err.Error())
-				target.Network.ContinueInterceptedRequest(iid, reason, "", "", "", "", nil, nil)
-			} else {
-				if encoded {
-					res, err = decodeBase64Response(res)
-					if err != nil {
-						log.Println("[-] Unable to decode body!")
-					}
-				}
-				webData := modules.WebData{
-					Body:    res,
-					Headers: responseHeaders,
-					Type:    rtype,
-					Url:     url,
-					Method:  method,
-				}
-				go d.CallInspectors(webData)
+	if iid != "" && !d.inFrameScope(msg.Params.FrameId) {
+		d.log("[+] Frame out of scope, forwarding "+url, nil)
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
 
-				if rtype != "" {
-					rawAlteredResponse, err := d.CallProcessors(webData)
-					if err != nil {
-						log.Println("[-] Unable to alter HTML")
-					}
+	if iid != "" && !d.isFirstParty(msg) {
+		d.log("[+] Third party request, forwarding "+url, nil)
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
 
-					log.Print("[+] Sending modified body\n\n\n")
+	initiator := d.initiatorFor(msg.Params.RequestId)
+	if iid != "" && !d.inInitiatorScope(initiator) {
+		d.log("[+] Initiator "+initiator.Type+" out of scope, forwarding "+url, nil)
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
 
-					_, err = d.Target.Network.ContinueInterceptedRequest(iid, reason, rawAlteredResponse, "", "", "", nil, nil)
-					if err != nil {
-						log.Println(err)
-					}
+	conn := d.connectionFor(msg.Params.RequestId, url)
+	if iid != "" && !d.inProtocolScope(conn.protocol) {
+		protocol := conn.protocol
+		if protocol == "" {
+			protocol = unknownProtocol
+		}
+		d.log("[+] Protocol "+protocol+" out of scope, forwarding "+url, nil)
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
+
+	workerScript := d.isWorkerScript(msg)
+	if iid != "" && workerScript && !d.Options.ProcessWorkerScripts {
+		d.log("[+] Service worker script, forwarding "+url, nil)
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
+
+	if raw := d.pinnedResponse(msg.Params.Request.Method, url); iid != "" && raw != "" {
+		d.log("[+] Serving pinned response for "+url, nil)
+		d.continueRequest(iid, "", raw, "", "")
+		return
+	}
+
+	if file := d.overlayFile(msg.Params.Request.Method, url); iid != "" && file != "" {
+		d.serveOverlay(iid, reason, url, file)
+		return
+	}
+
+	if iid != "" && d.Options.AnswerPreflights && isRequestStage(msg) && isPreflight(msg.Params.Request) {
+		d.answerPreflight(msg)
+		return
+	}
+
+	if iid != "" && isRequestStage(msg) && !interceptsRequestsOf(d.Options, msg.Params.ResourceType) {
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
+
+	if iid != "" && !d.sampled() {
+		d.log("[+] Not sampled, forwarding "+url, nil)
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
+
+	if iid != "" && isRequestStage(msg) {
+		d.interceptRequest(msg, workerScript)
+		return
+	}
+
+	if iid != "" && isEventStream(responseHeaders) {
+		d.log("[+] Event stream, forwarding "+url, nil)
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
+
+	if iid != "" && fault != nil {
+		d.injectFault(msg, fault)
+		return
+	}
+
+	if iid != "" && d.bridge != nil {
+		d.bridge.send(msg.Params.Request)
+	}
+
+	if iid != "" {
+		res, encoded, err := d.network().GetResponseBodyForInterception(iid)
+		if err != nil {
+			log.Println("[-] Unable to get intercepted response body!", err.Error())
+			d.continueRequest(iid, reason, "", "", "")
+		} else if isBinaryContent(rtype, responseHeaders) {
+			d.interceptBinary(msg, res, encoded)
+		} else {
+			raw := d.rawCapture(msg, res, encoded)
+			if encoded {
+				res, err = decodeBase64Response(res)
+				if err != nil {
+					log.Println("[-] Unable to decode body!")
+				}
+			}
+			webData := modules.WebData{
+				Body:            res,
+				Headers:         responseHeaders,
+				RequestHeaders:  msg.Params.Request.Headers,
+				Type:            responseType(rtype, responseHeaders),
+				Url:             url,
+				Method:          method,
+				RequestBody:     msg.Params.Request.PostData,
+				Status:          msg.Params.ResponseStatusCode,
+				Protocol:        conn.protocol,
+				TLSVersion:      conn.tlsVersion,
+				RequestId:       msg.Params.RequestId,
+				FrameId:         msg.Params.FrameId,
+				Navigation:      msg.Params.IsNavigationRequest,
+				WorkerScript:    workerScript,
+				Initiator:       initiator,
+				Redirects:       d.redirectsFor(msg.Params.RequestId),
+				ResponseCookies: modules.ParseResponseCookies(responseHeaders),
+				Raw:             raw,
+				Challenge:       responseChallenge(msg.Params.ResponseStatusCode, responseHeaders),
+				Findings:        d.Findings,
+			}
+			if path := d.scriptReplacement(webData); path != "" {
+				d.serveScriptReplacement(iid, reason, webData, path)
+				return
+			}
+
+			go d.CallInspectors(webData)
+			if d.store == nil && (rtype == "XHR" || rtype == "Fetch") {
+				d.recordEndpoint(webData)
+			}
+
+			if rtype != "" {
+				alteredBody, err := d.alterBody(webData)
+				if err != nil {
+					log.Println("[-] Unable to alter HTML")
+					d.continueRequest(iid, reason, "", "", "")
+				} else if d.forwardsUnchanged(webData, alteredBody) {
+					d.log("[+] Body unchanged, forwarding original response for "+url, nil)
+					d.pinSnapshot(webData, "")
+					d.continueRequest(iid, reason, "", "", "")
 				} else {
-					d.Target.Network.ContinueInterceptedRequest(iid, reason, "", "", "", "", nil, nil)
+					log.Print("[+] Sending modified body\n\n\n")
+					raw := d.buildResponse(webData, alteredBody)
+					d.pinSnapshot(webData, raw)
+					d.continueRequest(iid, reason, raw, "", "")
 				}
+			} else {
+				d.continueRequest(iid, reason, "", "", "")
 			}
+		}
+	} else {
+		d.continueRequest(iid, reason, "", "", "")
+	}
+}
+
+// interceptRequest handles requests intercepted before they are sent to the server. The request is passed
+// to inspectors and to the processors that declare the "Request" doc type, which may alter the post data.
+// Post data compressed with gzip or deflate is passed decompressed, and compressed again once altered.
+func (d *Debugger) interceptRequest(msg *gcdapi.NetworkRequestInterceptedEvent, workerScript bool) {
+	iid := msg.Params.InterceptionId
+	req := msg.Params.Request
+	url := req.Url
+	newUrl := ""
+	if u := d.overrideQuery(req.Url); u != req.Url {
+		d.log("[+] Rewriting "+req.Url+" to "+u, nil)
+		url, newUrl = u, u
+	}
+	body, encoding := req.PostData, ""
+	if lossyPostData(req.PostData) {
+		d.log("[-] Post data of "+req.Url+" was mangled by Chrome, not decompressing it", nil)
+	} else {
+		var err error
+		body, encoding, err = decodeRequestBody(req.Headers, req.PostData)
+		if err != nil {
+			d.log("[-] Unable to decompress request body of "+req.Url, err)
+		}
+	}
+	// a body left compressed is read-only, as altering it would break it
+	contentEncoding := strings.ToLower(strings.TrimSpace(headerValue(req.Headers, "content-encoding")))
+	readOnly := encoding == "" && contentEncoding != "" && contentEncoding != "identity"
+	conn := d.connectionFor(msg.Params.RequestId, req.Url)
+	webData := modules.WebData{
+		Body:           body,
+		Headers:        req.Headers,
+		RequestHeaders: req.Headers,
+		Type:           "Request",
+		Url:            url,
+		Method:         req.Method,
+		Protocol:       conn.protocol,
+		TLSVersion:     conn.tlsVersion,
+		RequestId:      msg.Params.RequestId,
+		FrameId:        msg.Params.FrameId,
+		Navigation:     msg.Params.IsNavigationRequest,
+		WorkerScript:   workerScript,
+		Initiator:      d.initiatorFor(msg.Params.RequestId),
+		Redirects:      d.redirectsFor(msg.Params.RequestId),
+		Findings:       d.Findings,
+	}
+
+	contentType := headerValue(req.Headers, "content-type")
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		form, err := modules.ParseMultipart(contentType, strings.NewReader(body), modules.DefaultFormMemory)
+		if err != nil {
+			d.log("[-] Unable to parse multipart body for "+req.Url, err)
+		} else {
+			webData.Multipart = form
+		}
+	}
+	if modules.IsProtobuf(contentType) {
+		if lossyPostData(body) {
+			d.log("[-] Post data of "+req.Url+" was mangled by Chrome, not decoding it as protobuf", nil)
 		} else {
-			d.Target.Network.ContinueInterceptedRequest(iid, reason, "", "", "", "", nil, nil)
+			webData.Protobuf = d.decodeProtobuf(req.Url, contentType, []byte(body))
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.CallInspectors(webData)
+	}()
+
+	postData := ""
+	if body != "" {
+		alteredBody, err := d.processRequestBody(webData)
+		if err != nil {
+			log.Println("[-] Unable to alter request body")
+		} else if alteredBody != body {
+			postData = alteredBody
 		}
+	}
+	if body != "" && len(d.Options.PostBodyRules) > 0 {
+		current := body
+		if postData != "" {
+			current = postData
+		}
+		if rewritten := d.rewritePostBody(url, contentType, current); rewritten != current {
+			d.log("[+] Rewriting post body of "+url, nil)
+			postData = rewritten
+		}
+	}
+	if postData != "" && encoding != "" {
+		// the server expects the body compressed as Content-Encoding says
+		if compressed, err := encodeRequestBody(postData, encoding); err != nil {
+			d.log("[-] Unable to compress request body of "+url+", sending it unchanged", err)
+			postData = ""
+		} else if !utf8.ValidString(compressed) {
+			d.log("[-] Compressed request body of "+url+" cannot be passed back to Chrome, sending it unchanged", nil)
+			postData = ""
+		} else {
+			postData = compressed
+		}
+	}
+	if postData != "" && readOnly {
+		d.log("[-] Request body of "+url+" could not be decompressed, sending it unchanged", nil)
+		postData = ""
+	}
+
+	if webData.Multipart != nil {
+		// temporary files for large parts can only go once every module is done with them
+		go func() {
+			wg.Wait()
+			webData.Multipart.RemoveAll()
+		}()
+	}
+
+	d.continueRequest(iid, "", "", newUrl, postData)
+}
+
+// trackFrames keeps track of navigated frames so that requests can be matched to frames by name. Frames are
+// recorded as soon as they are attached, so that requests made before their first navigation are known to
+// come from subframes. Navigations of the top frame, including those made with the history API or to another
+// fragment, are passed to the inspectors.
+func (d *Debugger) trackFrames() {
+	d.framesOnce.Do(func() {
+		d.events().Subscribe("Page.frameAttached", func(target *gcd.ChromeTarget, v []byte) {
+			msg := &gcdapi.PageFrameAttachedEvent{}
+			err := json.Unmarshal(v, msg)
+			if err != nil {
+				log.Println("[-] Unable to read frame attached event", err)
+				return
+			}
+			d.attachFrame(msg.Params.FrameId, msg.Params.ParentFrameId)
+		})
+		d.events().Subscribe("Page.frameNavigated", func(target *gcd.ChromeTarget, v []byte) {
+			msg := &gcdapi.PageFrameNavigatedEvent{}
+			err := json.Unmarshal(v, msg)
+			if err != nil {
+				log.Println("[-] Unable to read frame navigation event", err)
+				return
+			}
+			d.addFrame(msg.Params.Frame)
+			if f := msg.Params.Frame; f != nil && f.ParentId == "" {
+				go d.inspectNavigation(f.Url + f.UrlFragment)
+			}
+		})
+		d.events().Subscribe("Page.navigatedWithinDocument", func(target *gcd.ChromeTarget, v []byte) {
+			msg := &gcdapi.PageNavigatedWithinDocumentEvent{}
+			err := json.Unmarshal(v, msg)
+			if err != nil {
+				log.Println("[-] Unable to read navigation event", err)
+				return
+			}
+			if top := d.topFrame(); top != nil && top.Id == msg.Params.FrameId {
+				go d.inspectNavigation(msg.Params.Url)
+			}
+		})
 	})
 }
 
+func (d *Debugger) addFrame(frame *gcdapi.PageFrame) {
+	if frame == nil {
+		return
+	}
+	d.framesLock.Lock()
+	defer d.framesLock.Unlock()
+	if d.frames == nil {
+		d.frames = make(map[string]*gcdapi.PageFrame)
+	}
+	d.frames[frame.Id] = frame
+}
+
+// attachFrame records a frame that has not navigated yet, its name and url are only known once it does
+func (d *Debugger) attachFrame(frameId, parentId string) {
+	d.framesLock.Lock()
+	defer d.framesLock.Unlock()
+	if d.frames == nil {
+		d.frames = make(map[string]*gcdapi.PageFrame)
+	}
+	if _, ok := d.frames[frameId]; !ok {
+		d.frames[frameId] = &gcdapi.PageFrame{Id: frameId, ParentId: parentId}
+	}
+}
+
+// topFrame returns the main frame of the page, or nil if no navigation has been seen yet
+func (d *Debugger) topFrame() *gcdapi.PageFrame {
+	d.framesLock.RLock()
+	defer d.framesLock.RUnlock()
+	for _, f := range d.frames {
+		if f.ParentId == "" {
+			return f
+		}
+	}
+	return nil
+}
+
+// inFrameScope reports whether requests made by the given frame should be processed according to Options.FrameFilter
+func (d *Debugger) inFrameScope(frameId string) bool {
+	if len(d.Options.FrameFilter) == 0 {
+		return true
+	}
+	d.framesLock.RLock()
+	frame := d.frames[frameId]
+	d.framesLock.RUnlock()
+	for _, f := range d.Options.FrameFilter {
+		if f == frameId {
+			return true
+		}
+		if frame == nil {
+			// subframes are attached before they make any request, so this is the main frame navigating
+			// for the first time
+			if f == "top" && frameId != "" {
+				return true
+			}
+			continue
+		}
+		if (frame.Name != "" && f == frame.Name) || (f == "top" && frame.ParentId == "") {
+			return true
+		}
+	}
+	return false
+}
+
 func (d *Debugger) SetupDOMDebugger(){
 	for _, bp := range d.XHRBreakPoints{
 		b := &gcdapi.DOMDebuggerSetXHRBreakpointParams{
@@ -179,6 +883,9 @@ func GetUserScripts(path string) (string, error) {
 	return scripts , nil
 }
 
+// UpdateScriptsOnLoad injects the user scripts at path into every new document, and injects them again
+// whenever the file changes. It returns once the scripts are injected, so that it can be called before
+// navigating, and watches the file until the session is stopped.
 func (d *Debugger) UpdateScriptsOnLoad(path string){
 	//Initial load
 	scripts, err := GetUserScripts(path)
@@ -188,19 +895,14 @@ func (d *Debugger) UpdateScriptsOnLoad(path string){
 
 	sid := d.InjectScriptAsPageObject(&scripts)
 
-	if err != nil{
-		log.Println("[-] Unable to setup script injector")
-	}
-
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		fmt.Println("ERROR", err)
+		return
 	}
-	defer watcher.Close()
-
-	done := make(chan bool)
 
 	go func() {
+		defer watcher.Close()
 		for {
 			select {
 			// watch for events
@@ -218,55 +920,218 @@ func (d *Debugger) UpdateScriptsOnLoad(path string){
 				// watch for errors
 			case err := <-watcher.Errors:
 				fmt.Println("ERROR", err)
+			case <-d.Done:
+				return
 			}
 		}
 	}()
 
-
 	if err := watcher.Add(path); err != nil {
 		fmt.Println("ERROR", err)
 	}
-
-	<-done
 }
 
 // CallProcessors alters the body of web responses using the selected processors
 func (d *Debugger) CallProcessors(data modules.WebData) (string, error) {
+	alteredBody, err := d.alterBody(data)
+	if err != nil {
+		return "", err
+	}
+	return d.buildResponse(data, alteredBody), nil
+}
+
+// alterBody runs the processors on the body of data, then on the inline scripts of HTML documents when
+// Options.ProcessInlineScripts is set and the resources it embeds as data URIs when Options.ProcessDataURIs is set
+func (d *Debugger) alterBody(data modules.WebData) (string, error) {
 	alteredBody, err := d.processBody(data)
 	if err != nil {
 		return "", err
 	}
+	if d.Options.ProcessInlineScripts && isHTMLDocument(data) {
+		alteredBody = d.processInlineScripts(data, alteredBody)
+	}
+	if d.Options.ProcessDataURIs {
+		alteredBody = d.processDataURIs(data, alteredBody)
+	}
+	return alteredBody, nil
+}
 
+// forwardsUnchanged reports whether a response can be forwarded as sent by the server, keeping its original
+// Content-Encoding, because neither its body nor its headers would change once rebuilt
+func (d *Debugger) forwardsUnchanged(data modules.WebData, alteredBody string) bool {
+	return alteredBody == data.Body && d.beforeSend == nil && len(d.Options.AllowHeaders) == 0 &&
+		len(d.Options.DenyHeaders) == 0 && d.contentTypeFor(data, alteredBody) == ""
+}
+
+// buildResponse rebuilds the response described by data with a new body.
+// It returns the raw response, base64 encoded
+func (d *Debugger) buildResponse(data modules.WebData, alteredBody string) string {
+	encoding := ""
+	if d.Options.RecompressResponse && alteredBody != "" {
+		if compressed, err := gzipBody(alteredBody); err != nil {
+			d.log("[-] Unable to compress body for "+data.Url, err)
+		} else {
+			alteredBody, encoding = compressed, "gzip"
+		}
+	}
+	contentType := d.contentTypeFor(data, alteredBody)
+	status := data.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
 	alteredHeader := ""
-	for k, v := range data.Headers {
+	hasLength := false
+	hasDate := false
+	hasType := false
+	dropped := false
+	for _, k := range sortedHeaderNames(data.Headers) {
+		v := data.Headers[k]
+		if strings.HasPrefix(k, ":") {
+			// HTTP/2 pseudo-headers have no place in an HTTP/1.1 header block, only the status is kept when
+			// Chrome did not report it
+			if k == ":status" && data.Status == 0 {
+				if s, err := strconv.Atoi(fmt.Sprint(v)); err == nil {
+					status = s
+				}
+			}
+			continue
+		}
+		if strings.EqualFold(k, "transfer-encoding") {
+			// the rebuilt body is never chunked, whatever headers are allowed through
+			dropped = true
+			continue
+		}
+		if !d.forwardHeader(k, data.Headers) {
+			continue
+		}
+		// Content-Length and Date are written once below, whatever the casing and number of copies received
 		switch strings.ToLower(k) {
 		case "content-length":
-			v = strconv.Itoa(len(alteredBody))
-			break
+			hasLength = true
+			continue
 		case "date":
-			v = fmt.Sprintf("%s", time.Now().Format(time.RFC3339))
-			break
+			// the first copy is kept as is when the date is not rewritten
+			if hasDate || !d.Options.KeepDateHeader {
+				hasDate = true
+				continue
+			}
+			hasDate = true
+		case "content-type":
+			// only the first copy is kept, with the value it is overridden with if any
+			if hasType {
+				continue
+			}
+			hasType = true
+			if contentType != "" {
+				v = contentType
+			}
+		case "content-encoding":
+			// Chrome hands bodies over decoded, the original encoding no longer applies
+			continue
 		}
 		alteredHeader += k + ": " + v.(string) + "\r\n"
 	}
+	if contentType != "" && !hasType {
+		alteredHeader += "Content-Type: " + contentType + "\r\n"
+	}
+	if hasDate && !d.Options.KeepDateHeader {
+		alteredHeader += "Date: " + time.Now().UTC().Format(http.TimeFormat) + "\r\n"
+	}
+	if hasLength || dropped {
+		// a chunked body now needs a length as well
+		alteredHeader += "Content-Length: " + strconv.Itoa(len(alteredBody)) + "\r\n"
+	}
+	if encoding != "" {
+		alteredHeader += "Content-Encoding: " + encoding + "\r\n"
+	}
 	alteredHeader += "\r\n"
 
-	rawAlteredResponse := base64.StdEncoding.EncodeToString([]byte("HTTP/1.1 200 OK" + "\r\n" + alteredHeader + alteredBody))
+	finalBody := fmt.Sprintf("HTTP/1.1 %d %s\r\n", status, http.StatusText(status)) + alteredHeader + alteredBody
+	if d.beforeSend != nil {
+		finalBody = d.beforeSend(data, finalBody)
+	}
+	return base64.StdEncoding.EncodeToString([]byte(finalBody))
+}
 
-	return rawAlteredResponse, nil
+// OnBeforeSend registers a callback that receives the fully assembled response, status line and headers
+// included, right before it is sent to the browser. The value returned by fn is what gets sent.
+func (d *Debugger) OnBeforeSend(fn func(webData modules.WebData, finalBody string) string) {
+	d.beforeSend = fn
 }
 
-// CallInspectors executes inspectors in a gorp session
+// SetBodyTransform registers a callback that alters response bodies after every processor has run, without
+// having to write a module. fn receives the body as left by the processors
+func (d *Debugger) SetBodyTransform(fn func(webData modules.WebData) (string, error)) {
+	d.transform = fn
+}
+
+// CallInspectors executes inspectors in a gorp session. Inspectors run concurrently and
+// CallInspectors returns once all of them are done
 func (d *Debugger) CallInspectors(webData modules.WebData) {
+	if d.store != nil {
+		if err := d.store.Append(webData); err != nil {
+			d.log("[-] Unable to record fixture for "+webData.Url, err)
+		}
+	}
+	lines := isNDJSON(webData)
+	var wg sync.WaitGroup
 	for _, v := range d.Modules.Inspectors {
-		go v.Inspect(webData)
+		wg.Add(1)
+		go func(i modules.InspectorModule) {
+			defer wg.Done()
+			i.Inspect(webData)
+			if lines && i.InspectLine != nil {
+				d.inspectLines(i, webData)
+			}
+			d.recordModule(i.Registry.Name, "inspector", webData.Url, nil)
+		}(v)
 	}
+	wg.Wait()
 }
 
 func (d *Debugger) SetupFileLogger(){
+	if err := d.setupFileLogger(); err != nil {
+		panic(err)
+	}
+}
+
+// setupFileLogger opens Options.LogFile and starts writing log messages to it
+func (d *Debugger) setupFileLogger() error {
+	file, err := os.OpenFile(d.Options.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
 	d.MessageChan = make(chan string)
 
-	go d.fileLogger()
+	go d.fileLogger(file)
+	return nil
+}
+
+// gzipBody returns body compressed with gzip
+func gzipBody(body string) (string, error) {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// rawCapture returns the intercepted response as returned by Chrome when Options.RecordRawResponses is set,
+// nil otherwise
+func (d *Debugger) rawCapture(msg *gcdapi.NetworkRequestInterceptedEvent, body string, encoded bool) *modules.RawResponse {
+	if !d.Options.RecordRawResponses {
+		return nil
+	}
+	return &modules.RawResponse{
+		Status:  msg.Params.ResponseStatusCode,
+		Headers: msg.Params.ResponseHeaders,
+		Body:    body,
+		Base64:  encoded,
+	}
 }
 
 func decodeBase64Response(res string) (string, error) {
@@ -281,22 +1146,156 @@ func decodeBase64Response(res string) (string, error) {
 func (d *Debugger) processBody(data modules.WebData) (string, error) {
 	result := data
 	var err error
+	// body match rules are preconditions on the body as received, not as rewritten by earlier processors
+	gates := d.bodyGates(data.Body)
+	lines := isNDJSON(data)
 	for _, v := range d.Modules.Processors {
-		log.Println("[+] Running processor: " + v.Registry.Name)
-		result.Body, err = v.Process(result)
+		if v.Process == nil || !gateAllows(gates, v.Registry.Name) {
+			continue
+		}
+		if lines && v.ProcessLine != nil {
+			v = lineProcessor(v)
+		}
+		result.Body, err = d.runProcessor(v, result)
 		if err != nil {
 			return "", err
 		}
 	}
+	if d.transform != nil {
+		return d.transform(result)
+	}
 	return result.Body, nil
 }
 
-func (d *Debugger) fileLogger(){
-	file, err := os.OpenFile(d.Options.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// runProcessor runs a single processor and records what it changed in the change log
+func (d *Debugger) runProcessor(p modules.ProcessorModule, data modules.WebData) (string, error) {
+	if !d.conditionsMet(p, data) {
+		return data.Body, nil
+	}
+	log.Println("[+] Running processor: " + p.Registry.Name)
+	body, err := d.process(p, data)
+	d.dumpProcessorInput(p, data, err != nil)
+	if err == errProcessorTimeout {
+		d.log(fmt.Sprintf("[-] Processor %s stalled for over %s on %s, skipping it", p.Registry.Name,
+			d.Options.ProcessorTimeout, data.Url), nil)
+		return data.Body, nil
+	}
 	if err != nil {
-		panic(err)
+		return "", err
 	}
+	if err := d.checkOutput(data, body); err != nil {
+		d.log(fmt.Sprintf("[-] Processor %s broke the syntax of %s, discarding its changes: %s", p.Registry.Name,
+			data.Url, err), nil)
+		return data.Body, nil
+	}
+	if body == data.Body {
+		d.recordModule(p.Registry.Name, "processor", data.Url, nil)
+	} else {
+		change := modules.Change{
+			Processor:    p.Registry.Name,
+			Url:          data.Url,
+			RequestId:    data.RequestId,
+			Replacements: countReplacements(data.Body, body),
+			ByteDelta:    len(body) - len(data.Body),
+		}
+		d.Findings.RecordChange(change)
+		d.recordModule(p.Registry.Name, "processor", data.Url, &change)
+		d.log(fmt.Sprintf("[+] %s made %d replacement(s) to %s (%+d bytes)",
+			change.Processor, change.Replacements, change.Url, change.ByteDelta), nil)
+	}
+	return body, nil
+}
+
+// process calls the processor, giving up after Options.ProcessorTimeout. The processor goroutine is left to
+// finish on its own as there is no way to interrupt it.
+func (d *Debugger) process(p modules.ProcessorModule, data modules.WebData) (string, error) {
+	if d.Options.ProcessorTimeout <= 0 {
+		return p.Process(data)
+	}
+	type result struct {
+		body string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		body, err := p.Process(data)
+		done <- result{body, err}
+	}()
+	select {
+	case r := <-done:
+		return r.body, r.err
+	case <-time.After(d.Options.ProcessorTimeout):
+		return "", errProcessorTimeout
+	}
+}
 
+func (d *Debugger) processRequestBody(data modules.WebData) (string, error) {
+	result := data
+	var err error
+	for _, v := range d.Modules.Processors {
+		if v.Process == nil || !handlesDocType(v.Registry, "Request") {
+			continue
+		}
+		result.Body, err = d.runProcessor(v, result)
+		if err != nil {
+			return "", err
+		}
+	}
+	return result.Body, nil
+}
+
+// isRequestStage reports whether the event was intercepted before the request was sent, in which
+// case there is no response yet
+func isRequestStage(msg *gcdapi.NetworkRequestInterceptedEvent) bool {
+	return msg.Params.ResponseHeaders == nil && msg.Params.ResponseStatusCode == 0 &&
+		msg.Params.ResponseErrorReason == ""
+}
+
+// responseType returns the type passed to modules for a response of the given resource type. Scripts Chrome
+// does not load as such, like a script opened in a tab, are passed as "Script" when served with any of the
+// JavaScript content types. Web app manifests are passed as "Manifest" whatever the resource type Chrome reports
+// for them
+func responseType(resourceType string, headers map[string]interface{}) string {
+	contentType := headerValue(headers, "content-type")
+	if modules.IsManifest(contentType) {
+		return "Manifest"
+	}
+	switch resourceType {
+	case "Document", "Other":
+		if modules.IsJavaScript(contentType) {
+			return "Script"
+		}
+	}
+	return resourceType
+}
+
+// handlesDocType reports whether a module declares docType. Scripts can be declared as "Script", as the
+// modules.KindJavaScript content kind or as any of the JavaScript content types
+func handlesDocType(r modules.Registry, docType string) bool {
+	for _, t := range r.DocTypes {
+		if t == docType {
+			return true
+		}
+		if docType == "Script" && (t == modules.KindJavaScript || modules.IsJavaScript(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// headerValue returns the value of a header regardless of the casing used for its name
+func headerValue(headers map[string]interface{}, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			if s, ok := v.(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func (d *Debugger) fileLogger(file *os.File){
 	for l := range d.MessageChan{
 		if _, err := file.WriteString(l); err != nil {
 			panic(err)
@@ -304,10 +1303,60 @@ func (d *Debugger) fileLogger(){
 	}
 }
 
+// acquire waits until fewer than Options.MaxInflight intercepted requests are being handled. Requests
+// past the limit are queued rather than dropped, so that bursts do not flood the Chrome connection.
+// It returns a function that must be called once the request has been handled
+func (d *Debugger) acquire() func() {
+	if d.Options.MaxInflight <= 0 {
+		return func() {}
+	}
+	d.inflightOnce.Do(func() {
+		d.inflight = make(chan struct{}, d.Options.MaxInflight)
+	})
+	d.inflight <- struct{}{}
+	return func() { <-d.inflight }
+}
+
+// page returns the Page domain of the target
+func (d *Debugger) page() pageDomain {
+	if d.pg == nil {
+		return d.Target.Page
+	}
+	return d.pg
+}
+
+// events returns the source of the events of the target
+func (d *Debugger) events() eventSource {
+	if d.ev == nil {
+		return d.Target
+	}
+	return d.ev
+}
+
+// network returns the Network domain used to handle intercepted requests
+func (d *Debugger) network() networkDomain {
+	if d.net == nil {
+		return d.Target.Network
+	}
+	return d.net
+}
+
 func (d *Debugger) log(l string, err error){
+	kind := msgInfo
+	if err != nil || strings.HasPrefix(l, "[-]") {
+		kind = msgError
+	}
+	d.logAs(kind, l, err)
+}
+
+// logAs logs a message of the given kind, colored on the console when Options.Color is set
+func (d *Debugger) logAs(kind msgKind, l string, err error) {
 	//TODO: we should process a message Struct, with message + error
-	d.MessageChan <- l + "\n"
-	if err != nil{
+	if d.MessageChan != nil {
+		d.MessageChan <- l + "\n"
+	}
+	l = d.console.format(kind, l)
+	if err != nil {
 		log.Println(l, err)
 	} else {
 		log.Println(l)
@@ -0,0 +1,577 @@
+package debugger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func decodeRaw(t *testing.T, raw string) string {
+	b, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestOnBeforeSend(t *testing.T) {
+	d := Debugger{}
+	d.OnBeforeSend(func(webData modules.WebData, finalBody string) string {
+		return finalBody + "<!-- gorp -->"
+	})
+
+	raw, err := d.CallProcessors(modules.WebData{
+		Body:    "<html></html>",
+		Headers: map[string]interface{}{"Content-Type": "text/html"},
+		Type:    "Document",
+	})
+	assert.Equal(t, err, nil)
+
+	sent := decodeRaw(t, raw)
+	assert.Equal(t, strings.HasPrefix(sent, "HTTP/1.1 200 OK\r\n"), true)
+	assert.Equal(t, strings.HasSuffix(sent, "<html></html><!-- gorp -->"), true)
+}
+
+func TestSetBodyTransform(t *testing.T) {
+	d := Debugger{
+		Findings: &modules.Findings{},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{{
+			Registry: modules.Registry{Name: "greeter"},
+			Process: func(webData modules.WebData) (string, error) {
+				return strings.Replace(webData.Body, "</body>", "<p>hello</p></body>", 1), nil
+			},
+		}}},
+	}
+	d.SetBodyTransform(func(webData modules.WebData) (string, error) {
+		return strings.ToUpper(webData.Body), nil
+	})
+
+	body, err := d.processBody(modules.WebData{Body: "<body></body>", Type: "Document"})
+	assert.Equal(t, err, nil)
+	// the processor's own changes are uppercased too, so the callback ran last
+	assert.Equal(t, body, "<BODY><P>HELLO</P></BODY>")
+
+	d.SetBodyTransform(func(webData modules.WebData) (string, error) {
+		return "", errors.New("broken")
+	})
+	_, err = d.processBody(modules.WebData{Body: "<body></body>", Type: "Document"})
+	assert.Equal(t, err.Error(), "broken")
+}
+
+func TestPseudoHeaders(t *testing.T) {
+	d := Debugger{}
+	raw, err := d.CallProcessors(modules.WebData{
+		Body: `{"error":"not found"}`,
+		Headers: map[string]interface{}{
+			":status":      "404",
+			":path":        "/api/v1/user",
+			"content-type": "application/json",
+		},
+		Type: "XHR",
+	})
+	assert.Equal(t, err, nil)
+
+	sent := decodeRaw(t, raw)
+	assert.Equal(t, sent, "HTTP/1.1 404 Not Found\r\ncontent-type: application/json\r\n\r\n"+`{"error":"not found"}`)
+}
+
+func TestResponseStatus(t *testing.T) {
+	d := Debugger{}
+	raw, err := d.CallProcessors(modules.WebData{
+		Body:    `<a href="/login">Found</a>`,
+		Headers: map[string]interface{}{"Location": "/login"},
+		Type:    "Document",
+		Status:  302,
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.HasPrefix(decodeRaw(t, raw), "HTTP/1.1 302 Found\r\n"), true)
+}
+
+func TestRecompressResponse(t *testing.T) {
+	altered := strings.Repeat("<p>altered by gorp</p>", 100)
+	d := Debugger{Options: Options{RecompressResponse: true}}
+	raw := d.buildResponse(modules.WebData{
+		Headers: map[string]interface{}{
+			"Content-Type":     "text/html",
+			"Content-Encoding": "br",
+			"Content-Length":   "120",
+		},
+		Type: "Document",
+	}, altered)
+
+	res, err := http.ReadResponse(bufio.NewReader(strings.NewReader(decodeRaw(t, raw))), nil)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res.Header["Content-Encoding"], []string{"gzip"})
+	compressed, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, res.Header.Get("Content-Length"), strconv.Itoa(len(compressed)))
+	assert.Equal(t, len(compressed) < len(altered), true)
+
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	assert.Equal(t, err, nil)
+	body, _ := ioutil.ReadAll(r)
+	assert.Equal(t, string(body), altered)
+}
+
+func TestUnchangedResponseForwarded(t *testing.T) {
+	gzipped := interceptedEvent(t, `{"interceptionId":"1","requestId":"1","resourceType":"Script",
+		"request":{"url":"http://example.com/a.js","method":"GET"},"responseStatusCode":200,
+		"responseHeaders":{"Content-Type":"application/javascript","Content-Encoding":"gzip"}}`)
+	net := &mockNetwork{bodies: map[string]string{"1": "var a;"}}
+	var ran bool
+	d := Debugger{
+		net: net,
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{{
+			Registry: modules.Registry{Name: "noop"},
+			Process: func(webData modules.WebData) (string, error) {
+				ran = true
+				return webData.Body, nil
+			},
+		}}},
+	}
+
+	d.handleInterception(gzipped)
+	assert.Equal(t, ran, true)
+	// Chrome is left to send the response as received, still gzipped
+	assert.Equal(t, net.calls(), []continued{{InterceptionId: "1"}})
+
+	// stripping headers still requires rebuilding the response
+	net = &mockNetwork{bodies: map[string]string{"1": "var a;"}}
+	d.net = net
+	d.Options.DenyHeaders = []string{"X-Frame-Options"}
+	d.handleInterception(gzipped)
+	sent := decodeRaw(t, net.calls()[0].RawResponse)
+	assert.Equal(t, strings.Contains(sent, "Content-Encoding"), false)
+	assert.Equal(t, strings.HasSuffix(sent, "\r\n\r\nvar a;"), true)
+}
+
+func TestDuplicateHeaders(t *testing.T) {
+	d := Debugger{}
+	raw, err := d.CallProcessors(modules.WebData{
+		Body: "var a = 1;",
+		Headers: map[string]interface{}{
+			"Content-Type":   "application/javascript",
+			"Content-Length": "120",
+			"content-length": "120",
+			"CONTENT-LENGTH": "96",
+			"date":           "Mon, 01 Jan 2018 00:00:00 GMT",
+			"Date":           "Mon, 01 Jan 2018 00:00:00 GMT",
+		},
+		Type: "Script",
+	})
+	assert.Equal(t, err, nil)
+
+	sent := decodeRaw(t, raw)
+	assert.Equal(t, strings.Count(strings.ToLower(sent), "content-length:"), 1)
+	assert.Equal(t, strings.Count(strings.ToLower(sent), "date:"), 1)
+	res, err := http.ReadResponse(bufio.NewReader(strings.NewReader(sent)), nil)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res.Header["Content-Length"], []string{"10"})
+	assert.Equal(t, len(res.Header["Date"]), 1)
+}
+
+func TestDateHeader(t *testing.T) {
+	data := modules.WebData{
+		Body:    "var a = 1;",
+		Headers: map[string]interface{}{"Content-Type": "application/javascript", "Date": "Mon, 01 Jan 2018 00:00:00 GMT"},
+		Type:    "Script",
+	}
+
+	d := Debugger{}
+	res, err := http.ReadResponse(bufio.NewReader(strings.NewReader(decodeRaw(t, d.buildResponse(data, data.Body)))), nil)
+	assert.Equal(t, err, nil)
+	date, err := http.ParseTime(res.Header.Get("Date"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, time.Since(date) < time.Minute, true)
+	assert.Equal(t, strings.HasSuffix(res.Header.Get("Date"), " GMT"), true)
+
+	d.Options.KeepDateHeader = true
+	res, err = http.ReadResponse(bufio.NewReader(strings.NewReader(decodeRaw(t, d.buildResponse(data, data.Body)))), nil)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res.Header["Date"], []string{"Mon, 01 Jan 2018 00:00:00 GMT"})
+}
+
+func TestHeaderOrder(t *testing.T) {
+	data := modules.WebData{
+		Body: "<html></html>",
+		Headers: map[string]interface{}{
+			"x-frame-options":           "DENY",
+			"Content-Type":              "text/html",
+			"Cache-Control":             "no-cache",
+			"Strict-Transport-Security": "max-age=31536000",
+			"content-security-policy":   "default-src 'self'",
+			"Server":                    "nginx",
+			"Content-Length":            "42",
+		},
+		Type: "Document",
+	}
+
+	d := Debugger{}
+	first := decodeRaw(t, d.buildResponse(data, data.Body))
+	assert.Equal(t, first, "HTTP/1.1 200 OK\r\n"+
+		"Cache-Control: no-cache\r\n"+
+		"content-security-policy: default-src 'self'\r\n"+
+		"Content-Type: text/html\r\n"+
+		"Server: nginx\r\n"+
+		"Strict-Transport-Security: max-age=31536000\r\n"+
+		"x-frame-options: DENY\r\n"+
+		"Content-Length: 13\r\n\r\n<html></html>")
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, decodeRaw(t, d.buildResponse(data, data.Body)), first)
+	}
+}
+
+func TestHopByHopHeaders(t *testing.T) {
+	data := modules.WebData{
+		Body: "<html></html>",
+		Headers: map[string]interface{}{
+			"Connection":        "keep-alive, X-Session-Hint",
+			"Transfer-Encoding": "chunked",
+			"X-Session-Hint":    "1",
+			"Content-Type":      "text/html",
+		},
+		Type: "Document",
+	}
+
+	d := Debugger{}
+	raw, err := d.CallProcessors(data)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, decodeRaw(t, raw), "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nContent-Length: 13\r\n\r\n<html></html>")
+
+	d = Debugger{Options: Options{AllowHeaders: []string{"x-session-hint"}}}
+	raw, _ = d.CallProcessors(data)
+	assert.Equal(t, decodeRaw(t, raw), "HTTP/1.1 200 OK\r\nX-Session-Hint: 1\r\nContent-Length: 13\r\n\r\n<html></html>")
+
+	d = Debugger{Options: Options{DenyHeaders: []string{"content-type"}}}
+	raw, _ = d.CallProcessors(data)
+	assert.Equal(t, strings.Contains(decodeRaw(t, raw), "Content-Type"), false)
+
+	// the body is never sent chunked, even when Transfer-Encoding is allowed
+	d = Debugger{Options: Options{AllowHeaders: []string{"Transfer-Encoding", "Content-Type"}}}
+	raw, _ = d.CallProcessors(data)
+	assert.Equal(t, decodeRaw(t, raw), "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nContent-Length: 13\r\n\r\n<html></html>")
+}
+
+// countingProcessor returns a processor module that appends a marker to bodies and counts the urls it processed
+func countingProcessor(name string, processed *[]string) modules.ProcessorModule {
+	var mu sync.Mutex
+	return modules.ProcessorModule{
+		Registry: modules.Registry{Name: name, DocTypes: []string{"Document", "Script", "XHR"}},
+		Process: func(webData modules.WebData) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			*processed = append(*processed, webData.Url)
+			return webData.Body + "/*" + name + "*/", nil
+		},
+	}
+}
+
+func scriptResponse(t *testing.T, iid string, frameId string, url string) *gcdapi.NetworkRequestInterceptedEvent {
+	return interceptedEvent(t, `{"interceptionId":"`+iid+`","frameId":"`+frameId+`","resourceType":"Script",
+		"request":{"url":"`+url+`","method":"GET"},"responseStatusCode":200,
+		"responseHeaders":{"Content-Type":"application/javascript"}}`)
+}
+
+func TestFrameFilter(t *testing.T) {
+	var processed []string
+	net := &mockNetwork{bodies: map[string]string{"1": "var a;", "2": "var b;"}}
+	d := Debugger{
+		net:     net,
+		Options: Options{FrameFilter: []string{"checkout"}},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{countingProcessor("p", &processed)}},
+	}
+	d.addFrame(&gcdapi.PageFrame{Id: "top-frame", Url: "http://example.com/"})
+	d.addFrame(&gcdapi.PageFrame{Id: "iframe-1", ParentId: "top-frame", Name: "checkout"})
+
+	d.handleInterception(scriptResponse(t, "1", "top-frame", "http://example.com/a.js"))
+	d.handleInterception(scriptResponse(t, "2", "iframe-1", "http://example.com/b.js"))
+
+	assert.Equal(t, processed, []string{"http://example.com/b.js"})
+	calls := net.calls()
+	assert.Equal(t, len(calls), 2)
+	assert.Equal(t, calls[0].RawResponse, "")
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, calls[1].RawResponse), "var b;/*p*/"), true)
+}
+
+func TestFrameFilterAttachedFrames(t *testing.T) {
+	var processed []string
+	net := &mockNetwork{bodies: map[string]string{"1": "var a;", "2": "var b;"}}
+	d := Debugger{
+		net:     net,
+		Options: Options{FrameFilter: []string{"top"}},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{countingProcessor("p", &processed)}},
+	}
+	// neither frame has navigated yet, but the subframe has been attached to the main frame
+	d.attachFrame("iframe-1", "top-frame")
+
+	d.handleInterception(scriptResponse(t, "1", "top-frame", "http://example.com/a.js"))
+	d.handleInterception(scriptResponse(t, "2", "iframe-1", "http://ads.example.net/b.js"))
+
+	assert.Equal(t, processed, []string{"http://example.com/a.js"})
+	assert.Equal(t, d.inFrameScope("iframe-1"), false)
+	d.addFrame(&gcdapi.PageFrame{Id: "iframe-1", ParentId: "top-frame", Name: "ads"})
+	d.attachFrame("iframe-1", "top-frame")
+	assert.Equal(t, d.frames["iframe-1"].Name, "ads")
+}
+
+func TestProcessBodyChangeLog(t *testing.T) {
+	d := Debugger{
+		Findings: &modules.Findings{},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{
+			{
+				Registry: modules.Registry{Name: "FindReplace"},
+				Process: func(webData modules.WebData) (string, error) {
+					return strings.Replace(webData.Body, "isAdmin=false", "isAdmin=true", -1), nil
+				},
+			},
+			{
+				Registry: modules.Registry{Name: "Noop"},
+				Process: func(webData modules.WebData) (string, error) {
+					return webData.Body, nil
+				},
+			},
+		}},
+	}
+
+	_, err := d.processBody(modules.WebData{
+		Body:      "a(isAdmin=false);b(isAdmin=false);",
+		Url:       "http://example.com/app.js",
+		RequestId: "42",
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, d.Findings.Changes("42"), []modules.Change{{
+		Processor:    "FindReplace",
+		Url:          "http://example.com/app.js",
+		RequestId:    "42",
+		Replacements: 2,
+		ByteDelta:    -2,
+	}})
+	assert.Equal(t, len(d.Findings.Changes("43")), 0)
+}
+
+func TestProcessorTimeout(t *testing.T) {
+	var processed []string
+	d := Debugger{
+		Options: Options{ProcessorTimeout: 20 * time.Millisecond},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{
+			countingProcessor("before", &processed),
+			{
+				Registry: modules.Registry{Name: "Stalled"},
+				Process: func(webData modules.WebData) (string, error) {
+					time.Sleep(time.Second)
+					return "stalled", nil
+				},
+			},
+			countingProcessor("after", &processed),
+		}},
+	}
+
+	start := time.Now()
+	body, err := d.processBody(modules.WebData{Body: "var a;", Url: "http://example.com/a.js"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, "var a;/*before*//*after*/")
+	assert.Equal(t, time.Since(start) < time.Second, true)
+}
+
+func TestMockResponse(t *testing.T) {
+	net := &mockNetwork{}
+	d := Debugger{net: net}
+	d.MockResponse("*/api/v1/user*", 201, map[string]string{"Content-Type": "application/json"}, `{"role":"admin"}`)
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/v1/user?id=1","method":"GET"}}`))
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	assert.Equal(t, decodeRaw(t, calls[0].RawResponse), "HTTP/1.1 201 Created\r\n"+
+		"Content-Type: application/json\r\nContent-Length: 16\r\n\r\n"+`{"role":"admin"}`)
+}
+
+func TestMockResponseBeforeFilters(t *testing.T) {
+	net := &mockNetwork{}
+	d := Debugger{net: net, Options: Options{ProtocolFilter: []string{"h2"}, FrameFilter: []string{"top"}}}
+	d.MockResponse("*/api/v1/user*", 200, map[string]string{"Content-Type": "application/json"}, `{"role":"admin"}`)
+
+	// the protocol of the origin is not known yet and the request comes from a frame out of scope
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","frameId":"ad-frame","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/v1/user?id=1","method":"GET"}}`))
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, calls[0].RawResponse), `{"role":"admin"}`), true)
+}
+
+func TestMockResponsePatterns(t *testing.T) {
+	net := &mockNetwork{}
+	d := Debugger{net: net}
+	d.MockResponse("*/api/v1/user*", 200, nil, `{}`)
+	assert.Equal(t, len(net.patterns), 0)
+
+	// mocks are intercepted before the request is sent, whether they are added before or after interception is set up
+	d.patterns = InterceptionPatterns(Options{})
+	d.MockResponse("*/api/v1/admin*", 200, nil, `{}`)
+	n := len(d.patterns)
+	assert.Equal(t, len(net.patterns), n+2)
+	assert.Equal(t, *net.patterns[n], gcdapi.NetworkRequestPattern{UrlPattern: "*/api/v1/user*", InterceptionStage: "Request"})
+	assert.Equal(t, *net.patterns[n+1], gcdapi.NetworkRequestPattern{UrlPattern: "*/api/v1/admin*", InterceptionStage: "Request"})
+}
+
+func TestWildcardRegexp(t *testing.T) {
+	assert.Equal(t, wildcardRegexp("*example.com/*.js").MatchString("https://example.com/main.js"), true)
+	assert.Equal(t, wildcardRegexp("*example.com/*.js").MatchString("https://example.com/main.json"), false)
+	assert.Equal(t, wildcardRegexp("https://example.com/v?/").MatchString("https://example.com/v2/"), true)
+}
+
+func TestMaxInflight(t *testing.T) {
+	const burst = 500
+	net := &mockNetwork{bodies: map[string]string{}, delay: time.Millisecond}
+	d := Debugger{net: net, Options: Options{MaxInflight: 8}}
+
+	var events []*gcdapi.NetworkRequestInterceptedEvent
+	for i := 0; i < burst; i++ {
+		iid := strconv.Itoa(i)
+		net.bodies[iid] = "var a" + iid + ";"
+		events = append(events, scriptResponse(t, iid, "top", "http://example.com/"+iid+".js"))
+	}
+
+	var wg sync.WaitGroup
+	for _, msg := range events {
+		msg := msg
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.handleInterception(msg)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, len(net.calls()), burst)
+	assert.Equal(t, net.maxInflight <= 8, true)
+}
+
+func TestScriptReplacements(t *testing.T) {
+	f, err := ioutil.TempFile("", "gorp-replacement-*.js")
+	assert.Equal(t, err, nil)
+	defer os.Remove(f.Name())
+	f.WriteString("console.log('local');")
+	f.Close()
+
+	net := &mockNetwork{bodies: map[string]string{"1": "console.log('remote');", "2": "console.log('remote');"}}
+	d := Debugger{
+		net:     net,
+		Options: Options{ScriptReplacements: map[string]string{"*example.com/static/app*.js": f.Name()}},
+	}
+
+	d.handleInterception(scriptResponse(t, "1", "top", "https://example.com/static/app.3f2a.js"))
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, net.calls()[0].RawResponse), "\r\n\r\nconsole.log('local');"), true)
+
+	// edits to the local file are served on the next request
+	ioutil.WriteFile(f.Name(), []byte("console.log('edited');"), 0644)
+	d.handleInterception(scriptResponse(t, "2", "top", "https://example.com/static/app.3f2a.js"))
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, net.calls()[1].RawResponse), "\r\n\r\nconsole.log('edited');"), true)
+}
+
+func TestQueryOverrides(t *testing.T) {
+	net := &mockNetwork{}
+	d := Debugger{net: net, Options: Options{InterceptRequests: true, QueryOverrides: []QueryOverride{{
+		Url:    "*example.com/app*",
+		Set:    map[string]string{"debug": "1", "lang": "fr", "q": "a b&c"},
+		Remove: []string{"tracking"},
+	}}}}
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"Document",
+		"request":{"url":"https://example.com/app?id=1&lang=en&tracking=xyz&name=J%C3%B6rg","method":"GET"}}`))
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"2","resourceType":"Document",
+		"request":{"url":"https://other.com/app?id=1","method":"GET"}}`))
+
+	calls := net.calls()
+	assert.Equal(t, calls[0].Url, "https://example.com/app?id=1&lang=fr&name=J%C3%B6rg&debug=1&q=a+b%26c")
+	assert.Equal(t, calls[1].Url, "")
+}
+
+func TestJavaScriptContentTypes(t *testing.T) {
+	var types []string
+	net := &mockNetwork{bodies: map[string]string{"1": "var a;", "2": "var b;", "3": "var c;", "4": "<p>"}}
+	d := Debugger{
+		net: net,
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{{
+			Registry: modules.Registry{Name: "scripts", DocTypes: []string{"Script"}},
+			Process: func(webData modules.WebData) (string, error) {
+				types = append(types, webData.Type)
+				if webData.Type != "Script" {
+					return webData.Body, nil
+				}
+				return webData.Body + "//processed", nil
+			},
+		}}},
+	}
+
+	for i, contentType := range []string{"text/javascript", "application/javascript; charset=utf-8", "application/x-javascript", "text/html"} {
+		iid := strconv.Itoa(i + 1)
+		d.handleInterception(interceptedEvent(t, `{"interceptionId":"`+iid+`","resourceType":"Document",
+			"request":{"url":"https://example.com/`+iid+`","method":"GET"},
+			"responseStatusCode":200,"responseHeaders":{"Content-Type":"`+contentType+`"}}`))
+	}
+
+	assert.Equal(t, types, []string{"Script", "Script", "Script", "Document"})
+	calls := net.calls()
+	for _, c := range calls[:3] {
+		assert.Equal(t, strings.HasSuffix(decodeRaw(t, c.RawResponse), "//processed"), true)
+	}
+}
+
+func TestHandlesDocType(t *testing.T) {
+	for _, docType := range []string{"Script", "javascript", "text/javascript", "application/x-javascript"} {
+		assert.Equal(t, handlesDocType(modules.Registry{DocTypes: []string{docType}}, "Script"), true, docType)
+	}
+	assert.Equal(t, handlesDocType(modules.Registry{DocTypes: []string{"text/html"}}, "Script"), false)
+	assert.Equal(t, handlesDocType(modules.Registry{DocTypes: []string{"javascript"}}, "Document"), false)
+}
+
+// minLengthInspector reads its options once, when it is registered
+type minLengthInspector struct {
+	defaultMin string
+	Options    []modules.Option
+	min        int
+}
+
+func (m *minLengthInspector) Init() {
+	m.Options = []modules.Option{{Name: "MinLength", Value: m.defaultMin, Required: true}}
+}
+
+func (m *minLengthInspector) Configure(options []modules.Option) error {
+	var err error
+	m.min, err = modules.GetModuleInt(options, "MinLength")
+	return err
+}
+
+func (m *minLengthInspector) GetOptions() []modules.Option { return m.Options }
+func (m *minLengthInspector) GetRegistry() modules.Registry {
+	return modules.Registry{Name: "MinLength"}
+}
+func (m *minLengthInspector) Inspect(webData modules.WebData) error { return nil }
+
+func TestAddInspectorConfigures(t *testing.T) {
+	d := Debugger{}
+	i := &minLengthInspector{defaultMin: "4"}
+	assert.Equal(t, d.AddInspector(i), nil)
+	assert.Equal(t, i.min, 4)
+	assert.Equal(t, len(d.Modules.Inspectors), 1)
+
+	// modules rejecting their options are left out
+	err := d.AddInspector(&minLengthInspector{defaultMin: "short"})
+	assert.Equal(t, err.Error(), `invalid options for module MinLength: option MinLength must be an integer, got "short"`)
+	assert.Equal(t, len(d.Modules.Inspectors), 1)
+}
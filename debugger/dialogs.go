@@ -0,0 +1,43 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"log"
+)
+
+// Actions taken on JavaScript dialogs with Options.DialogAction
+const (
+	DialogAccept  = "accept"  // Press OK, entering Options.DialogPromptText into prompts
+	DialogDismiss = "dismiss" // Press Cancel
+)
+
+// trackDialogs closes alert, confirm, prompt and beforeunload dialogs as they open, as told by
+// Options.DialogAction, so that they do not hold up the page
+func (d *Debugger) trackDialogs() {
+	d.Target.Subscribe("Page.javascriptDialogOpening", func(target *gcd.ChromeTarget, v []byte) {
+		msg := &gcdapi.PageJavascriptDialogOpeningEvent{}
+		err := json.Unmarshal(v, msg)
+		if err != nil {
+			log.Println("[-] Unable to read dialog opening event", err)
+			return
+		}
+		d.handleDialog(msg)
+	})
+}
+
+func (d *Debugger) handleDialog(msg *gcdapi.PageJavascriptDialogOpeningEvent) {
+	params := &gcdapi.PageHandleJavaScriptDialogParams{Accept: d.Options.DialogAction == DialogAccept}
+	if params.Accept && msg.Params.Type == "prompt" {
+		params.PromptText = d.Options.DialogPromptText
+		if params.PromptText == "" {
+			params.PromptText = msg.Params.DefaultPrompt
+		}
+	}
+	if _, err := d.page().HandleJavaScriptDialogWithParams(params); err != nil {
+		d.log("[-] Unable to "+d.Options.DialogAction+" "+msg.Params.Type+" dialog on "+msg.Params.Url, err)
+		return
+	}
+	d.log("[+] Dialog "+msg.Params.Type+" on "+msg.Params.Url+" handled with "+d.Options.DialogAction+": "+msg.Params.Message, nil)
+}
@@ -0,0 +1,45 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"testing"
+)
+
+// dialogOpening builds a Page.javascriptDialogOpening event from the JSON representation of its params
+func dialogOpening(t *testing.T, params string) *gcdapi.PageJavascriptDialogOpeningEvent {
+	msg := &gcdapi.PageJavascriptDialogOpeningEvent{}
+	if err := json.Unmarshal([]byte(`{"method":"Page.javascriptDialogOpening","Params":`+params+`}`), msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestDialogAction(t *testing.T) {
+	page := &mockPage{}
+	d := Debugger{pg: page, Options: Options{DialogAction: DialogDismiss}}
+	d.handleDialog(dialogOpening(t, `{"url":"https://example.com/","message":"Leave?","type":"confirm"}`))
+
+	assert.Equal(t, len(page.dialogs), 1)
+	assert.Equal(t, page.dialogs[0].Accept, false)
+
+	d.Options.DialogAction = DialogAccept
+	d.Options.DialogPromptText = "gorp"
+	d.handleDialog(dialogOpening(t, `{"url":"https://example.com/","message":"Name?","type":"prompt",
+		"defaultPrompt":"guest"}`))
+	d.Options.DialogPromptText = ""
+	d.handleDialog(dialogOpening(t, `{"url":"https://example.com/","message":"Name?","type":"prompt",
+		"defaultPrompt":"guest"}`))
+	d.handleDialog(dialogOpening(t, `{"url":"https://example.com/","message":"Hi","type":"alert"}`))
+
+	assert.Equal(t, len(page.dialogs), 4)
+	assert.Equal(t, *page.dialogs[1], gcdapi.PageHandleJavaScriptDialogParams{Accept: true, PromptText: "gorp"})
+	assert.Equal(t, *page.dialogs[2], gcdapi.PageHandleJavaScriptDialogParams{Accept: true, PromptText: "guest"})
+	assert.Equal(t, *page.dialogs[3], gcdapi.PageHandleJavaScriptDialogParams{Accept: true})
+}
+
+func TestValidateDialogAction(t *testing.T) {
+	err := Options{DialogAction: "ok"}.Validate()
+	assert.Equal(t, err.Error(), `invalid options: dialogAction "ok" is not one of accept or dismiss`)
+}
@@ -0,0 +1,138 @@
+package debugger
+
+import (
+	"fmt"
+	"github.com/wirepair/gcd/gcdapi"
+	"github.com/wirepair/gcd/gcdmessage"
+	"sort"
+	"strings"
+)
+
+// emulationDomain is the subset of the Chrome Dev Tools Emulation domain used to emulate devices and slower CPUs.
+// It is implemented by gcdapi.Emulation.
+type emulationDomain interface {
+	SetDeviceMetricsOverrideWithParams(v *gcdapi.EmulationSetDeviceMetricsOverrideParams) (*gcdmessage.ChromeResponse, error)
+	SetTouchEmulationEnabledWithParams(v *gcdapi.EmulationSetTouchEmulationEnabledParams) (*gcdmessage.ChromeResponse, error)
+	SetUserAgentOverrideWithParams(v *gcdapi.EmulationSetUserAgentOverrideParams) (*gcdmessage.ChromeResponse, error)
+	ClearDeviceMetricsOverride() (*gcdmessage.ChromeResponse, error)
+	SetCPUThrottlingRate(rate float64) (*gcdmessage.ChromeResponse, error)
+}
+
+// Device describes the screen and browser of a device to emulate
+type Device struct {
+	Width       int     // Width of the viewport in CSS pixels
+	Height      int     // Height of the viewport in CSS pixels
+	ScaleFactor float64 // Device pixel ratio, 0 to keep that of the screen
+	Mobile      bool    // Whether the page is rendered as on a mobile device, with touch events enabled
+	UserAgent   string  // User agent sent with requests and seen by scripts, empty to keep Chrome's
+}
+
+// DevicePresets are the devices that can be emulated by name with EmulateDevicePreset or Options.Device
+var DevicePresets = map[string]Device{
+	"iPhone": {
+		Width: 390, Height: 844, ScaleFactor: 3, Mobile: true,
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 15_0 like Mac OS X) AppleWebKit/605.1.15 " +
+			"(KHTML, like Gecko) Version/15.0 Mobile/15E148 Safari/604.1",
+	},
+	"iPad": {
+		Width: 820, Height: 1180, ScaleFactor: 2, Mobile: true,
+		UserAgent: "Mozilla/5.0 (iPad; CPU OS 15_0 like Mac OS X) AppleWebKit/605.1.15 " +
+			"(KHTML, like Gecko) Version/15.0 Mobile/15E148 Safari/604.1",
+	},
+	"Pixel": {
+		Width: 393, Height: 851, ScaleFactor: 2.75, Mobile: true,
+		UserAgent: "Mozilla/5.0 (Linux; Android 12; Pixel 5) AppleWebKit/537.36 " +
+			"(KHTML, like Gecko) Chrome/96.0.4664.45 Mobile Safari/537.36",
+	},
+}
+
+// EmulateDevice overrides the viewport of the page with one of width by height CSS pixels, and the user agent
+// when userAgent is not empty. Mobile devices also get touch events and mobile rendering, such as the meta
+// viewport tag being honored
+func (d *Debugger) EmulateDevice(width, height int, mobile bool, userAgent string) error {
+	return d.emulate(Device{Width: width, Height: height, Mobile: mobile, UserAgent: userAgent})
+}
+
+// EmulateDevicePreset emulates one of the DevicePresets, such as "iPhone" or "Pixel"
+func (d *Debugger) EmulateDevicePreset(name string) error {
+	device, ok := DevicePresets[name]
+	if !ok {
+		return fmt.Errorf("unknown device %q, known devices are %s", name, devicePresetNames())
+	}
+	return d.emulate(device)
+}
+
+// ClearDeviceEmulation restores the viewport of the browser window. Touch events and the user agent are
+// restored as well
+func (d *Debugger) ClearDeviceEmulation() error {
+	if _, err := d.emulation().ClearDeviceMetricsOverride(); err != nil {
+		return fmt.Errorf("unable to clear device metrics: %s", err)
+	}
+	return d.emulateBrowser(false, "")
+}
+
+// SetCPUThrottling slows the CPU down by rate, 4 running scripts about four times slower than they would
+// otherwise, to see how the page behaves on slower devices. A rate of 1 turns throttling off
+func (d *Debugger) SetCPUThrottling(rate float64) error {
+	if rate < 1 {
+		return fmt.Errorf("invalid CPU throttling rate %g, expected 1 or more", rate)
+	}
+	if _, err := d.emulation().SetCPUThrottlingRate(rate); err != nil {
+		return fmt.Errorf("unable to set CPU throttling: %s", err)
+	}
+	return nil
+}
+
+// emulate applies the screen and browser of device to the page
+func (d *Debugger) emulate(device Device) error {
+	if device.Width <= 0 || device.Height <= 0 {
+		return fmt.Errorf("invalid viewport size %dx%d", device.Width, device.Height)
+	}
+	_, err := d.emulation().SetDeviceMetricsOverrideWithParams(&gcdapi.EmulationSetDeviceMetricsOverrideParams{
+		Width:             device.Width,
+		Height:            device.Height,
+		DeviceScaleFactor: device.ScaleFactor,
+		Mobile:            device.Mobile,
+		ScreenWidth:       device.Width,
+		ScreenHeight:      device.Height,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to set device metrics: %s", err)
+	}
+	return d.emulateBrowser(device.Mobile, device.UserAgent)
+}
+
+// emulateBrowser turns touch events on for mobile devices and off otherwise, and overrides the user agent
+// when userAgent is not empty
+func (d *Debugger) emulateBrowser(mobile bool, userAgent string) error {
+	touch := &gcdapi.EmulationSetTouchEmulationEnabledParams{Enabled: mobile}
+	if mobile {
+		touch.MaxTouchPoints = 5
+	}
+	if _, err := d.emulation().SetTouchEmulationEnabledWithParams(touch); err != nil {
+		return fmt.Errorf("unable to set touch emulation: %s", err)
+	}
+	// an empty user agent restores the default one
+	if _, err := d.emulation().SetUserAgentOverrideWithParams(&gcdapi.EmulationSetUserAgentOverrideParams{UserAgent: userAgent}); err != nil {
+		return fmt.Errorf("unable to set user agent: %s", err)
+	}
+	return nil
+}
+
+// devicePresetNames returns the names of the device presets, sorted and comma separated
+func devicePresetNames() string {
+	names := make([]string, 0, len(DevicePresets))
+	for name := range DevicePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// emulation returns the Emulation domain used to emulate devices and slower CPUs
+func (d *Debugger) emulation() emulationDomain {
+	if d.em == nil {
+		return d.Target.Emulation
+	}
+	return d.em
+}
@@ -0,0 +1,96 @@
+package debugger
+
+import (
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"github.com/wirepair/gcd/gcdmessage"
+	"testing"
+)
+
+// mockEmulation stands in for the Chrome Emulation domain, recording the overrides set
+type mockEmulation struct {
+	metrics   []*gcdapi.EmulationSetDeviceMetricsOverrideParams
+	touch     []*gcdapi.EmulationSetTouchEmulationEnabledParams
+	userAgent []string
+	cleared   int
+	cpuRates  []float64
+	err       error
+}
+
+func (m *mockEmulation) SetDeviceMetricsOverrideWithParams(v *gcdapi.EmulationSetDeviceMetricsOverrideParams) (*gcdmessage.ChromeResponse, error) {
+	m.metrics = append(m.metrics, v)
+	return nil, m.err
+}
+
+func (m *mockEmulation) SetTouchEmulationEnabledWithParams(v *gcdapi.EmulationSetTouchEmulationEnabledParams) (*gcdmessage.ChromeResponse, error) {
+	m.touch = append(m.touch, v)
+	return nil, nil
+}
+
+func (m *mockEmulation) SetUserAgentOverrideWithParams(v *gcdapi.EmulationSetUserAgentOverrideParams) (*gcdmessage.ChromeResponse, error) {
+	m.userAgent = append(m.userAgent, v.UserAgent)
+	return nil, nil
+}
+
+func (m *mockEmulation) ClearDeviceMetricsOverride() (*gcdmessage.ChromeResponse, error) {
+	m.cleared++
+	return nil, nil
+}
+
+func (m *mockEmulation) SetCPUThrottlingRate(rate float64) (*gcdmessage.ChromeResponse, error) {
+	m.cpuRates = append(m.cpuRates, rate)
+	return nil, m.err
+}
+
+func TestEmulateDevice(t *testing.T) {
+	em := &mockEmulation{}
+	d := Debugger{em: em}
+	assert.Equal(t, d.EmulateDevice(360, 640, true, "TestAgent/1.0"), nil)
+
+	assert.Equal(t, len(em.metrics), 1)
+	assert.Equal(t, em.metrics[0].Width, 360)
+	assert.Equal(t, em.metrics[0].Height, 640)
+	assert.Equal(t, em.metrics[0].Mobile, true)
+	assert.Equal(t, *em.touch[0], gcdapi.EmulationSetTouchEmulationEnabledParams{Enabled: true, MaxTouchPoints: 5})
+	assert.Equal(t, em.userAgent, []string{"TestAgent/1.0"})
+
+	assert.Equal(t, d.EmulateDevicePreset("Pixel"), nil)
+	assert.Equal(t, em.metrics[1].Width, DevicePresets["Pixel"].Width)
+	assert.Equal(t, em.metrics[1].DeviceScaleFactor, 2.75)
+
+	assert.Equal(t, d.ClearDeviceEmulation(), nil)
+	assert.Equal(t, em.cleared, 1)
+	assert.Equal(t, em.touch[2].Enabled, false)
+	assert.Equal(t, em.userAgent[2], "")
+}
+
+func TestEmulateDeviceErrors(t *testing.T) {
+	em := &mockEmulation{}
+	d := Debugger{em: em}
+	assert.Equal(t, d.EmulateDevicePreset("Nokia").Error(), `unknown device "Nokia", known devices are Pixel, iPad, iPhone`)
+	assert.Equal(t, d.EmulateDevice(0, 640, false, "") != nil, true)
+	assert.Equal(t, len(em.metrics), 0)
+
+	em.err = errors.New("no target")
+	assert.Equal(t, d.EmulateDevice(800, 600, false, "").Error(), "unable to set device metrics: no target")
+	assert.Equal(t, len(em.touch), 0)
+
+	assert.Equal(t, Options{Device: "Nokia"}.Validate().Error(),
+		`invalid options: device "Nokia" is not one of Pixel, iPad, iPhone`)
+}
+
+func TestSetCPUThrottling(t *testing.T) {
+	em := &mockEmulation{}
+	d := Debugger{em: em}
+	assert.Equal(t, d.SetCPUThrottling(4), nil)
+	assert.Equal(t, d.SetCPUThrottling(1), nil)
+	assert.Equal(t, d.SetCPUThrottling(0.5).Error(), "invalid CPU throttling rate 0.5, expected 1 or more")
+	assert.Equal(t, em.cpuRates, []float64{4, 1})
+
+	em.err = errors.New("websocket closed")
+	assert.Equal(t, d.SetCPUThrottling(2).Error(), "unable to set CPU throttling: websocket closed")
+
+	err := Options{CPUThrottle: 0.5}.Validate()
+	assert.Equal(t, err.Error(), "invalid options: cpuThrottle must be 1 or more, got 0.5")
+}
@@ -0,0 +1,51 @@
+package debugger
+
+import (
+	"fmt"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd/gcdapi"
+	"strings"
+)
+
+// SetupEvalHooks reports code passed to eval and the Function constructor at runtime to inspectors, as web data
+// of type "Eval" whose body is the evaluated code and url that of the script that evaluated it. The code is taken
+// from the scripts Chrome parses, so eval is left untouched and direct calls still see the local scope.
+func (d *Debugger) SetupEvalHooks() error {
+	d.scriptsLock.Lock()
+	d.evalHooks = true
+	d.scriptsLock.Unlock()
+	return nil
+}
+
+// handleEvalScript passes a parsed script to inspectors if it was evaluated at runtime. Evaluated scripts have no
+// url and are parsed while another script runs, unlike the scripts gorp evaluates or injects itself.
+func (d *Debugger) handleEvalScript(msg *gcdapi.DebuggerScriptParsedEvent) {
+	d.scriptsLock.Lock()
+	enabled := d.evalHooks
+	d.scriptsLock.Unlock()
+	if !enabled || msg.Params.Url != "" {
+		return
+	}
+	loaderId, loader := scriptLoader(msg.Params.StackTrace, msg.Params.ScriptId)
+	if loaderId == "" && loader == "" {
+		return
+	}
+	code, _, err := d.scriptSource().GetScriptSource(msg.Params.ScriptId)
+	if err != nil {
+		d.log("[-] Unable to get source of evaluated script "+msg.Params.ScriptId, err)
+		return
+	}
+	kind := "eval"
+	if strings.HasPrefix(code, "(function anonymous(") {
+		// how Chrome wraps the body given to the Function constructor
+		kind = "Function"
+	}
+	d.log(fmt.Sprintf("[+] %s called from %s with %d bytes of code", kind, loader, len(code)), nil)
+
+	d.CallInspectors(modules.WebData{
+		Body:     code,
+		Type:     "Eval",
+		Url:      loader,
+		Findings: d.Findings,
+	})
+}
@@ -0,0 +1,60 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"testing"
+	"time"
+)
+
+func TestEvalCallCaptured(t *testing.T) {
+	captured := make(chan modules.WebData, 10)
+	events := &mockEvents{}
+	d := Debugger{
+		ev: events,
+		dbg: &mockDebugger{sources: map[string]string{
+			"20": "alert(document.domain)",
+			"21": "(function anonymous(a\n) {\nreturn a\n})",
+			"22": "window.gorp = function() {};",
+		}},
+		Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+			Registry: modules.Registry{Name: "EvalLogger"},
+			Inspect: func(webData modules.WebData) error {
+				captured <- webData
+				return nil
+			},
+		}}},
+	}
+	assert.Equal(t, d.SetupEvalHooks(), nil)
+	d.trackScripts()
+
+	// eval("alert(document.domain)") and new Function("a", "return a") called from app.js
+	events.fire("Debugger.scriptParsed", `{"scriptId":"20","url":"","stackTrace":{"callFrames":[
+		{"functionName":"run","scriptId":"11","url":"https://example.com/app.js"}]}}`)
+	events.fire("Debugger.scriptParsed", `{"scriptId":"21","url":"","stackTrace":{"callFrames":[
+		{"functionName":"run","scriptId":"11","url":"https://example.com/app.js"}]}}`)
+	// scripts injected by gorp and scripts loaded from a url are not evaluated code
+	events.fire("Debugger.scriptParsed", `{"scriptId":"22","url":""}`)
+	events.fire("Debugger.scriptParsed", `{"scriptId":"11","url":"https://example.com/app.js"}`)
+
+	var evaluated []modules.WebData
+	timeout := time.After(time.Second)
+	for len(evaluated) < 2 {
+		select {
+		case w := <-captured:
+			evaluated = append(evaluated, w)
+		case <-timeout:
+			t.Fatal("evaluated code was not passed to inspectors")
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, len(captured), 0)
+	if evaluated[0].Body != "alert(document.domain)" {
+		evaluated[0], evaluated[1] = evaluated[1], evaluated[0]
+	}
+	assert.Equal(t, evaluated[0].Type, "Eval")
+	assert.Equal(t, evaluated[0].Body, "alert(document.domain)")
+	assert.Equal(t, evaluated[0].Url, "https://example.com/app.js")
+	assert.Equal(t, evaluated[1].Body, "(function anonymous(a\n) {\nreturn a\n})")
+	assert.Equal(t, len(d.Scripts()), 4)
+}
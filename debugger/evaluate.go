@@ -0,0 +1,83 @@
+package debugger
+
+import (
+	"fmt"
+	"github.com/wirepair/gcd/gcdapi"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// runtimeDomain is the subset of the Chrome Dev Tools Runtime domain used to evaluate expressions and read
+// variables.
+// It is implemented by gcdapi.Runtime.
+type runtimeDomain interface {
+	EvaluateWithParams(v *gcdapi.RuntimeEvaluateParams) (*gcdapi.RuntimeRemoteObject, *gcdapi.RuntimeExceptionDetails, error)
+	GetPropertiesWithParams(v *gcdapi.RuntimeGetPropertiesParams) ([]*gcdapi.RuntimePropertyDescriptor,
+		[]*gcdapi.RuntimeInternalPropertyDescriptor, []*gcdapi.RuntimePrivatePropertyDescriptor,
+		*gcdapi.RuntimeExceptionDetails, error)
+}
+
+// Evaluate runs a JavaScript expression in the global scope of the page and returns its result. Promises are
+// awaited. Results are converted to Go values the way encoding/json decodes them, so numbers are float64,
+// objects map[string]interface{} and arrays []interface{}. NaN and infinities are returned as float64,
+// BigInt values as *big.Int, and undefined and null as nil. Exceptions thrown by the expression are returned
+// as errors.
+func (d *Debugger) Evaluate(expr string) (interface{}, error) {
+	result, exception, err := d.runtime().EvaluateWithParams(&gcdapi.RuntimeEvaluateParams{
+		Expression:    expr,
+		ReturnByValue: true,
+		AwaitPromise:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to evaluate expression: %s", err)
+	}
+	if exception != nil {
+		return nil, exceptionError(exception)
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return remoteValue(result)
+}
+
+// exceptionError describes an exception thrown while evaluating an expression
+func exceptionError(e *gcdapi.RuntimeExceptionDetails) error {
+	msg := e.Text
+	if e.Exception != nil && e.Exception.Description != "" {
+		// the description holds the message and stack of thrown errors, only the message is of interest
+		msg += " " + strings.SplitN(e.Exception.Description, "\n", 2)[0]
+	}
+	return fmt.Errorf("%s at %d:%d", msg, e.LineNumber+1, e.ColumnNumber+1)
+}
+
+// remoteValue converts a result returned by value to a Go value
+func remoteValue(r *gcdapi.RuntimeRemoteObject) (interface{}, error) {
+	if r.UnserializableValue == "" {
+		return r.Value, nil
+	}
+	switch v := r.UnserializableValue; v {
+	case "NaN":
+		return math.NaN(), nil
+	case "Infinity":
+		return math.Inf(1), nil
+	case "-Infinity":
+		return math.Inf(-1), nil
+	case "-0":
+		return math.Copysign(0, -1), nil
+	default:
+		if strings.HasSuffix(v, "n") {
+			if n, ok := new(big.Int).SetString(strings.TrimSuffix(v, "n"), 10); ok {
+				return n, nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported value %s", v)
+	}
+}
+
+func (d *Debugger) runtime() runtimeDomain {
+	if d.rt == nil {
+		return d.Target.Runtime
+	}
+	return d.rt
+}
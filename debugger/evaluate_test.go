@@ -0,0 +1,78 @@
+package debugger
+
+import (
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// mockRuntime stands in for the Chrome Runtime domain, answering expressions and property reads from maps
+type mockRuntime struct {
+	results    map[string]*gcdapi.RuntimeRemoteObject
+	exceptions map[string]*gcdapi.RuntimeExceptionDetails
+	evaluated  []*gcdapi.RuntimeEvaluateParams
+	properties map[string][]*gcdapi.RuntimePropertyDescriptor // Properties by object id
+}
+
+func (m *mockRuntime) GetPropertiesWithParams(v *gcdapi.RuntimeGetPropertiesParams) ([]*gcdapi.RuntimePropertyDescriptor,
+	[]*gcdapi.RuntimeInternalPropertyDescriptor, []*gcdapi.RuntimePrivatePropertyDescriptor,
+	*gcdapi.RuntimeExceptionDetails, error) {
+	return m.properties[v.ObjectId], nil, nil, nil, nil
+}
+
+func (m *mockRuntime) EvaluateWithParams(v *gcdapi.RuntimeEvaluateParams) (*gcdapi.RuntimeRemoteObject, *gcdapi.RuntimeExceptionDetails, error) {
+	m.evaluated = append(m.evaluated, v)
+	if e, ok := m.exceptions[v.Expression]; ok {
+		return &gcdapi.RuntimeRemoteObject{Type: "object", Subtype: "error"}, e, nil
+	}
+	if r, ok := m.results[v.Expression]; ok {
+		return r, nil, nil
+	}
+	return nil, nil, errors.New("target closed")
+}
+
+func TestEvaluate(t *testing.T) {
+	rt := &mockRuntime{results: map[string]*gcdapi.RuntimeRemoteObject{
+		"1+1":             {Type: "number", Value: float64(2), Description: "2"},
+		"document.title":  {Type: "string", Value: "Inbox"},
+		"({a: [1, 'b']})": {Type: "object", Value: map[string]interface{}{"a": []interface{}{float64(1), "b"}}},
+		"undefined":       {Type: "undefined"},
+		"1/0":             {Type: "number", UnserializableValue: "Infinity"},
+		"2n**64n":         {Type: "bigint", UnserializableValue: "18446744073709551616n"},
+	}}
+	d := Debugger{rt: rt}
+
+	v, err := d.Evaluate("1+1")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, v, float64(2))
+	assert.Equal(t, rt.evaluated[0].ReturnByValue, true)
+	assert.Equal(t, rt.evaluated[0].AwaitPromise, true)
+
+	v, _ = d.Evaluate("document.title")
+	assert.Equal(t, v, "Inbox")
+	v, _ = d.Evaluate("({a: [1, 'b']})")
+	assert.Equal(t, v, map[string]interface{}{"a": []interface{}{float64(1), "b"}})
+	v, _ = d.Evaluate("undefined")
+	assert.Equal(t, v, nil)
+	v, _ = d.Evaluate("1/0")
+	assert.Equal(t, math.IsInf(v.(float64), 1), true)
+	v, _ = d.Evaluate("2n**64n")
+	assert.Equal(t, v.(*big.Int).String(), "18446744073709551616")
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	rt := &mockRuntime{exceptions: map[string]*gcdapi.RuntimeExceptionDetails{
+		"missing()": {Text: "Uncaught", LineNumber: 0, ColumnNumber: 0, Exception: &gcdapi.RuntimeRemoteObject{
+			Type: "object", Subtype: "error", Description: "ReferenceError: missing is not defined\n    at <anonymous>:1:1",
+		}},
+	}}
+	d := Debugger{rt: rt}
+
+	_, err := d.Evaluate("missing()")
+	assert.Equal(t, err.Error(), "Uncaught ReferenceError: missing is not defined at 1:1")
+	_, err = d.Evaluate("1+1")
+	assert.Equal(t, err.Error(), "unable to evaluate expression: target closed")
+}
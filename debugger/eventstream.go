@@ -0,0 +1,48 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"log"
+	"mime"
+)
+
+// isEventStream reports whether the response is a text/event-stream. Its body only ends when the server
+// closes the stream, so it cannot be fetched and rebuilt like other responses
+func isEventStream(responseHeaders map[string]interface{}) bool {
+	mediaType, _, err := mime.ParseMediaType(headerValue(responseHeaders, "content-type"))
+	return err == nil && mediaType == "text/event-stream"
+}
+
+// trackEventStreams passes the events received by EventSource objects to inspectors as they arrive, as web
+// data of type "EventSource" with the url the stream was requested from. Streams are read only, processors never
+// see them.
+func (d *Debugger) trackEventStreams() {
+	d.Target.Subscribe("Network.eventSourceMessageReceived", func(target *gcd.ChromeTarget, v []byte) {
+		msg := &gcdapi.NetworkEventSourceMessageReceivedEvent{}
+		err := json.Unmarshal(v, msg)
+		if err != nil {
+			log.Println("[-] Unable to read event source message", err)
+			return
+		}
+		d.handleEventSourceMessage(msg)
+	})
+}
+
+func (d *Debugger) handleEventSourceMessage(msg *gcdapi.NetworkEventSourceMessageReceivedEvent) {
+	event := &modules.ServerSentEvent{
+		Id:    msg.Params.EventId,
+		Event: msg.Params.EventName,
+		Data:  msg.Params.Data,
+	}
+	d.CallInspectors(modules.WebData{
+		Body:      event.Data,
+		Type:      "EventSource",
+		Url:       d.sentUrl(msg.Params.RequestId),
+		RequestId: msg.Params.RequestId,
+		Event:     event,
+		Findings:  d.Findings,
+	})
+}
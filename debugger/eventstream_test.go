@@ -0,0 +1,53 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"testing"
+)
+
+func TestEventStreamForwarded(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{}}
+	d := Debugger{net: net}
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","frameId":"top-frame","resourceType":"XHR",
+		"requestId":"r1","request":{"url":"http://example.com/prices","method":"GET"},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"text/event-stream; charset=utf-8"}}`))
+
+	// the body is never fetched, as that would only return once the server closes the stream
+	assert.Equal(t, net.maxInflight, 0)
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	assert.Equal(t, calls[0].RawResponse, "")
+}
+
+func TestEventSourceMessages(t *testing.T) {
+	var received []modules.WebData
+	d := Debugger{
+		Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+			Registry: modules.Registry{Name: "events"},
+			Inspect: func(webData modules.WebData) error {
+				received = append(received, webData)
+				return nil
+			},
+		}}},
+	}
+
+	// EventSource connections are never intercepted, their url comes from the request Chrome sent
+	d.handleRequestWillBeSent(requestWillBeSent(t, `{"requestId":"r1","type":"EventSource",
+		"request":{"url":"http://example.com/prices","method":"GET"},"initiator":{"type":"script"}}`))
+
+	msg := &gcdapi.NetworkEventSourceMessageReceivedEvent{}
+	msg.Params.RequestId = "r1"
+	msg.Params.EventName = "price"
+	msg.Params.EventId = "7"
+	msg.Params.Data = `{"price": 10}`
+	d.handleEventSourceMessage(msg)
+
+	assert.Equal(t, len(received), 1)
+	assert.Equal(t, received[0].Type, "EventSource")
+	assert.Equal(t, received[0].Url, "http://example.com/prices")
+	assert.Equal(t, received[0].Body, `{"price": 10}`)
+	assert.Equal(t, *received[0].Event, modules.ServerSentEvent{Id: "7", Event: "price", Data: `{"price": 10}`})
+}
@@ -0,0 +1,61 @@
+package debugger_test
+
+import (
+	"github.com/DharmaOfCode/gorp/debugger"
+	"github.com/DharmaOfCode/gorp/modules"
+	"log"
+	"strings"
+)
+
+// todoFinder is an inspector reporting script comments that mention TODO
+type todoFinder struct {
+	Registry modules.Registry
+}
+
+func (t *todoFinder) Init() {
+	t.Registry = modules.Registry{
+		Name:        "TODO Finder",
+		DocTypes:    []string{"Script"},
+		Description: "Reports scripts with TODO comments",
+	}
+}
+
+func (t *todoFinder) GetOptions() []modules.Option {
+	return nil
+}
+
+func (t *todoFinder) GetRegistry() modules.Registry {
+	return t.Registry
+}
+
+func (t *todoFinder) Inspect(webData modules.WebData) error {
+	if strings.Contains(webData.Body, "TODO") {
+		webData.Findings.Report(modules.Finding{Rule: "todo", Url: webData.Url})
+	}
+	return nil
+}
+
+func Example() {
+	d, err := debugger.New(debugger.Options{
+		Scope:      "example.com",
+		ChromePath: "/usr/bin/google-chrome",
+		UserDir:    "/tmp/chrome-testing",
+		Port:       "9222",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := d.AddInspector(&todoFinder{}); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := d.Start(); err != nil {
+		log.Fatal(err)
+	}
+	defer d.Stop()
+
+	<-d.Done
+	for _, f := range d.Findings.All() {
+		log.Println(f.Rule, f.Url)
+	}
+}
@@ -0,0 +1,111 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"log"
+	"strings"
+)
+
+// trackFailures passes the requests that did not complete, because of a DNS failure, a refused connection, a
+// blocked or canceled request and the like, to inspectors as web data of type "LoadingFailed". Genuine
+// failures are reported as findings, while requests gorp failed on purpose, blocking them or injecting a
+// fault, are only passed to inspectors, with their Failure.Cause set.
+func (d *Debugger) trackFailures() {
+	d.Target.Subscribe("Network.loadingFailed", func(target *gcd.ChromeTarget, v []byte) {
+		msg := &gcdapi.NetworkLoadingFailedEvent{}
+		err := json.Unmarshal(v, msg)
+		if err != nil {
+			log.Println("[-] Unable to read loading failure event", err)
+			return
+		}
+		d.handleLoadingFailed(msg)
+	})
+}
+
+func (d *Debugger) handleLoadingFailed(msg *gcdapi.NetworkLoadingFailedEvent) {
+	id := msg.Params.RequestId
+	failure := &modules.LoadingFailure{
+		ErrorText:     msg.Params.ErrorText,
+		BlockedReason: msg.Params.BlockedReason,
+		ResourceType:  msg.Params.Type,
+		Canceled:      msg.Params.Canceled,
+	}
+	d.failuresLock.Lock()
+	url := d.sentUrls[id]
+	failure.Cause = d.failedByUs[id]
+	delete(d.failedByUs, id)
+	d.failuresLock.Unlock()
+
+	detail := failure.ResourceType + " failed with " + failure.ErrorText
+	if failure.BlockedReason != "" {
+		detail += ", blocked by " + failure.BlockedReason
+	}
+	if failure.Cause != "" {
+		d.logAs(msgBlocked, "[+] "+url+" failed as intended: "+detail, nil)
+	} else {
+		d.log("[-] Unable to load "+url+": "+detail, nil)
+		reason := failure.BlockedReason
+		if reason == "" {
+			reason = strings.TrimPrefix(failure.ErrorText, "net::")
+		}
+		d.Findings.Report(modules.Finding{
+			Rule:   "LoadingFailed/" + reason,
+			Url:    url,
+			Detail: detail,
+		})
+	}
+	d.CallInspectors(modules.WebData{
+		Body:      failure.ErrorText,
+		Type:      "LoadingFailed",
+		Url:       url,
+		RequestId: id,
+		Failure:   failure,
+		Findings:  d.Findings,
+	})
+}
+
+// addSentUrl keeps the url of a request, for the failure of the request, or the events of a stream, to be
+// reported with it
+func (d *Debugger) addSentUrl(requestId string, url string) {
+	if requestId == "" {
+		return
+	}
+	d.failuresLock.Lock()
+	defer d.failuresLock.Unlock()
+	if d.sentUrls == nil {
+		d.sentUrls = make(map[string]string)
+	}
+	if _, ok := d.sentUrls[requestId]; !ok {
+		d.sentOrder = append(d.sentOrder, requestId)
+	}
+	d.sentUrls[requestId] = url
+	for len(d.sentOrder) > maxInitiators {
+		delete(d.sentUrls, d.sentOrder[0])
+		delete(d.failedByUs, d.sentOrder[0])
+		d.sentOrder = d.sentOrder[1:]
+	}
+}
+
+// sentUrl returns the url of a request, empty when it was not seen or was pruned
+func (d *Debugger) sentUrl(requestId string) string {
+	d.failuresLock.Lock()
+	defer d.failuresLock.Unlock()
+	return d.sentUrls[requestId]
+}
+
+// failOnPurpose records that gorp is about to fail a request, "blocked" or with a "fault", so that the failure
+// is not reported as a genuine one
+func (d *Debugger) failOnPurpose(requestId string, cause string) {
+	if requestId == "" {
+		return
+	}
+	d.failuresLock.Lock()
+	defer d.failuresLock.Unlock()
+	if d.failedByUs == nil {
+		d.failedByUs = make(map[string]string)
+	}
+	d.failedByUs[requestId] = cause
+}
@@ -0,0 +1,63 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"testing"
+)
+
+func loadingFailed(t *testing.T, params string) *gcdapi.NetworkLoadingFailedEvent {
+	msg := &gcdapi.NetworkLoadingFailedEvent{}
+	if err := json.Unmarshal([]byte(`{"method":"Network.loadingFailed","Params":`+params+`}`), msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestLoadingFailedReported(t *testing.T) {
+	var received []modules.WebData
+	d := Debugger{
+		net:      &mockNetwork{},
+		Findings: &modules.Findings{},
+		Options:  Options{BlockMatcher: headerMatcher{header: "x-tag", value: "block"}},
+		Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+			Registry: modules.Registry{Name: "failures"},
+			Inspect: func(webData modules.WebData) error {
+				received = append(received, webData)
+				return nil
+			},
+		}}},
+	}
+	d.addSentUrl("r1", "https://cdn.example.net/app.js")
+	d.handleLoadingFailed(loadingFailed(t, `{"requestId":"r1","type":"Script","errorText":"net::ERR_NAME_NOT_RESOLVED"}`))
+
+	assert.Equal(t, len(received), 1)
+	assert.Equal(t, received[0].Type, "LoadingFailed")
+	assert.Equal(t, received[0].Url, "https://cdn.example.net/app.js")
+	assert.Equal(t, *received[0].Failure, modules.LoadingFailure{
+		ErrorText:    "net::ERR_NAME_NOT_RESOLVED",
+		ResourceType: "Script",
+	})
+	findings := d.Findings.All()
+	assert.Equal(t, len(findings), 1)
+	assert.Equal(t, findings[0].Rule, "LoadingFailed/ERR_NAME_NOT_RESOLVED")
+	assert.Equal(t, findings[0].Url, "https://cdn.example.net/app.js")
+	assert.Equal(t, findings[0].Detail, "Script failed with net::ERR_NAME_NOT_RESOLVED")
+
+	// requests blocked by gorp are passed to inspectors but not reported
+	d.handleInterception(interceptedEvent(t, taggedXhr("3", "block")))
+	d.handleLoadingFailed(loadingFailed(t, `{"requestId":"3","type":"XHR","errorText":"net::ERR_BLOCKED_BY_CLIENT"}`))
+	assert.Equal(t, len(received), 2)
+	assert.Equal(t, received[1].Failure.Cause, "blocked")
+	assert.Equal(t, len(d.Findings.All()), 1)
+
+	// requests blocked by the browser name the reason
+	d.handleLoadingFailed(loadingFailed(t, `{"requestId":"r4","type":"Image","errorText":"net::ERR_BLOCKED_BY_CLIENT",
+		"blockedReason":"mixed-content"}`))
+	findings = d.Findings.All()
+	assert.Equal(t, len(findings), 2)
+	assert.Equal(t, findings[1].Rule, "LoadingFailed/mixed-content")
+	assert.Equal(t, findings[1].Detail, "Image failed with net::ERR_BLOCKED_BY_CLIENT, blocked by mixed-content")
+}
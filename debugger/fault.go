@@ -0,0 +1,92 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd/gcdapi"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Faults that can be injected by a FaultRule
+const (
+	FaultError    = "error"    // Serve an error status in place of the response
+	FaultTruncate = "truncate" // Serve the first half of the body, leaving it malformed
+	FaultDelay    = "delay"    // Hold the response for a while before handling it as usual
+	FaultDrop     = "drop"     // Fail the request as if the connection was lost
+)
+
+// FaultRule injects a failure into responses whose url matches Url, to see how the application copes with it
+type FaultRule struct {
+	Url         string        // Url pattern using Chrome interception wildcards
+	Fault       string        // One of "error", "truncate", "delay" or "drop"
+	Probability float64       // Chance of injecting the fault into a matching response, above 0 and up to 1. 1 always injects it
+	Status      int           // Status served by "error" faults, 500 when 0
+	Delay       time.Duration // How long "delay" faults hold the response
+}
+
+// faultFor returns the first fault rule matching the url of an intercepted response whose fault is to be
+// injected this time around, nil otherwise. A rule whose draw fails leaves the response to the next matching
+// rules. Requests intercepted before they are sent are left alone, so that a fault is only injected once per
+// request
+func (d *Debugger) faultFor(msg *gcdapi.NetworkRequestInterceptedEvent) *FaultRule {
+	if msg.Params.InterceptionId == "" || isRequestStage(msg) {
+		return nil
+	}
+	for i, f := range d.Options.FaultRules {
+		if !wildcardRegexp(f.Url).MatchString(msg.Params.Request.Url) {
+			continue
+		}
+		if rand.Float64() >= f.Probability {
+			continue
+		}
+		return &d.Options.FaultRules[i]
+	}
+	return nil
+}
+
+// drawFault returns the fault rule to inject into an intercepted response, as faultFor does. Delay faults are
+// applied right away, like latency, so that delayed responses do not hold a slot other requests could use, and
+// nil is returned for them as the response is then handled as usual
+func (d *Debugger) drawFault(msg *gcdapi.NetworkRequestInterceptedEvent) *FaultRule {
+	rule := d.faultFor(msg)
+	if rule == nil || rule.Fault != FaultDelay {
+		return rule
+	}
+	d.logAs(msgBlocked, "[+] Injecting "+rule.Fault+" fault into "+msg.Params.Request.Url, nil)
+	time.Sleep(rule.Delay)
+	return nil
+}
+
+// injectFault injects the fault of rule, any but a delay, into an intercepted response
+func (d *Debugger) injectFault(msg *gcdapi.NetworkRequestInterceptedEvent, rule *FaultRule) {
+	iid := msg.Params.InterceptionId
+	url := msg.Params.Request.Url
+	d.logAs(msgBlocked, "[+] Injecting "+rule.Fault+" fault into "+url, nil)
+
+	switch rule.Fault {
+	case FaultError:
+		status := rule.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		headers := map[string]string{"Content-Type": "text/plain"}
+		d.continueRequest(iid, "", rawResponse(status, headers, http.StatusText(status)), "", "")
+	case FaultDrop:
+		d.failOnPurpose(msg.Params.RequestId, "fault")
+		d.continueRequest(iid, "ConnectionReset", "", "", "")
+	case FaultTruncate:
+		res, encoded, err := d.network().GetResponseBodyForInterception(iid)
+		if err == nil && encoded {
+			res, err = decodeBase64Response(res)
+		}
+		if err != nil {
+			log.Println("[-] Unable to get intercepted response body!", err.Error())
+			d.continueRequest(iid, msg.Params.ResponseErrorReason, "", "", "")
+			return
+		}
+		webData := modules.WebData{Headers: msg.Params.ResponseHeaders, Url: url, Status: msg.Params.ResponseStatusCode}
+		d.continueRequest(iid, "", d.buildResponse(webData, res[:len(res)/2]), "", "")
+	}
+}
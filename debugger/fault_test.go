@@ -0,0 +1,119 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFaultError(t *testing.T) {
+	processed := false
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`, "2": `{"id":2}`}}
+	d := Debugger{
+		net: net,
+		Options: Options{FaultRules: []FaultRule{
+			{Url: "https://example.com/api/r1", Fault: FaultError, Probability: 1},
+		}},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{{
+			Registry: modules.Registry{Name: "spy"},
+			Process: func(webData modules.WebData) (string, error) {
+				processed = true
+				return webData.Body, nil
+			},
+		}}},
+	}
+
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	sent := decodeRaw(t, calls[0].RawResponse)
+	assert.Equal(t, strings.HasPrefix(sent, "HTTP/1.1 500 Internal Server Error\r\n"), true)
+	assert.Equal(t, processed, false)
+
+	// other urls are left alone
+	d.handleInterception(xhrResponse(t, "2", "r2"))
+	calls = net.calls()
+	assert.Equal(t, len(calls), 2)
+	assert.Equal(t, calls[1].RawResponse, "")
+	assert.Equal(t, processed, true)
+}
+
+func TestFaultTruncateAndDrop(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1234}`}}
+	d := Debugger{
+		net: net,
+		Options: Options{FaultRules: []FaultRule{
+			{Url: "*/api/r1", Fault: FaultTruncate, Probability: 1},
+			{Url: "*/api/*", Fault: FaultDrop, Probability: 1},
+		}},
+	}
+
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	d.handleInterception(xhrResponse(t, "2", "r2"))
+	calls := net.calls()
+	assert.Equal(t, len(calls), 2)
+	sent := decodeRaw(t, calls[0].RawResponse)
+	assert.Equal(t, strings.HasPrefix(sent, "HTTP/1.1 200 OK\r\n"), true)
+	assert.Equal(t, strings.HasSuffix(sent, "\r\n\r\n"+`{"id"`), true)
+	assert.Equal(t, calls[1], continued{InterceptionId: "2", ErrorReason: "ConnectionReset"})
+}
+
+func TestFaultDelay(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`}}
+	d := Debugger{
+		net: net,
+		Options: Options{
+			InterceptRequests: true,
+			FaultRules:        []FaultRule{{Url: "*", Fault: FaultDelay, Probability: 1, Delay: 50 * time.Millisecond}},
+		},
+	}
+
+	// the request is not held before it is sent, only its response is
+	start := time.Now()
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"0","requestId":"r1","frameId":"top-frame",
+		"resourceType":"XHR","request":{"url":"https://example.com/api/r1","method":"GET"}}`))
+	assert.Equal(t, time.Since(start) < 50*time.Millisecond, true)
+
+	start = time.Now()
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	assert.Equal(t, time.Since(start) >= 50*time.Millisecond, true)
+	calls := net.calls()
+	assert.Equal(t, len(calls), 2)
+	// the response is handled as usual once the delay is over, and forwarded since nothing changed it
+	assert.Equal(t, calls[1], continued{InterceptionId: "1"})
+}
+
+func TestFaultDelayHoldsNoSlot(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`, "2": `{"id":2}`}}
+	d := Debugger{
+		net: net,
+		Options: Options{
+			MaxInflight: 1,
+			FaultRules:  []FaultRule{{Url: "*/api/r1", Fault: FaultDelay, Probability: 1, Delay: 200 * time.Millisecond}},
+		},
+	}
+
+	go d.handleInterception(xhrResponse(t, "1", "r1"))
+	time.Sleep(20 * time.Millisecond)
+	// the only slot is free while the first response is delayed
+	start := time.Now()
+	d.handleInterception(xhrResponse(t, "2", "r2"))
+	assert.Equal(t, time.Since(start) < 150*time.Millisecond, true)
+}
+
+func TestFaultRulesAfterFailedDraw(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`}}
+	d := Debugger{
+		net: net,
+		Options: Options{FaultRules: []FaultRule{
+			{Url: "*/api/*", Fault: FaultError, Probability: 0.0000001},
+			{Url: "*/api/r1", Fault: FaultDrop, Probability: 1},
+		}},
+	}
+
+	// the first rule is almost never drawn, the second one then applies
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	assert.Equal(t, net.calls()[0], continued{InterceptionId: "1", ErrorReason: "ConnectionReset"})
+}
@@ -0,0 +1,38 @@
+package debugger
+
+import (
+	"github.com/wirepair/gcd/gcdapi"
+	"net/url"
+	"strings"
+)
+
+// isFirstParty reports whether a request goes to the origin of the document loaded in the top frame, according
+// to Options.FirstPartyOnly and Options.FirstPartySubdomains. Navigations of the top frame, which load the next
+// document, and requests made before any document was loaded are always first party
+func (d *Debugger) isFirstParty(msg *gcdapi.NetworkRequestInterceptedEvent) bool {
+	if !d.Options.FirstPartyOnly {
+		return true
+	}
+	top := d.topFrame()
+	if top == nil || (msg.Params.IsNavigationRequest && msg.Params.FrameId == top.Id) {
+		return true
+	}
+	document, err := url.Parse(top.Url)
+	if err != nil || document.Host == "" {
+		return true
+	}
+	request, err := url.Parse(msg.Params.Request.Url)
+	if err != nil {
+		return false
+	}
+	if d.Options.FirstPartySubdomains {
+		return isSubdomain(request.Hostname(), strings.TrimPrefix(document.Hostname(), "www."))
+	}
+	return request.Scheme == document.Scheme && request.Host == document.Host
+}
+
+// isSubdomain reports whether host is domain or one of its subdomains
+func isSubdomain(host string, domain string) bool {
+	host, domain = strings.ToLower(host), strings.ToLower(domain)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
@@ -0,0 +1,59 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"testing"
+)
+
+func TestFirstPartyOnly(t *testing.T) {
+	var processed []string
+	net := &mockNetwork{bodies: map[string]string{"1": "var a;", "2": "var b;", "3": "var c;", "4": "var d;"}}
+	d := Debugger{
+		net:     net,
+		Options: Options{FirstPartyOnly: true},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{countingProcessor("p", &processed)}},
+	}
+	d.addFrame(&gcdapi.PageFrame{Id: "top-frame", Url: "https://www.example.com/app"})
+
+	d.handleInterception(scriptResponse(t, "1", "top-frame", "https://www.example.com/app.js"))
+	d.handleInterception(scriptResponse(t, "2", "top-frame", "https://cdn.tracker.net/t.js"))
+	d.handleInterception(scriptResponse(t, "3", "top-frame", "https://static.example.com/lib.js"))
+	d.handleInterception(scriptResponse(t, "4", "top-frame", "http://www.example.com/app.js"))
+
+	assert.Equal(t, processed, []string{"https://www.example.com/app.js"})
+	calls := net.calls()
+	assert.Equal(t, len(calls), 4)
+	for _, c := range calls[1:] {
+		assert.Equal(t, c.RawResponse, "")
+	}
+}
+
+func TestFirstPartySubdomains(t *testing.T) {
+	var processed []string
+	net := &mockNetwork{bodies: map[string]string{"1": "var a;", "2": "var b;", "3": "var c;"}}
+	d := Debugger{
+		net:     net,
+		Options: Options{FirstPartyOnly: true, FirstPartySubdomains: true},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{countingProcessor("p", &processed)}},
+	}
+	d.addFrame(&gcdapi.PageFrame{Id: "top-frame", Url: "https://www.example.com/app"})
+
+	d.handleInterception(scriptResponse(t, "1", "top-frame", "https://static.example.com/lib.js"))
+	d.handleInterception(scriptResponse(t, "2", "top-frame", "https://cdn.tracker.net/t.js"))
+	d.handleInterception(scriptResponse(t, "3", "top-frame", "https://notexample.com/x.js"))
+
+	assert.Equal(t, processed, []string{"https://static.example.com/lib.js"})
+}
+
+func TestFirstPartyNavigation(t *testing.T) {
+	d := Debugger{Options: Options{FirstPartyOnly: true}}
+	// nothing is known of the page before its first navigation
+	assert.Equal(t, d.isFirstParty(scriptResponse(t, "1", "top-frame", "https://cdn.tracker.net/t.js")), true)
+
+	d.addFrame(&gcdapi.PageFrame{Id: "top-frame", Url: "https://www.example.com/app"})
+	msg := interceptedEvent(t, `{"interceptionId":"2","frameId":"top-frame","resourceType":"Document",
+		"isNavigationRequest":true,"request":{"url":"https://other.com/","method":"GET"}}`)
+	assert.Equal(t, d.isFirstParty(msg), true)
+}
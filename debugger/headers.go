@@ -0,0 +1,67 @@
+package debugger
+
+import (
+	"sort"
+	"strings"
+)
+
+// hopByHopHeaders only apply to a single connection and must not be forwarded, as defined in RFC 7230
+var hopByHopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"TE",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// forwardHeader reports whether a response header is kept when the response is rebuilt. Hop-by-hop headers,
+// including those listed in the Connection header, are dropped unless they are in Options.AllowHeaders.
+// When Options.AllowHeaders is set, headers missing from it are dropped as well, except Content-Length which is
+// recomputed for the new body. Headers in Options.DenyHeaders are always dropped.
+func (d *Debugger) forwardHeader(name string, headers map[string]interface{}) bool {
+	if containsFold(d.Options.DenyHeaders, name) {
+		return false
+	}
+	if len(d.Options.AllowHeaders) > 0 {
+		return containsFold(d.Options.AllowHeaders, name) || strings.EqualFold(name, "content-length")
+	}
+	if containsFold(hopByHopHeaders, name) {
+		return false
+	}
+	for _, h := range strings.Split(headerValue(headers, "connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(h), name) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedHeaderNames returns the header names sorted regardless of case, so that rebuilt responses are the
+// same from one run to the next. Chrome reports headers as a map, their original order is lost
+func sortedHeaderNames(headers map[string]interface{}) []string {
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := strings.ToLower(names[i]), strings.ToLower(names[j])
+		if a == b {
+			return names[i] < names[j]
+		}
+		return a < b
+	})
+	return names
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"log"
+)
+
+// maxInitiators bounds the number of request initiators kept, as most requests are never intercepted
+const maxInitiators = 5000
+
+// trackInitiators keeps track of what caused each request to be made, of its url and of the urls it was
+// redirected from, so that they can be added to the web data of the request, its response or its failure.
+// Document requests are passed on to the interception watchdog
+func (d *Debugger) trackInitiators() {
+	d.Target.Subscribe("Network.requestWillBeSent", func(target *gcd.ChromeTarget, v []byte) {
+		msg := &gcdapi.NetworkRequestWillBeSentEvent{}
+		err := json.Unmarshal(v, msg)
+		if err != nil {
+			log.Println("[-] Unable to read request event", err)
+			return
+		}
+		d.handleRequestWillBeSent(msg)
+	})
+}
+
+func (d *Debugger) handleRequestWillBeSent(msg *gcdapi.NetworkRequestWillBeSentEvent) {
+	d.addInitiator(msg.Params.RequestId, msg.Params.Initiator)
+	if msg.Params.Request != nil {
+		d.addSentUrl(msg.Params.RequestId, msg.Params.Request.Url)
+	}
+	if msg.Params.RedirectResponse != nil {
+		d.addRedirect(msg.Params.RequestId, msg.Params.RedirectResponse.Url)
+	}
+	d.requestSent(msg)
+}
+
+func (d *Debugger) addInitiator(requestId string, initiator *gcdapi.NetworkInitiator) {
+	if requestId == "" || initiator == nil {
+		return
+	}
+	i := modules.Initiator{Type: initiator.Type, Url: initiator.Url}
+	if i.Url == "" && initiator.Stack != nil && len(initiator.Stack.CallFrames) > 0 {
+		// requests made by scripts only carry the stack of the call
+		i.Url = initiator.Stack.CallFrames[0].Url
+	}
+
+	d.initiatorsLock.Lock()
+	defer d.initiatorsLock.Unlock()
+	if d.initiators == nil {
+		d.initiators = make(map[string]modules.Initiator)
+	}
+	if _, ok := d.initiators[requestId]; !ok {
+		d.initiatorOrder = append(d.initiatorOrder, requestId)
+	}
+	d.initiators[requestId] = i
+	for len(d.initiatorOrder) > maxInitiators {
+		delete(d.initiators, d.initiatorOrder[0])
+		d.initiatorOrder = d.initiatorOrder[1:]
+	}
+}
+
+// initiatorFor returns the initiator of the request, empty when Chrome has not reported it yet. This can
+// happen for requests intercepted at the "Request" stage
+func (d *Debugger) initiatorFor(requestId string) modules.Initiator {
+	d.initiatorsLock.RLock()
+	defer d.initiatorsLock.RUnlock()
+	return d.initiators[requestId]
+}
+
+// inInitiatorScope reports whether requests with the given initiator should be processed according to
+// Options.InitiatorFilter
+func (d *Debugger) inInitiatorScope(initiator modules.Initiator) bool {
+	if len(d.Options.InitiatorFilter) == 0 {
+		return true
+	}
+	for _, t := range d.Options.InitiatorFilter {
+		if t == initiator.Type {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"testing"
+)
+
+func xhrResponse(t *testing.T, iid string, requestId string) *gcdapi.NetworkRequestInterceptedEvent {
+	return interceptedEvent(t, `{"interceptionId":"`+iid+`","requestId":"`+requestId+`","frameId":"top-frame",
+		"resourceType":"XHR","request":{"url":"https://example.com/api/`+requestId+`","method":"GET"},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"application/json"}}`)
+}
+
+func requestWillBeSent(t *testing.T, params string) *gcdapi.NetworkRequestWillBeSentEvent {
+	msg := &gcdapi.NetworkRequestWillBeSentEvent{}
+	if err := json.Unmarshal([]byte(`{"method":"Network.requestWillBeSent","Params":`+params+`}`), msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestInitiator(t *testing.T) {
+	var initiators []modules.Initiator
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`, "2": `{"id":2}`}}
+	d := Debugger{
+		net:     net,
+		Options: Options{InitiatorFilter: []string{"script"}},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{{
+			Registry: modules.Registry{Name: "initiators", DocTypes: []string{"XHR"}},
+			Process: func(webData modules.WebData) (string, error) {
+				initiators = append(initiators, webData.Initiator)
+				return webData.Body, nil
+			},
+		}}},
+	}
+
+	// fetch() called by app.js
+	script := requestWillBeSent(t, `{"requestId":"r1","initiator":{"type":"script",
+		"stack":{"callFrames":[{"functionName":"load","scriptId":"12","url":"https://example.com/app.js","lineNumber":3,"columnNumber":8}]}}}`)
+	d.addInitiator(script.Params.RequestId, script.Params.Initiator)
+	// preloaded by the html parser
+	parser := requestWillBeSent(t, `{"requestId":"r2","initiator":{"type":"parser","url":"https://example.com/","lineNumber":12}}`)
+	d.addInitiator(parser.Params.RequestId, parser.Params.Initiator)
+
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	d.handleInterception(xhrResponse(t, "2", "r2"))
+
+	assert.Equal(t, initiators, []modules.Initiator{{Type: "script", Url: "https://example.com/app.js"}})
+	calls := net.calls()
+	assert.Equal(t, len(calls), 2)
+	assert.Equal(t, calls[1].InterceptionId, "2")
+	assert.Equal(t, calls[1].RawResponse, "")
+	assert.Equal(t, d.initiatorFor("r2"), modules.Initiator{Type: "parser", Url: "https://example.com/"})
+}
@@ -0,0 +1,67 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"strconv"
+)
+
+// processInlineScripts passes the inline scripts of an HTML document to the processors that declare the
+// "Script" doc type, as web data of type "Script" whose body is the code of the script, and writes the code they
+// return back into the document. Processors that also declare "Document" already saw the scripts as part of the
+// document and are skipped. Body match rules are applied to the code of every script, and a failing processor
+// leaves its script as it was.
+func (d *Debugger) processInlineScripts(data modules.WebData, body string) string {
+	var processors []modules.ProcessorModule
+	for _, v := range d.Modules.Processors {
+		if v.Process != nil && handlesDocType(v.Registry, "Script") && !handlesDocType(v.Registry, "Document") {
+			processors = append(processors, v)
+		}
+	}
+	if len(processors) == 0 {
+		return body
+	}
+
+	result, n := api.ReplaceInlineScripts(body, func(script *api.InlineScript) bool {
+		webData := modules.WebData{
+			Body:           script.Code,
+			Headers:        map[string]interface{}{"Content-Type": "text/javascript"},
+			RequestHeaders: data.RequestHeaders,
+			Type:           "Script",
+			Url:            data.Url,
+			Method:         data.Method,
+			RequestId:      data.RequestId,
+			FrameId:        data.FrameId,
+			Initiator:      data.Initiator,
+			Redirects:      data.Redirects,
+			Findings:       d.Findings,
+		}
+		gates := d.bodyGates(script.Code)
+		for _, p := range processors {
+			if !gateAllows(gates, p.Registry.Name) {
+				continue
+			}
+			altered, err := d.runProcessor(p, webData)
+			if err != nil {
+				d.log("[-] Unable to process inline script in "+data.Url, err)
+				return false
+			}
+			webData.Body = altered
+		}
+		if webData.Body == script.Code {
+			return false
+		}
+		script.Code = webData.Body
+		return true
+	})
+	if n > 0 {
+		d.log("[+] Replaced "+strconv.Itoa(n)+" inline script(s) in "+data.Url, nil)
+	}
+	return result
+}
+
+// isHTMLDocument reports whether data is an HTML document, whose inline scripts can be processed
+func isHTMLDocument(data modules.WebData) bool {
+	kind := modules.ContentKind(headerValue(data.Headers, "content-type"))
+	return data.Type == "Document" && (kind == "text/html" || kind == "")
+}
@@ -0,0 +1,56 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+// debuggerRemover removes debugger statements from scripts
+var debuggerRemover = modules.ProcessorModule{
+	Registry: modules.Registry{Name: "nodebugger", DocTypes: []string{"Script"}},
+	Process: func(webData modules.WebData) (string, error) {
+		if webData.Type != "Script" {
+			return webData.Body, nil
+		}
+		return strings.Replace(webData.Body, "debugger;", "", -1), nil
+	},
+}
+
+const inlineScriptDocument = `<html><head><script src="/app.js"></script><script>setInterval(function() { debugger; }, 100);</script></head>
+<body><p>debugger;</p><script type="application/json">{"code": "debugger;"}</script></body></html>`
+
+func TestProcessInlineScripts(t *testing.T) {
+	d := Debugger{
+		Options: Options{ProcessInlineScripts: true},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{debuggerRemover}},
+	}
+	raw, err := d.CallProcessors(modules.WebData{Body: inlineScriptDocument, Type: "Document",
+		Headers: map[string]interface{}{"Content-Type": "text/html; charset=utf-8"}})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, raw),
+		`<html><head><script src="/app.js"></script><script>setInterval(function() {  }, 100);</script></head>
+<body><p>debugger;</p><script type="application/json">{"code": "debugger;"}</script></body></html>`), true)
+}
+
+func TestInlineScriptsUntouchedByDefault(t *testing.T) {
+	d := Debugger{Modules: modules.Modules{Processors: []modules.ProcessorModule{debuggerRemover}}}
+	raw, err := d.CallProcessors(modules.WebData{Body: inlineScriptDocument, Type: "Document"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, raw), inlineScriptDocument), true)
+}
+
+func TestInlineScriptsBodyMatchRules(t *testing.T) {
+	d := Debugger{
+		Options: Options{ProcessInlineScripts: true, BodyMatchRules: []BodyMatchRule{
+			{Match: `setInterval`, Processors: []string{"nodebugger"}},
+		}},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{debuggerRemover}},
+	}
+	raw, err := d.CallProcessors(modules.WebData{Body: `<script>setInterval(function() { debugger; }, 100);</script><script>debugger;</script>`,
+		Type: "Document", Headers: map[string]interface{}{"Content-Type": "text/html"}})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, raw),
+		`<script>setInterval(function() {  }, 100);</script><script>debugger;</script>`), true)
+}
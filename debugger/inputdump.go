@@ -0,0 +1,40 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/DharmaOfCode/gorp/modules"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// dumpProcessorInput saves the web data passed to a processor in Options.ProcessorInputDir, as a fixture file
+// that modules.LoadFixtures reads, so that the processor can be run on the exact same input in a unit test.
+// Files are named after the processor, with a "-failed" suffix when the processor failed on the input
+func (d *Debugger) dumpProcessorInput(p modules.ProcessorModule, data modules.WebData, failed bool) {
+	if d.Options.ProcessorInputDir == "" || (!failed && !d.Options.DumpProcessorInputs) {
+		return
+	}
+	n := atomic.AddInt32(&d.dumpedInputs, 1)
+	name := fmt.Sprintf("%s-%04d", unsafeFileChars.ReplaceAllString(p.Registry.Name, "_"), n)
+	if failed {
+		name += "-failed"
+	}
+	path := filepath.Join(d.Options.ProcessorInputDir, name+".json")
+
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err == nil {
+		if err = os.MkdirAll(d.Options.ProcessorInputDir, 0755); err == nil {
+			err = ioutil.WriteFile(path, encoded, 0644)
+		}
+	}
+	if err != nil {
+		d.log("[-] Unable to save input of processor "+p.Registry.Name, err)
+		return
+	}
+	if failed {
+		d.log("[+] Saved the input "+p.Registry.Name+" failed on to "+path, nil)
+	}
+}
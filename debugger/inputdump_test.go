@@ -0,0 +1,73 @@
+package debugger
+
+import (
+	"errors"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDumpFailingProcessorInput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-inputs")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`, "2": `{"id":"two"}`}}
+	d := Debugger{
+		net:     net,
+		Options: Options{ProcessorInputDir: dir},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{{
+			Registry: modules.Registry{Name: "id parser"},
+			Process: func(webData modules.WebData) (string, error) {
+				if webData.Body == `{"id":"two"}` {
+					return "", errors.New("id is not a number")
+				}
+				return webData.Body, nil
+			},
+		}}},
+	}
+
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	d.handleInterception(xhrResponse(t, "2", "r2"))
+
+	files, err := ioutil.ReadDir(dir)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(files), 1)
+	assert.Equal(t, files[0].Name(), "id_parser-0001-failed.json")
+
+	inputs, err := modules.LoadFixtures(filepath.Join(dir, files[0].Name()))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(inputs), 1)
+	assert.Equal(t, inputs[0].Body, `{"id":"two"}`)
+	assert.Equal(t, inputs[0].Url, "https://example.com/api/r2")
+	assert.Equal(t, inputs[0].Type, "XHR")
+}
+
+func TestDumpAllProcessorInputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-inputs")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`}}
+	d := Debugger{
+		net:     net,
+		Options: Options{ProcessorInputDir: filepath.Join(dir, "inputs"), DumpProcessorInputs: true},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{{
+			Registry: modules.Registry{Name: "noop"},
+			Process: func(webData modules.WebData) (string, error) {
+				return webData.Body, nil
+			},
+		}}},
+	}
+
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	inputs, err := modules.LoadFixtures(filepath.Join(dir, "inputs", "noop-0001.json"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, inputs[0].Body, `{"id":1}`)
+
+	err = Options{DumpProcessorInputs: true}.Validate()
+	assert.Equal(t, err.Error(), "invalid options: dumpProcessorInputs requires processorInputDir")
+}
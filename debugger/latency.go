@@ -0,0 +1,47 @@
+package debugger
+
+import (
+	"github.com/wirepair/gcd/gcdapi"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// LatencyRange is the range a random delay is drawn from
+type LatencyRange struct {
+	Min time.Duration // Shortest delay
+	Max time.Duration // Longest delay, 0 for a fixed delay of Min
+}
+
+// latencyFor draws the delay added to an intercepted response according to Options.Latency, whose resource
+// types are matched regardless of case, as the config file loader lowercases them. Requests intercepted before
+// they are sent are not delayed
+func (d *Debugger) latencyFor(msg *gcdapi.NetworkRequestInterceptedEvent) time.Duration {
+	if msg.Params.InterceptionId == "" || isRequestStage(msg) {
+		return 0
+	}
+	var l LatencyRange
+	ok := false
+	for t, r := range d.Options.Latency {
+		if strings.EqualFold(t, msg.Params.ResourceType) {
+			l, ok = r, true
+			break
+		}
+	}
+	if !ok {
+		return 0
+	}
+	if l.Max <= l.Min {
+		return l.Min
+	}
+	return l.Min + time.Duration(rand.Int63n(int64(l.Max-l.Min)+1))
+}
+
+// addLatency holds an intercepted response for the delay drawn by latencyFor. Only the response is held,
+// others are handled meanwhile
+func (d *Debugger) addLatency(msg *gcdapi.NetworkRequestInterceptedEvent) {
+	if delay := d.latencyFor(msg); delay > 0 {
+		d.log("[+] Delaying "+msg.Params.Request.Url+" by "+delay.String(), nil)
+		time.Sleep(delay)
+	}
+}
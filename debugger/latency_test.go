@@ -0,0 +1,51 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+	"time"
+)
+
+func TestLatency(t *testing.T) {
+	d := Debugger{Options: Options{Latency: map[string]LatencyRange{
+		"XHR":   {Min: 100 * time.Millisecond, Max: 300 * time.Millisecond},
+		"image": {Min: 5 * time.Millisecond},
+	}}}
+	xhr := xhrResponse(t, "1", "r1")
+	seen := map[bool]bool{}
+	for i := 0; i < 200; i++ {
+		delay := d.latencyFor(xhr)
+		assert.Equal(t, delay >= 100*time.Millisecond && delay <= 300*time.Millisecond, true)
+		seen[delay < 200*time.Millisecond] = true
+	}
+	// delays are spread over the range
+	assert.Equal(t, len(seen), 2)
+
+	image := interceptedEvent(t, `{"interceptionId":"2","resourceType":"Image","request":{"url":"https://example.com/a.png"},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"image/png"}}`)
+	assert.Equal(t, d.latencyFor(image), 5*time.Millisecond)
+	// other types and requests not sent yet are not delayed
+	script := interceptedEvent(t, `{"interceptionId":"3","resourceType":"Script","request":{"url":"https://example.com/a.js"},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"text/javascript"}}`)
+	assert.Equal(t, d.latencyFor(script), time.Duration(0))
+	request := interceptedEvent(t, `{"interceptionId":"4","resourceType":"XHR","request":{"url":"https://example.com/api"}}`)
+	assert.Equal(t, d.latencyFor(request), time.Duration(0))
+}
+
+func TestLatencyDelaysResponse(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": "{}"}}
+	d := Debugger{net: net, Options: Options{Latency: map[string]LatencyRange{
+		"XHR": {Min: 20 * time.Millisecond, Max: 40 * time.Millisecond},
+	}}}
+	start := time.Now()
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	assert.Equal(t, time.Since(start) >= 20*time.Millisecond, true)
+	assert.Equal(t, len(net.calls()), 1)
+}
+
+func TestValidateLatency(t *testing.T) {
+	err := Options{Latency: map[string]LatencyRange{"XHR": {Min: time.Second, Max: time.Millisecond}}}.Validate()
+	assert.Equal(t, err.Error(), "invalid options: latency of XHR must range from a non-negative delay to a longer one, got 1s to 1ms")
+	// a range without a maximum is a fixed delay
+	assert.Equal(t, Options{Latency: map[string]LatencyRange{"Image": {Min: time.Second}}}.Validate(), nil)
+}
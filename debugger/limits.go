@@ -0,0 +1,52 @@
+package debugger
+
+import (
+	"math/rand"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// countInterception counts an intercepted request towards Options.MaxRequests, stopping the session once the
+// limit is reached. It is called once the interception has been continued, so that the last request still
+// gets through, and stops the session in the background so that the interception is not held up meanwhile
+func (d *Debugger) countInterception() {
+	if d.Options.MaxRequests <= 0 {
+		return
+	}
+	if n := atomic.AddInt32(&d.intercepted, 1); int(n) == d.Options.MaxRequests {
+		d.log("[+] Reached the limit of "+strconv.Itoa(d.Options.MaxRequests)+" requests, stopping", nil)
+		go d.Stop()
+	}
+}
+
+// limitDuration stops the session once Options.MaxDuration has elapsed. Stopping the session earlier makes
+// the timer a no-op
+func (d *Debugger) limitDuration() {
+	if d.Options.MaxDuration <= 0 {
+		return
+	}
+	time.AfterFunc(d.Options.MaxDuration, func() {
+		d.log("[+] Session lasted "+d.Options.MaxDuration.String()+", stopping", nil)
+		d.Stop()
+	})
+}
+
+// sampled reports whether an intercepted request is to be processed according to Options.SampleRate. Each
+// interception is drawn on its own, so the request and the response of a single request may not both be
+// processed
+func (d *Debugger) sampled() bool {
+	if !d.Options.Sampling {
+		return true
+	}
+	d.samplerLock.Lock()
+	defer d.samplerLock.Unlock()
+	if d.sampler == nil {
+		seed := d.Options.SampleSeed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		d.sampler = rand.New(rand.NewSource(seed))
+	}
+	return d.sampler.Float64() < d.Options.SampleRate
+}
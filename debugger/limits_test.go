@@ -0,0 +1,105 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func stopped(d *Debugger) bool {
+	select {
+	case <-d.Done:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitStopped reports whether the session stops within a few seconds
+func waitStopped(d *Debugger) bool {
+	select {
+	case <-d.Done:
+		return true
+	case <-time.After(5 * time.Second):
+		return false
+	}
+}
+
+func TestMaxRequests(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": "{}", "2": "{}", "3": "{}"}}
+	d := &Debugger{net: net, Done: make(chan bool), Options: Options{MaxRequests: 3}}
+
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	d.handleInterception(xhrResponse(t, "2", "r2"))
+	assert.Equal(t, stopped(d), false)
+	d.handleInterception(xhrResponse(t, "3", "r3"))
+	assert.Equal(t, waitStopped(d), true)
+	// the last request was still sent back to the browser
+	assert.Equal(t, len(net.calls()), 3)
+}
+
+func TestMaxRequestsConcurrent(t *testing.T) {
+	d := &Debugger{Done: make(chan bool), Options: Options{MaxRequests: 10}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.countInterception()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, waitStopped(d), true)
+}
+
+func TestMaxDuration(t *testing.T) {
+	d := &Debugger{Done: make(chan bool), Options: Options{MaxDuration: 10 * time.Millisecond}}
+	d.limitDuration()
+	assert.Equal(t, waitStopped(d), true)
+}
+
+func TestValidateLimits(t *testing.T) {
+	err := Options{MaxRequests: -1, MaxDuration: -time.Second}.Validate()
+	assert.Equal(t, err.Error(), "invalid options: maxRequests must not be negative, got -1; maxDuration must not be negative, got -1s")
+}
+
+// sample runs ten responses through a debugger sampling them at rate with seed.
+// It returns the urls processed and the number of responses sent back to the browser
+func sample(t *testing.T, rate float64, seed int64) ([]string, int) {
+	net := &mockNetwork{bodies: map[string]string{}}
+	var processed []string
+	d := &Debugger{
+		net:     net,
+		Done:    make(chan bool),
+		Options: Options{Sampling: true, SampleRate: rate, SampleSeed: seed, MaxRequests: 10},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{countingProcessor("p", &processed)}},
+	}
+	for i := 0; i < 10; i++ {
+		iid := strconv.Itoa(i)
+		net.bodies[iid] = "{}"
+		d.handleInterception(xhrResponse(t, iid, "r"+iid))
+	}
+	// requests left out of the sample still count towards the limit
+	assert.Equal(t, waitStopped(d), true)
+	return processed, len(net.calls())
+}
+
+func TestSampling(t *testing.T) {
+	processed, sent := sample(t, 0, 1)
+	assert.Equal(t, len(processed), 0)
+	assert.Equal(t, sent, 10)
+
+	processed, sent = sample(t, 1, 1)
+	assert.Equal(t, len(processed), 10)
+	assert.Equal(t, sent, 10)
+
+	// the same seed draws the same sample
+	processed, _ = sample(t, 0.5, 42)
+	again, _ := sample(t, 0.5, 42)
+	assert.Equal(t, again, processed)
+	assert.Equal(t, len(processed) > 0 && len(processed) < 10, true)
+}
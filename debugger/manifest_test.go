@@ -0,0 +1,68 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+const webAppManifest = `{"name":"Example","start_url":"/?source=pwa","scope":"/","display":"standalone"}`
+
+func TestManifestProcessor(t *testing.T) {
+	inspected := make(chan string, 1)
+	net := &mockNetwork{bodies: map[string]string{"1": webAppManifest}}
+	d := Debugger{
+		net: net,
+		Modules: modules.Modules{
+			Processors: []modules.ProcessorModule{{
+				Registry: modules.Registry{Name: "StartUrl", DocTypes: []string{"Manifest"}},
+				Process: func(webData modules.WebData) (string, error) {
+					if webData.Type != "Manifest" {
+						return webData.Body, nil
+					}
+					var manifest map[string]interface{}
+					if err := json.Unmarshal([]byte(webData.Body), &manifest); err != nil {
+						return "", err
+					}
+					manifest["start_url"] = "/debug"
+					b, err := json.Marshal(manifest)
+					return string(b), err
+				},
+			}},
+			Inspectors: []modules.InspectorModule{{
+				Registry: modules.Registry{Name: "Manifests"},
+				Inspect: func(webData modules.WebData) error {
+					inspected <- webData.Type
+					return nil
+				},
+			}},
+		},
+	}
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"Manifest",
+		"request":{"url":"https://example.com/manifest.webmanifest","method":"GET"},"responseStatusCode":200,
+		"responseHeaders":{"Content-Type":"application/manifest+json"}}`))
+
+	assert.Equal(t, <-inspected, "Manifest")
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	body := strings.SplitN(decodeRaw(t, calls[0].RawResponse), "\r\n\r\n", 2)[1]
+	var manifest map[string]interface{}
+	assert.Equal(t, json.Unmarshal([]byte(body), &manifest), nil)
+	assert.Equal(t, manifest["start_url"], "/debug")
+	assert.Equal(t, manifest["scope"], "/")
+}
+
+func TestManifestAndIconTypes(t *testing.T) {
+	assert.Equal(t, responseType("Other", map[string]interface{}{"content-type": "application/manifest+json"}), "Manifest")
+	assert.Equal(t, responseType("Image", map[string]interface{}{"Content-Type": "image/x-icon"}), "Image")
+	assert.Equal(t, responseType("Image", map[string]interface{}{"Content-Type": "image/png"}), "Image")
+
+	found := false
+	for _, p := range InterceptionPatterns(Options{InterceptManifests: true}) {
+		found = found || p.ResourceType == "Manifest"
+	}
+	assert.Equal(t, found, true)
+}
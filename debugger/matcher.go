@@ -0,0 +1,48 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd/gcdapi"
+)
+
+// matchData describes an intercepted request for the matchers of the options. The body is left empty, since
+// it is yet to be fetched when requests are matched
+func (d *Debugger) matchData(msg *gcdapi.NetworkRequestInterceptedEvent) modules.WebData {
+	data := modules.WebData{
+		Headers:      msg.Params.ResponseHeaders,
+		Type:         responseType(msg.Params.ResourceType, msg.Params.ResponseHeaders),
+		RequestId:    msg.Params.RequestId,
+		FrameId:      msg.Params.FrameId,
+		Navigation:   msg.Params.IsNavigationRequest,
+		WorkerScript: d.isWorkerScript(msg),
+		Initiator:    d.initiatorFor(msg.Params.RequestId),
+		Redirects:    d.redirectsFor(msg.Params.RequestId),
+	}
+	if req := msg.Params.Request; req != nil {
+		data.Url = req.Url
+		data.Method = req.Method
+		data.RequestHeaders = req.Headers
+	}
+	if isRequestStage(msg) {
+		data.Type = "Request"
+		data.Headers = data.RequestHeaders
+	}
+	return data
+}
+
+// blocked reports whether the request matches Options.BlockMatcher
+func (d *Debugger) blocked(data modules.WebData) bool {
+	return d.Options.BlockMatcher != nil && d.Options.BlockMatcher.Match(data)
+}
+
+// inMatcherScope reports whether the request matches Options.ScopeMatcher, which selects every request when nil
+func (d *Debugger) inMatcherScope(data modules.WebData) bool {
+	return d.Options.ScopeMatcher == nil || d.Options.ScopeMatcher.Match(data)
+}
+
+// processorMatches reports whether the matcher of the processor in Options.ProcessorMatchers, if any, selects
+// the web data
+func (d *Debugger) processorMatches(processor string, data modules.WebData) bool {
+	m, ok := d.Options.ProcessorMatchers[processor]
+	return !ok || m == nil || m.Match(data)
+}
@@ -0,0 +1,61 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+// headerMatcher selects web data whose request carries a header with the given value
+type headerMatcher struct {
+	header string
+	value  string
+}
+
+func (m headerMatcher) Match(webData modules.WebData) bool {
+	return headerValue(webData.RequestHeaders, m.header) == m.value
+}
+
+func taggedXhr(iid string, tag string) string {
+	return `{"interceptionId":"` + iid + `","requestId":"` + iid + `","frameId":"top-frame","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/` + iid + `","method":"GET","headers":{"X-Tag":"` + tag + `"}},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"application/json"}}`
+}
+
+func TestMatchers(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`, "2": `{"id":2}`, "3": `{"id":3}`, "4": `{"id":4}`}}
+	var processed []string
+	d := Debugger{
+		net: net,
+		Options: Options{
+			ScopeMatcher: modules.MatcherFunc(func(webData modules.WebData) bool {
+				return headerValue(webData.RequestHeaders, "x-tag") != "ignore"
+			}),
+			BlockMatcher:      headerMatcher{header: "x-tag", value: "block"},
+			ProcessorMatchers: map[string]modules.Matcher{"tagged": headerMatcher{header: "x-tag", value: "process"}},
+		},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{
+			countingProcessor("tagged", &processed),
+			countingProcessor("always", &processed),
+		}},
+	}
+
+	d.handleInterception(interceptedEvent(t, taggedXhr("1", "process")))
+	d.handleInterception(interceptedEvent(t, taggedXhr("2", "other")))
+	d.handleInterception(interceptedEvent(t, taggedXhr("3", "block")))
+	d.handleInterception(interceptedEvent(t, taggedXhr("4", "ignore")))
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 4)
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, calls[0].RawResponse), `{"id":1}/*tagged*//*always*/`), true)
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, calls[1].RawResponse), `{"id":2}/*always*/`), true)
+	assert.Equal(t, calls[2].ErrorReason, "BlockedByClient")
+	assert.Equal(t, calls[3].RawResponse, "")
+	assert.Equal(t, calls[3].ErrorReason, "")
+	assert.Equal(t, processed, []string{
+		"https://example.com/api/1",
+		"https://example.com/api/1",
+		"https://example.com/api/2",
+	})
+}
@@ -0,0 +1,108 @@
+package debugger
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/wirepair/gcd/gcdapi"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mockResponse is a response served for matching urls in place of the one from the server
+type mockResponse struct {
+	urlPattern string
+	pattern    *regexp.Regexp
+	status     int
+	headers    map[string]string
+	body       string
+}
+
+// MockResponse serves a fabricated response for every request whose url matches urlPattern, which uses
+// the same wildcards as Chrome interception patterns ('*' and '?'). Matching requests are intercepted before
+// they are sent, so they are fulfilled without ever reaching the server. Mocks can be added while the session
+// runs, interception is updated right away.
+func (d *Debugger) MockResponse(urlPattern string, status int, headers map[string]string, body string) {
+	d.mocksLock.Lock()
+	d.mocks = append(d.mocks, mockResponse{
+		urlPattern: urlPattern,
+		pattern:    wildcardRegexp(urlPattern),
+		status:     status,
+		headers:    headers,
+		body:       body,
+	})
+	d.mocksLock.Unlock()
+	if err := d.updateInterception(); err != nil {
+		d.log("[-] Unable to intercept requests to "+urlPattern, err)
+	}
+}
+
+// updateInterception sets the interception patterns again once mocks or pins changed. Nothing is done until
+// interception is set up, as SetupRequestInterception adds their patterns
+func (d *Debugger) updateInterception() error {
+	d.mocksLock.Lock()
+	if d.patterns == nil {
+		d.mocksLock.Unlock()
+		return nil
+	}
+	params := &gcdapi.NetworkSetRequestInterceptionParams{Patterns: d.withMockPatterns(d.patterns)}
+	d.mocksLock.Unlock()
+	_, err := d.network().SetRequestInterceptionWithParams(params)
+	return err
+}
+
+// withMockPatterns returns patterns along with a pattern intercepting the requests of every mock before they are
+// sent, and one intercepting the responses to every pinned url pattern. It must be called with mocksLock held.
+func (d *Debugger) withMockPatterns(patterns []*gcdapi.NetworkRequestPattern) []*gcdapi.NetworkRequestPattern {
+	result := append([]*gcdapi.NetworkRequestPattern{}, patterns...)
+	for _, m := range d.mocks {
+		result = append(result, &gcdapi.NetworkRequestPattern{
+			UrlPattern:        m.urlPattern,
+			InterceptionStage: "Request",
+		})
+	}
+	return append(result, d.pinPatterns()...)
+}
+
+// mockFor returns the mock registered for the url, if any
+func (d *Debugger) mockFor(url string) *mockResponse {
+	d.mocksLock.RLock()
+	defer d.mocksLock.RUnlock()
+	for i := range d.mocks {
+		if d.mocks[i].pattern.MatchString(url) {
+			return &d.mocks[i]
+		}
+	}
+	return nil
+}
+
+// rawResponse builds a base64 encoded raw HTTP response, as expected by ContinueInterceptedRequest
+func rawResponse(status int, headers map[string]string, body string) string {
+	raw := fmt.Sprintf("HTTP/1.1 %d %s\r\n", status, http.StatusText(status))
+	names := make([]string, 0, len(headers))
+	hasLength := false
+	for k := range headers {
+		names = append(names, k)
+		hasLength = hasLength || strings.EqualFold(k, "content-length")
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		raw += k + ": " + headers[k] + "\r\n"
+	}
+	if !hasLength {
+		raw += "Content-Length: " + strconv.Itoa(len(body)) + "\r\n"
+	}
+	raw += "\r\n" + body
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+// wildcardRegexp compiles a Chrome interception url pattern, where '*' matches any number of characters
+// and '?' a single one, into a regular expression matching the whole url
+func wildcardRegexp(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.Replace(expr, `\*`, ".*", -1)
+	expr = strings.Replace(expr, `\?`, ".", -1)
+	return regexp.MustCompile("^" + expr + "$")
+}
@@ -0,0 +1,188 @@
+package debugger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"github.com/wirepair/gcd/gcdmessage"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// the debugger logs every intercepted request
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// continued records a call to ContinueInterceptedRequest
+type continued struct {
+	InterceptionId string
+	ErrorReason    string
+	RawResponse    string
+	Url            string
+	Method         string
+	PostData       string
+	Headers        map[string]interface{}
+	Auth           *gcdapi.NetworkAuthChallengeResponse
+}
+
+// mockNetwork stands in for the Chrome Network domain, serving bodies from a map of interception ids
+type mockNetwork struct {
+	mu          sync.Mutex
+	bodies      map[string]string
+	continued   []continued
+	delay       time.Duration // Time taken to fetch a body
+	encoded     bool          // Bodies are returned base64 encoded, as Chrome does for binary content
+	failures    int           // Number of calls to ContinueInterceptedRequest failing before they succeed
+	cleared     []string      // Browser state cleared, "cache" or "cookies", in order
+	clearErr    error         // Error returned when clearing browser state
+	inflight    int
+	maxInflight int
+	patterns    []*gcdapi.NetworkRequestPattern // Patterns last passed to SetRequestInterceptionWithParams
+}
+
+func (m *mockNetwork) GetResponseBodyForInterception(interceptionId string) (string, bool, error) {
+	m.mu.Lock()
+	m.inflight++
+	if m.inflight > m.maxInflight {
+		m.maxInflight = m.inflight
+	}
+	m.mu.Unlock()
+
+	time.Sleep(m.delay)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inflight--
+	if m.encoded {
+		return base64.StdEncoding.EncodeToString([]byte(m.bodies[interceptionId])), true, nil
+	}
+	return m.bodies[interceptionId], false, nil
+}
+
+func (m *mockNetwork) ContinueInterceptedRequest(interceptionId string, errorReason string, rawResponse string, url string,
+	method string, postData string, headers map[string]interface{},
+	authChallengeResponse *gcdapi.NetworkAuthChallengeResponse) (*gcdmessage.ChromeResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.continued = append(m.continued, continued{interceptionId, errorReason, rawResponse, url, method, postData, headers, authChallengeResponse})
+	if m.failures > 0 {
+		m.failures--
+		return nil, errors.New("websocket closed")
+	}
+	return &gcdmessage.ChromeResponse{}, nil
+}
+
+func (m *mockNetwork) ClearBrowserCache() (*gcdmessage.ChromeResponse, error) {
+	return m.clear("cache")
+}
+
+func (m *mockNetwork) ClearBrowserCookies() (*gcdmessage.ChromeResponse, error) {
+	return m.clear("cookies")
+}
+
+func (m *mockNetwork) SetRequestInterceptionWithParams(v *gcdapi.NetworkSetRequestInterceptionParams) (*gcdmessage.ChromeResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.patterns = v.Patterns
+	return &gcdmessage.ChromeResponse{}, nil
+}
+
+func (m *mockNetwork) clear(state string) (*gcdmessage.ChromeResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cleared = append(m.cleared, state)
+	if m.clearErr != nil {
+		return nil, m.clearErr
+	}
+	return &gcdmessage.ChromeResponse{}, nil
+}
+
+func (m *mockNetwork) calls() []continued {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]continued(nil), m.continued...)
+}
+
+// interceptedEvent builds a Network.requestIntercepted event from its JSON representation
+func interceptedEvent(t *testing.T, params string) *gcdapi.NetworkRequestInterceptedEvent {
+	msg := &gcdapi.NetworkRequestInterceptedEvent{}
+	if err := json.Unmarshal([]byte(`{"method":"Network.requestIntercepted","params":`+params+`}`), msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+// mockPage stands in for the Chrome Page domain
+type mockPage struct {
+	mu          sync.Mutex
+	screenshot  []byte
+	contentSize gcdapi.DOMRect
+	captures    []*gcdapi.PageCaptureScreenshotParams
+	navigations []string
+	navErrors   []string // Error text returned by successive navigations, before they succeed
+	onNavigate  func()   // Called after a navigation succeeds, to fire the load event
+	dialogs     []*gcdapi.PageHandleJavaScriptDialogParams
+}
+
+func (m *mockPage) CaptureScreenshotWithParams(v *gcdapi.PageCaptureScreenshotParams) (string, error) {
+	m.captures = append(m.captures, v)
+	return base64.StdEncoding.EncodeToString(m.screenshot), nil
+}
+
+func (m *mockPage) GetLayoutMetrics() (*gcdapi.PageLayoutViewport, *gcdapi.PageVisualViewport, *gcdapi.DOMRect, error) {
+	return &gcdapi.PageLayoutViewport{}, &gcdapi.PageVisualViewport{}, &m.contentSize, nil
+}
+
+func (m *mockPage) NavigateWithParams(v *gcdapi.PageNavigateParams) (string, string, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.navigations = append(m.navigations, v.Url)
+	if len(m.navErrors) > 0 {
+		errorText := m.navErrors[0]
+		m.navErrors = m.navErrors[1:]
+		return "top", "", errorText, nil
+	}
+	if m.onNavigate != nil {
+		go m.onNavigate()
+	}
+	return "top", "loader", "", nil
+}
+
+// navigated returns the urls navigated to so far
+func (m *mockPage) navigated() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.navigations...)
+}
+
+func (m *mockPage) HandleJavaScriptDialogWithParams(v *gcdapi.PageHandleJavaScriptDialogParams) (*gcdmessage.ChromeResponse, error) {
+	m.dialogs = append(m.dialogs, v)
+	return nil, nil
+}
+
+// mockEvents stands in for the events of a Chrome target, delivering events fired by tests to subscribers
+type mockEvents struct {
+	callbacks map[string]func(*gcd.ChromeTarget, []byte)
+}
+
+func (m *mockEvents) Subscribe(method string, callback func(*gcd.ChromeTarget, []byte)) {
+	if m.callbacks == nil {
+		m.callbacks = make(map[string]func(*gcd.ChromeTarget, []byte))
+	}
+	m.callbacks[method] = callback
+}
+
+// fire delivers an event with the JSON representation of its params, as Chrome sends it
+func (m *mockEvents) fire(method string, params string) {
+	if callback := m.callbacks[method]; callback != nil {
+		callback(nil, []byte(`{"method":"`+method+`","Params":`+params+`}`))
+	}
+}
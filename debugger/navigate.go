@@ -0,0 +1,93 @@
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"time"
+)
+
+// defaultNavigationTimeout is how long Navigate waits for a page to load when Options.NavigationTimeout is 0
+const defaultNavigationTimeout = 30 * time.Second
+
+// errLoadTimeout is returned by navigate when the page does not load in time
+var errLoadTimeout = errors.New("page did not load in time")
+
+// Navigate loads url in the debugger tab and waits for the page to load. Navigations that fail or do not load
+// within Options.NavigationTimeout are retried up to Options.NavigationRetries times, so that a flaky network
+// does not leave the tab stuck on an error page.
+// It returns an error once every attempt failed
+func (d *Debugger) Navigate(url string) error {
+	var err error
+	for attempt := 0; attempt <= d.Options.NavigationRetries; attempt++ {
+		if attempt > 0 {
+			d.log(fmt.Sprintf("[-] Navigation to %s failed, retrying (%d/%d)", url, attempt,
+				d.Options.NavigationRetries), err)
+		}
+		if err = d.navigate(url); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("unable to navigate to %s: %s", url, err)
+}
+
+// navigate makes a single attempt at loading url, waiting for the load event of the page
+func (d *Debugger) navigate(url string) error {
+	loaded := make(chan struct{})
+	d.loadsLock.Lock()
+	d.loadWaiter = loaded
+	d.loadsLock.Unlock()
+	defer func() {
+		d.loadsLock.Lock()
+		if d.loadWaiter == loaded {
+			d.loadWaiter = nil
+		}
+		d.loadsLock.Unlock()
+	}()
+
+	_, _, errorText, err := d.page().NavigateWithParams(&gcdapi.PageNavigateParams{Url: url})
+	if err != nil {
+		return err
+	}
+	if errorText != "" {
+		return errors.New(errorText)
+	}
+
+	timeout := d.Options.NavigationTimeout
+	if timeout == 0 {
+		timeout = defaultNavigationTimeout
+	}
+	select {
+	case <-loaded:
+		return nil
+	case <-time.After(timeout):
+		return errLoadTimeout
+	}
+}
+
+// trackLoads subscribes to the load events of the page, for Navigate and screenshots
+func (d *Debugger) trackLoads() {
+	d.loadsOnce.Do(func() {
+		d.events().Subscribe("Page.loadEventFired", func(target *gcd.ChromeTarget, v []byte) {
+			d.pageLoaded()
+		})
+	})
+}
+
+// pageLoaded ends the wait of a pending navigation and captures a screenshot when they are enabled
+func (d *Debugger) pageLoaded() {
+	d.loadsLock.Lock()
+	if d.loadWaiter != nil {
+		close(d.loadWaiter)
+		d.loadWaiter = nil
+	}
+	screenshots := d.screenshots
+	d.loadsLock.Unlock()
+
+	if screenshots {
+		if _, err := d.captureScreenshot(); err != nil {
+			d.log("[-] Unable to capture screenshot", err)
+		}
+	}
+}
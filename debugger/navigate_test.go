@@ -0,0 +1,48 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNavigateRetries(t *testing.T) {
+	page := &mockPage{navErrors: []string{"net::ERR_CONNECTION_RESET"}}
+	d := Debugger{pg: page, Options: Options{NavigationRetries: 2, NavigationTimeout: time.Second}}
+	page.onNavigate = d.pageLoaded
+
+	assert.Equal(t, d.Navigate("https://example.com/"), nil)
+	assert.Equal(t, page.navigated(), []string{"https://example.com/", "https://example.com/"})
+}
+
+func TestNavigateGivesUp(t *testing.T) {
+	page := &mockPage{navErrors: []string{"net::ERR_CONNECTION_RESET", "net::ERR_CONNECTION_RESET",
+		"net::ERR_NAME_NOT_RESOLVED"}}
+	d := Debugger{pg: page, Options: Options{NavigationRetries: 1, NavigationTimeout: time.Second}}
+	page.onNavigate = d.pageLoaded
+
+	err := d.Navigate("https://example.com/")
+	assert.Equal(t, err != nil, true)
+	assert.Equal(t, strings.Contains(err.Error(), "net::ERR_CONNECTION_RESET"), true)
+	assert.Equal(t, len(page.navigated()), 2)
+}
+
+func TestNavigateLoadTimeout(t *testing.T) {
+	// the first load never fires, the retry loads
+	page := &mockPage{}
+	d := Debugger{pg: page, Options: Options{NavigationRetries: 1, NavigationTimeout: 50 * time.Millisecond}}
+	page.onNavigate = func() {
+		if len(page.navigated()) > 1 {
+			d.pageLoaded()
+		}
+	}
+
+	assert.Equal(t, d.Navigate("https://example.com/"), nil)
+	assert.Equal(t, len(page.navigated()), 2)
+
+	d.Options.NavigationRetries = 0
+	page.onNavigate = nil
+	err := d.Navigate("https://example.com/")
+	assert.Equal(t, strings.Contains(err.Error(), errLoadTimeout.Error()), true)
+}
@@ -0,0 +1,39 @@
+package debugger
+
+import "github.com/DharmaOfCode/gorp/modules"
+
+// OnNavigation registers a callback run every time the top frame starts a navigation, before any module sees
+// the document, so that per page state can be reset. It must be registered before SetupRequestInterception
+func (d *Debugger) OnNavigation(fn func(url string)) {
+	d.onNavigation = fn
+}
+
+// navigated runs what is set to happen when the top frame navigates to url
+func (d *Debugger) navigated(url string) {
+	if d.Options.ResetFindingsOnNavigation {
+		d.Findings.Reset()
+	}
+	if d.onNavigation != nil {
+		d.onNavigation(url)
+	}
+}
+
+// runsOnNavigations reports whether the named processor runs on documents loaded by navigations
+func (d *Debugger) runsOnNavigations(processor string) bool {
+	if len(d.Options.NavigationProcessors) == 0 {
+		return true
+	}
+	for _, p := range d.Options.NavigationProcessors {
+		if p == processor {
+			return true
+		}
+	}
+	return false
+}
+
+// inspectNavigation passes a navigation of the top frame to url to the inspectors as web data of type
+// "Navigation". Unlike documents, these include navigations that load no document, such as those made with
+// the history API by single page applications
+func (d *Debugger) inspectNavigation(url string) {
+	d.CallInspectors(modules.WebData{Type: "Navigation", Url: url, Findings: d.Findings})
+}
@@ -0,0 +1,102 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"testing"
+	"time"
+)
+
+func TestOnNavigation(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"2": `{"id":2}`}}
+	d := Debugger{
+		net:      net,
+		Findings: &modules.Findings{},
+		Options:  Options{ResetFindingsOnNavigation: true},
+	}
+	var navigated []string
+	d.OnNavigation(func(url string) {
+		navigated = append(navigated, url)
+	})
+
+	d.Findings.Report(modules.Finding{Rule: "test", Url: "https://example.com/"})
+	d.handleInterception(navigation(t, "1", "https://example.com/account"))
+	assert.Equal(t, navigated, []string{"https://example.com/account"})
+	assert.Equal(t, len(d.Findings.All()), 0)
+
+	// other requests are not navigations
+	d.handleInterception(xhrResponse(t, "2", "r2"))
+	assert.Equal(t, len(navigated), 1)
+}
+
+func TestNavigationProcessors(t *testing.T) {
+	var ran []string
+	processor := func(name string) modules.ProcessorModule {
+		return modules.ProcessorModule{
+			Registry: modules.Registry{Name: name},
+			Process: func(webData modules.WebData) (string, error) {
+				ran = append(ran, name+" "+webData.Url)
+				return webData.Body, nil
+			},
+		}
+	}
+	net := &mockNetwork{bodies: map[string]string{"1": "<html></html>", "2": `{"id":2}`}}
+	d := Debugger{
+		net:     net,
+		Options: Options{NavigationProcessors: []string{"page"}},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{processor("page"), processor("api")}},
+	}
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","frameId":"top-frame","resourceType":"Document",
+		"isNavigationRequest":true,"request":{"url":"https://example.com/","method":"GET"},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"text/html"}}`))
+	d.handleInterception(xhrResponse(t, "2", "r2"))
+
+	assert.Equal(t, ran, []string{
+		"page https://example.com/",
+		"page https://example.com/api/r2",
+		"api https://example.com/api/r2",
+	})
+	assert.Equal(t, net.calls()[0].RawResponse, "")
+}
+
+func TestNavigationsInspected(t *testing.T) {
+	captured := make(chan modules.WebData, 10)
+	events := &mockEvents{}
+	d := Debugger{
+		ev: events,
+		Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+			Registry: modules.Registry{Name: "NavigationLogger"},
+			Inspect: func(webData modules.WebData) error {
+				captured <- webData
+				return nil
+			},
+		}}},
+	}
+	d.trackFrames()
+
+	events.fire("Page.frameNavigated", `{"frame":{"id":"top-frame","url":"https://example.com/","urlFragment":"#/login"}}`)
+	// navigations of subframes are left out
+	events.fire("Page.frameNavigated", `{"frame":{"id":"ad-frame","parentId":"top-frame","url":"https://ads.example.net/"}}`)
+	events.fire("Page.navigatedWithinDocument", `{"frameId":"ad-frame","url":"https://ads.example.net/#next"}`)
+	// history.pushState("", "", "/users/42")
+	events.fire("Page.navigatedWithinDocument", `{"frameId":"top-frame","url":"https://example.com/users/42"}`)
+
+	var urls []string
+	timeout := time.After(time.Second)
+	for len(urls) < 2 {
+		select {
+		case w := <-captured:
+			assert.Equal(t, w.Type, "Navigation")
+			urls = append(urls, w.Url)
+		case <-timeout:
+			t.Fatal("navigations were not passed to inspectors")
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, len(captured), 0)
+	if urls[0] != "https://example.com/#/login" {
+		urls[0], urls[1] = urls[1], urls[0]
+	}
+	assert.Equal(t, urls, []string{"https://example.com/#/login", "https://example.com/users/42"})
+}
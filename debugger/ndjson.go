@@ -0,0 +1,33 @@
+package debugger
+
+import (
+	"fmt"
+	"github.com/DharmaOfCode/gorp/modules"
+)
+
+// isNDJSON reports whether the body of data is newline-delimited JSON, going by its Content-Type header or, for
+// bodies served as JSON, plain text or without a type, by its content
+func isNDJSON(data modules.WebData) bool {
+	return modules.IsNDJSONBody(headerValue(data.Headers, "Content-Type"), data.Body)
+}
+
+// lineProcessor returns p with Process rewriting the body one line at a time with its ProcessLine function,
+// so that it runs like any other processor, for timeouts, safe mode and the change log
+func lineProcessor(p modules.ProcessorModule) modules.ProcessorModule {
+	processLine := p.ProcessLine
+	p.Process = func(webData modules.WebData) (string, error) {
+		return modules.MapNDJSON(webData.Body, func(line string, index int) (string, error) {
+			return processLine(webData, line, index)
+		})
+	}
+	return p
+}
+
+// inspectLines passes each line of the NDJSON body of webData to the InspectLine function of an inspector
+func (d *Debugger) inspectLines(i modules.InspectorModule, webData modules.WebData) {
+	for index, line := range modules.NDJSONLines(webData.Body) {
+		if err := i.InspectLine(webData, line, index); err != nil {
+			d.log(fmt.Sprintf("[-] Inspector %s failed on line %d of %s", i.Registry.Name, index, webData.Url), err)
+		}
+	}
+}
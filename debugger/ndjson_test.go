@@ -0,0 +1,67 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const ndjsonStream = "{\"id\":1,\"status\":\"queued\"}\n{\"id\":2,\"status\":\"running\"}\n\n{\"id\":3,\"status\":\"done\"}\n"
+
+func TestNDJSONLines(t *testing.T) {
+	// inspectors run in the background once the response is continued
+	inspected := make(chan string, 1)
+	lines := make(chan string, 3)
+	net := &mockNetwork{bodies: map[string]string{"1": ndjsonStream}}
+	d := Debugger{
+		net: net,
+		Modules: modules.Modules{
+			Processors: []modules.ProcessorModule{{
+				Registry: modules.Registry{Name: "Status", DocTypes: []string{"XHR"}},
+				Process: func(webData modules.WebData) (string, error) {
+					return webData.Body + "garbage", nil
+				},
+				ProcessLine: func(webData modules.WebData, line string, index int) (string, error) {
+					return strings.Replace(line, `"queued"`, `"done"`, 1), nil
+				},
+			}},
+			Inspectors: []modules.InspectorModule{{
+				Registry: modules.Registry{Name: "Lines"},
+				Inspect: func(webData modules.WebData) error {
+					inspected <- webData.Body
+					return nil
+				},
+				InspectLine: func(webData modules.WebData, line string, index int) error {
+					lines <- strconv.Itoa(index) + " " + line
+					return nil
+				},
+			}},
+		},
+	}
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","requestId":"1","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/jobs","method":"GET"},"responseStatusCode":200,
+		"responseHeaders":{"Content-Type":"application/x-ndjson"}}`))
+
+	assert.Equal(t, <-inspected, ndjsonStream)
+	assert.Equal(t, []string{<-lines, <-lines, <-lines}, []string{
+		`0 {"id":1,"status":"queued"}`,
+		`1 {"id":2,"status":"running"}`,
+		`2 {"id":3,"status":"done"}`,
+	})
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	body := strings.SplitN(decodeRaw(t, calls[0].RawResponse), "\r\n\r\n", 2)[1]
+	assert.Equal(t, body, strings.Replace(ndjsonStream, `"queued"`, `"done"`, 1))
+}
+
+func TestNDJSONDetection(t *testing.T) {
+	jsonHeaders := map[string]interface{}{"Content-Type": "application/json"}
+	assert.Equal(t, isNDJSON(modules.WebData{Body: ndjsonStream, Headers: jsonHeaders}), true)
+	assert.Equal(t, isNDJSON(modules.WebData{Body: `{"id":1}`, Headers: jsonHeaders}), false)
+	assert.Equal(t, isNDJSON(modules.WebData{Body: ndjsonStream,
+		Headers: map[string]interface{}{"Content-Type": "text/html"}}), false)
+}
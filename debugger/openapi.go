@@ -0,0 +1,378 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// identifierSegment matches the path segments that look like identifiers, such as numbers, UUIDs or object ids,
+// which are turned into path parameters so that requests for different objects share an endpoint
+var identifierSegment = regexp.MustCompile(`^(?:\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{24,})$`)
+
+// openAPIDocument is the subset of an OpenAPI 3 document ExportOpenAPI writes
+type openAPIDocument struct {
+	OpenAPI string                                  `json:"openapi"`
+	Info    openAPIInfo                             `json:"info"`
+	Servers []openAPIServer                         `json:"servers,omitempty"`
+	Paths   map[string]map[string]*openAPIOperation `json:"paths"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIServer struct {
+	Url string `json:"url"`
+}
+
+type openAPIOperation struct {
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIBody                `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIBody struct {
+	Content map[string]*openAPIMedia `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                   `json:"description"`
+	Content     map[string]*openAPIMedia `json:"content,omitempty"`
+}
+
+type openAPIMedia struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+// openAPISchema is a schema inferred from sample values. A schema without a type accepts any value
+type openAPISchema struct {
+	Type       string                    `json:"type,omitempty"`
+	Nullable   bool                      `json:"nullable,omitempty"`
+	Items      *openAPISchema            `json:"items,omitempty"`
+	Properties map[string]*openAPISchema `json:"properties,omitempty"`
+
+	mixed bool // Whether samples of different types were seen
+}
+
+// isEmpty reports whether nothing is known of the values, as for the items of an empty array
+func (s *openAPISchema) isEmpty() bool {
+	return s.Type == "" && !s.Nullable && !s.mixed
+}
+
+// isNull reports whether the only samples seen were null
+func (s *openAPISchema) isNull() bool {
+	return s.Type == "" && s.Nullable && !s.mixed
+}
+
+// endpoints holds the API endpoints observed during the session, for ExportOpenAPI. It is guarded by
+// Debugger.endpointsLock
+type endpoints struct {
+	operations map[string]map[string]*openAPIOperation // Operations by path template and lower case method
+	servers    map[string]bool                         // Origins the requests were sent to
+}
+
+// recordEndpoint adds what an XHR or fetch request and its response tell about an API endpoint to the endpoints
+// observed during the session
+func (d *Debugger) recordEndpoint(data modules.WebData) {
+	d.endpointsLock.Lock()
+	defer d.endpointsLock.Unlock()
+	d.endpoints.add(data)
+}
+
+// add adds what an XHR or fetch request and its response tell about an API endpoint: its path, query
+// parameters, and the shapes of JSON and form bodies
+func (e *endpoints) add(data modules.WebData) {
+	u, err := url.Parse(data.Url)
+	if err != nil || u.Host == "" {
+		return
+	}
+	path, pathParams := pathTemplate(u.Path)
+	method := strings.ToLower(data.Method)
+	if method == "" {
+		method = "get"
+	}
+
+	if e.operations == nil {
+		e.operations = make(map[string]map[string]*openAPIOperation)
+		e.servers = make(map[string]bool)
+	}
+	e.servers[u.Scheme+"://"+u.Host] = true
+	if e.operations[path] == nil {
+		e.operations[path] = make(map[string]*openAPIOperation)
+	}
+	op := e.operations[path][method]
+	if op == nil {
+		op = &openAPIOperation{Responses: make(map[string]*openAPIResponse)}
+		for _, name := range pathParams {
+			op.addParameter(name, "path", &openAPISchema{Type: "string"})
+		}
+		e.operations[path][method] = op
+	}
+
+	query := u.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		op.addParameter(name, "query", valueSchema(query.Get(name)))
+	}
+	if data.RequestBody != "" {
+		contentType, schema := bodySchema(headerValue(data.RequestHeaders, "content-type"), data.RequestBody)
+		if schema != nil {
+			if op.RequestBody == nil {
+				op.RequestBody = &openAPIBody{Content: make(map[string]*openAPIMedia)}
+			}
+			op.RequestBody.Content[contentType] = mergeMedia(op.RequestBody.Content[contentType], schema)
+		}
+	}
+
+	code := strconv.Itoa(data.Status)
+	if data.Status == 0 {
+		code = "default"
+	}
+	res := op.Responses[code]
+	if res == nil {
+		res = &openAPIResponse{Description: "Observed response"}
+		op.Responses[code] = res
+	}
+	if contentType, schema := bodySchema(headerValue(data.Headers, "content-type"), data.Body); schema != nil {
+		if res.Content == nil {
+			res.Content = make(map[string]*openAPIMedia)
+		}
+		res.Content[contentType] = mergeMedia(res.Content[contentType], schema)
+	}
+}
+
+// addParameter adds a parameter to the operation, or merges the type of the sample with that of the
+// parameter already known
+func (op *openAPIOperation) addParameter(name string, in string, schema *openAPISchema) {
+	for _, p := range op.Parameters {
+		if p.Name == name && p.In == in {
+			p.Schema = mergeSchema(p.Schema, schema)
+			return
+		}
+	}
+	op.Parameters = append(op.Parameters, &openAPIParameter{Name: name, In: in, Required: in == "path", Schema: schema})
+}
+
+// pathTemplate replaces the segments of path that look like identifiers with parameters.
+// It returns the template and the names of its parameters
+func pathTemplate(path string) (string, []string) {
+	if path == "" {
+		return "/", nil
+	}
+	var params []string
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if !identifierSegment.MatchString(s) {
+			continue
+		}
+		name := "id"
+		if len(params) > 0 {
+			name += strconv.Itoa(len(params) + 1)
+		}
+		params = append(params, name)
+		segments[i] = "{" + name + "}"
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// bodySchema infers the schema of a JSON or url encoded form body.
+// It returns the media type of the body and its schema, nil when the body is neither
+func bodySchema(contentType string, body string) (string, *openAPISchema) {
+	kind := modules.ContentKind(contentType)
+	if kind == "application/x-www-form-urlencoded" {
+		form, err := url.ParseQuery(body)
+		if err != nil || len(form) == 0 {
+			return "", nil
+		}
+		schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+		for name, values := range form {
+			schema.Properties[name] = valueSchema(values[0])
+		}
+		return kind, schema
+	}
+	if kind != "" && kind != "application/json" && !strings.HasSuffix(kind, "+json") && kind != "text/plain" {
+		return "", nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil || decoder.More() {
+		return "", nil
+	}
+	return "application/json", jsonSchema(v)
+}
+
+// jsonSchema infers the schema of a decoded JSON value, numbers being decoded as json.Number
+func jsonSchema(v interface{}) *openAPISchema {
+	switch v := v.(type) {
+	case nil:
+		return &openAPISchema{Nullable: true}
+	case bool:
+		return &openAPISchema{Type: "boolean"}
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &openAPISchema{Type: "integer"}
+		}
+		return &openAPISchema{Type: "number"}
+	case string:
+		return &openAPISchema{Type: "string"}
+	case []interface{}:
+		items := &openAPISchema{}
+		for i, e := range v {
+			if i == 0 {
+				items = jsonSchema(e)
+			} else {
+				items = mergeSchema(items, jsonSchema(e))
+			}
+		}
+		return &openAPISchema{Type: "array", Items: items}
+	case map[string]interface{}:
+		schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+		for k, e := range v {
+			schema.Properties[k] = jsonSchema(e)
+		}
+		return schema
+	}
+	return &openAPISchema{}
+}
+
+// valueSchema infers the schema of a query parameter or form field from its text
+func valueSchema(s string) *openAPISchema {
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return &openAPISchema{Type: "integer"}
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return &openAPISchema{Type: "number"}
+	}
+	if s == "true" || s == "false" {
+		return &openAPISchema{Type: "boolean"}
+	}
+	return &openAPISchema{Type: "string"}
+}
+
+// mergeSchema combines the schemas of two samples of the same value. Properties of objects are merged, integers
+// widen to numbers and values seen with different types are left without a type
+func mergeSchema(a *openAPISchema, b *openAPISchema) *openAPISchema {
+	switch {
+	case a == nil || a.isEmpty():
+		return b
+	case b == nil || b.isEmpty():
+		return a
+	case a.isNull() || b.isNull():
+		merged := *b
+		if b.isNull() {
+			merged = *a
+		}
+		merged.Nullable = true
+		return &merged
+	}
+	merged := &openAPISchema{Type: a.Type, Nullable: a.Nullable || b.Nullable}
+	switch {
+	case a.mixed || b.mixed:
+		return &openAPISchema{Nullable: merged.Nullable, mixed: true}
+	case a.Type == b.Type && a.Type == "object":
+		merged.Properties = make(map[string]*openAPISchema)
+		for k, v := range a.Properties {
+			merged.Properties[k] = v
+		}
+		for k, v := range b.Properties {
+			merged.Properties[k] = mergeSchema(merged.Properties[k], v)
+		}
+	case a.Type == b.Type && a.Type == "array":
+		merged.Items = mergeSchema(a.Items, b.Items)
+	case a.Type == b.Type:
+	case (a.Type == "integer" || a.Type == "number") && (b.Type == "integer" || b.Type == "number"):
+		merged.Type = "number"
+	default:
+		return &openAPISchema{Nullable: merged.Nullable, mixed: true}
+	}
+	return merged
+}
+
+// mergeMedia adds a sample schema to the schema of a body
+func mergeMedia(m *openAPIMedia, schema *openAPISchema) *openAPIMedia {
+	if m == nil {
+		return &openAPIMedia{Schema: schema}
+	}
+	m.Schema = mergeSchema(m.Schema, schema)
+	return m
+}
+
+// ExportOpenAPI writes the API endpoints observed in XHR and fetch requests so far to path, as an OpenAPI 3
+// document in JSON. Identifiers in paths become path parameters, and the types of parameters and of JSON and
+// form bodies are inferred from the values seen. The document is a skeleton to start documenting an API from,
+// it only describes what the page happened to send and receive. When the session is recorded to a store, the
+// endpoints are read back from the store. Fetches are only intercepted when Options.OpenAPIFile or
+// Options.InterceptBinary is set, the document covers XHR only otherwise
+func (d *Debugger) ExportOpenAPI(path string) error {
+	encoded, err := d.encodeOpenAPI()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, encoded, 0644)
+}
+
+// encodeOpenAPI returns the OpenAPI document of the endpoints observed so far in JSON. The document shares the
+// endpoints recorded in memory, which intercepted responses keep adding to, so it is encoded before their lock
+// is released
+func (d *Debugger) encodeOpenAPI() ([]byte, error) {
+	if d.store == nil {
+		d.endpointsLock.Lock()
+		defer d.endpointsLock.Unlock()
+	}
+	doc, err := d.openAPI()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// openAPI builds the OpenAPI document of the endpoints observed so far. Callers hold Debugger.endpointsLock
+// when the session is not recorded to a store
+func (d *Debugger) openAPI() (*openAPIDocument, error) {
+	var e endpoints
+	if d.store != nil {
+		err := d.store.Iterate(func(webData modules.WebData) error {
+			if webData.Type == "XHR" || webData.Type == "Fetch" {
+				e.add(webData)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		e = d.endpoints
+	}
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    openAPIInfo{Title: "Observed API", Version: "1.0.0"},
+		Paths:   make(map[string]map[string]*openAPIOperation),
+	}
+	for server := range e.servers {
+		doc.Servers = append(doc.Servers, openAPIServer{Url: server})
+	}
+	sort.Slice(doc.Servers, func(i, j int) bool { return doc.Servers[i].Url < doc.Servers[j].Url })
+	for path, operations := range e.operations {
+		doc.Paths[path] = operations
+	}
+	return doc, nil
+}
@@ -0,0 +1,152 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestExportOpenAPI(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{
+		"1": `{"id": 42, "name": "alice", "roles": ["admin"], "manager": null}`,
+		"2": `{"id": 7, "name": "bob", "roles": [], "manager": {"id": 42}}`,
+		"3": `{"ok": true, "score": 1.5}`,
+	}}
+	d := Debugger{net: net}
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/users/42?fields=name&limit=10","method":"GET"},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"application/json"}}`))
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"2","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/users/7","method":"GET"},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"application/json; charset=utf-8"}}`))
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"3","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/login","method":"POST",
+		"headers":{"Content-Type":"application/json"},"postData":"{\"user\":\"alice\",\"remember\":true}"},
+		"responseStatusCode":201,"responseHeaders":{"Content-Type":"application/json"}}`))
+
+	dir, err := ioutil.TempDir("", "gorp-openapi")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "openapi.json")
+	assert.Equal(t, d.ExportOpenAPI(path), nil)
+
+	encoded, err := ioutil.ReadFile(path)
+	assert.Equal(t, err, nil)
+	var spec struct {
+		OpenAPI string
+		Servers []struct{ Url string }
+		Paths   map[string]map[string]struct {
+			Parameters []struct {
+				Name     string
+				In       string
+				Required bool
+				Schema   map[string]interface{}
+			}
+			RequestBody struct {
+				Content map[string]struct{ Schema map[string]interface{} }
+			}
+			Responses map[string]struct {
+				Description string
+				Content     map[string]struct{ Schema map[string]interface{} }
+			}
+		}
+	}
+	assert.Equal(t, json.Unmarshal(encoded, &spec), nil)
+	assert.Equal(t, spec.OpenAPI, "3.0.3")
+	assert.Equal(t, len(spec.Servers), 1)
+	assert.Equal(t, spec.Servers[0].Url, "https://example.com")
+	assert.Equal(t, len(spec.Paths), 2)
+
+	user := spec.Paths["/api/users/{id}"]["get"]
+	assert.Equal(t, len(user.Parameters), 3)
+	assert.Equal(t, user.Parameters[0].Name, "id")
+	assert.Equal(t, user.Parameters[0].In, "path")
+	assert.Equal(t, user.Parameters[0].Required, true)
+	for _, p := range user.Parameters[1:] {
+		assert.Equal(t, p.In, "query")
+		assert.Equal(t, p.Required, false)
+		if p.Name == "limit" {
+			assert.Equal(t, p.Schema["type"], "integer")
+		}
+	}
+	assert.Equal(t, user.Responses["200"].Description != "", true)
+	schema := user.Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, schema["type"], "object")
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, properties["id"], map[string]interface{}{"type": "integer"})
+	assert.Equal(t, properties["roles"], map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}})
+	assert.Equal(t, properties["manager"], map[string]interface{}{"type": "object", "nullable": true,
+		"properties": map[string]interface{}{"id": map[string]interface{}{"type": "integer"}}})
+
+	login := spec.Paths["/api/login"]["post"]
+	assert.Equal(t, login.RequestBody.Content["application/json"].Schema["properties"], map[string]interface{}{
+		"user":     map[string]interface{}{"type": "string"},
+		"remember": map[string]interface{}{"type": "boolean"},
+	})
+	score := login.Responses["201"].Content["application/json"].Schema["properties"].(map[string]interface{})["score"]
+	assert.Equal(t, score, map[string]interface{}{"type": "number"})
+}
+
+func TestExportOpenAPIWhileRecording(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-openapi")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "openapi.json")
+
+	// responses still in flight keep recording endpoints while the session stops, run with -race
+	d := Debugger{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			d.recordEndpoint(modules.WebData{
+				Url:     "https://example.com/api/items/" + strconv.Itoa(i) + "?page=" + strconv.Itoa(i),
+				Method:  "GET",
+				Status:  200 + i%3,
+				Headers: map[string]interface{}{"Content-Type": "application/json"},
+				Body:    `{"field` + strconv.Itoa(i) + `": 1}`,
+			})
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, d.ExportOpenAPI(path), nil)
+	}
+	<-done
+	assert.Equal(t, d.ExportOpenAPI(path), nil)
+}
+
+func TestOpenAPIInterceptsFetch(t *testing.T) {
+	fetches := 0
+	for _, p := range InterceptionPatterns(Options{OpenAPIFile: "openapi.json"}) {
+		if p.ResourceType == "Fetch" && p.InterceptionStage == "HeadersReceived" {
+			fetches++
+		}
+	}
+	assert.Equal(t, fetches, 1)
+}
+
+func TestPathTemplate(t *testing.T) {
+	path, params := pathTemplate("/api/orgs/5f2b8c9e1a2b3c4d5e6f7a8b/users/123e4567-e89b-12d3-a456-426614174000")
+	assert.Equal(t, path, "/api/orgs/{id}/users/{id2}")
+	assert.Equal(t, params, []string{"id", "id2"})
+
+	path, params = pathTemplate("/api/v2/search")
+	assert.Equal(t, path, "/api/v2/search")
+	assert.Equal(t, len(params), 0)
+}
+
+func TestMergeSchema(t *testing.T) {
+	assert.Equal(t, mergeSchema(&openAPISchema{Type: "integer"}, &openAPISchema{Type: "number"}).Type, "number")
+	mixed := mergeSchema(&openAPISchema{Type: "string"}, &openAPISchema{Type: "integer"})
+	assert.Equal(t, mixed.Type, "")
+	// a later sample does not give a type back to a value seen with several
+	assert.Equal(t, mergeSchema(mixed, &openAPISchema{Type: "string"}).Type, "")
+	assert.Equal(t, *mergeSchema(&openAPISchema{Nullable: true}, &openAPISchema{Type: "string"}),
+		openAPISchema{Type: "string", Nullable: true})
+}
@@ -0,0 +1,68 @@
+package debugger
+
+import (
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// overlayIndex is the file served for url paths that are directories of the overlay
+const overlayIndex = "index.html"
+
+// overlayFile returns the file of Options.OverlayDir mirroring the path of the url, if any: the file for
+// https://example.com/js/app.js is OverlayDir/js/app.js. Only urls of Options.OverlayHost, or in Options.Scope
+// when no host is set, are served from the overlay. Paths that are directories of the overlay are served their
+// index.html. OPTIONS requests, such as CORS preflights, are never served from the overlay
+func (d *Debugger) overlayFile(method string, rawUrl string) string {
+	if d.Options.OverlayDir == "" || strings.EqualFold(method, http.MethodOptions) {
+		return ""
+	}
+	u, err := url.Parse(rawUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	switch {
+	case d.Options.OverlayHost != "":
+		if !strings.EqualFold(u.Hostname(), d.Options.OverlayHost) && !strings.EqualFold(u.Host, d.Options.OverlayHost) {
+			return ""
+		}
+	case d.Options.Scope != "":
+		if !strings.Contains(rawUrl, d.Options.Scope+"/") {
+			return ""
+		}
+	}
+	// cleaning a rooted path drops any ".." segments, so that files outside of the overlay are never served
+	p := filepath.Join(d.Options.OverlayDir, filepath.FromSlash(path.Clean("/"+u.Path)))
+	fi, err := os.Stat(p)
+	if err == nil && fi.IsDir() {
+		p = filepath.Join(p, overlayIndex)
+		fi, err = os.Stat(p)
+	}
+	if err != nil || !fi.Mode().IsRegular() {
+		return ""
+	}
+	return p
+}
+
+// serveOverlay serves the contents of the overlay file at path in place of the response to the request, with
+// a Content-Type inferred from its extension, or from its contents when the extension is not known. The
+// request is forwarded if the file cannot be read
+func (d *Debugger) serveOverlay(iid string, reason string, rawUrl string, path string) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		d.log("[-] Unable to read overlay file "+path, err)
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
+	contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	d.log("[+] Serving "+rawUrl+" from overlay file "+path, nil)
+	d.continueRequest(iid, "", rawResponse(http.StatusOK, map[string]string{"Content-Type": contentType}, string(body)), "", "")
+}
@@ -0,0 +1,73 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-overlay")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	assert.Equal(t, os.MkdirAll(filepath.Join(dir, "static", "js"), 0755), nil)
+	assert.Equal(t, ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("<h1>overlay</h1>"), 0644), nil)
+	assert.Equal(t, ioutil.WriteFile(filepath.Join(dir, "static", "js", "app.js"), []byte("var overlay;"), 0644), nil)
+
+	net := &mockNetwork{bodies: map[string]string{"3": "var backend;"}}
+	d := Debugger{net: net, Options: Options{OverlayDir: dir}}
+	d.handleInterception(scriptResponse(t, "1", "top", "https://example.com/static/js/app.js?v=2"))
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"2","resourceType":"Document",
+		"request":{"url":"https://example.com/","method":"GET"},"responseStatusCode":200}`))
+	d.handleInterception(scriptResponse(t, "3", "top", "https://example.com/static/js/vendor.js"))
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 3)
+	script := decodeRaw(t, calls[0].RawResponse)
+	assert.Equal(t, strings.HasPrefix(script, "HTTP/1.1 200 OK\r\nContent-Type: "), true)
+	assert.Equal(t, strings.Contains(script, "javascript"), true)
+	assert.Equal(t, strings.HasSuffix(script, "\r\n\r\nvar overlay;"), true)
+	index := decodeRaw(t, calls[1].RawResponse)
+	assert.Equal(t, strings.Contains(index, "Content-Type: text/html"), true)
+	assert.Equal(t, strings.HasSuffix(index, "\r\n\r\n<h1>overlay</h1>"), true)
+	assert.Equal(t, calls[2].InterceptionId, "3")
+	assert.Equal(t, calls[2].RawResponse, "")
+}
+
+func TestOverlayFileStaysInOverlay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-overlay")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	assert.Equal(t, os.MkdirAll(filepath.Join(dir, "site"), 0755), nil)
+	assert.Equal(t, ioutil.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0644), nil)
+
+	d := Debugger{Options: Options{OverlayDir: filepath.Join(dir, "site")}}
+	assert.Equal(t, d.overlayFile("GET", "https://example.com/../secret.txt"), "")
+	assert.Equal(t, d.overlayFile("GET", "https://example.com/%2e%2e/secret.txt"), "")
+	// a folder without an index is not served
+	assert.Equal(t, d.overlayFile("GET", "https://example.com/"), "")
+}
+
+func TestOverlayHostAndScope(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-overlay")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	assert.Equal(t, ioutil.WriteFile(filepath.Join(dir, "app.js"), []byte("var overlay;"), 0644), nil)
+	file := filepath.Join(dir, "app.js")
+
+	d := Debugger{Options: Options{OverlayDir: dir, Scope: "example.com"}}
+	assert.Equal(t, d.overlayFile("GET", "https://example.com/app.js"), file)
+	assert.Equal(t, d.overlayFile("GET", "https://cdn.other.com/app.js"), "")
+	// preflights reach the server, or the preflight handler
+	assert.Equal(t, d.overlayFile("OPTIONS", "https://example.com/app.js"), "")
+
+	d.Options.OverlayHost = "static.example.com"
+	assert.Equal(t, d.overlayFile("GET", "https://example.com/app.js"), "")
+	assert.Equal(t, d.overlayFile("GET", "https://static.example.com:8443/app.js"), file)
+
+	err = Options{OverlayHost: "static.example.com"}.Validate()
+	assert.Equal(t, err.Error(), "invalid options: overlayHost requires overlayDir")
+}
@@ -0,0 +1,138 @@
+package debugger
+
+import (
+	"fmt"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd/gcdapi"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// pinnedResponses holds the responses served to the browser for the urls matching a pinned url pattern
+type pinnedResponses struct {
+	pattern   string
+	re        *regexp.Regexp
+	snapshots map[string]string // Raw responses, base64 encoded, by method and url
+}
+
+// pinKey returns the key the copy of the response to a request is kept under, so that a POST to a url is not
+// answered with the copy of a GET
+func pinKey(method string, url string) string {
+	return strings.ToUpper(method) + " " + url
+}
+
+// PinResponse freezes the responses to the urls matching urlPattern, which uses the same wildcards as Chrome
+// interception patterns ('*' and '?'), whatever their resource type: the responses to matching urls are
+// intercepted even when nothing else would intercept them. The first response to each method and url is served
+// as usual and kept,
+// as it was sent to the browser, and later requests with the same method and url are answered with that copy,
+// whatever the server now answers. Responses are intercepted once the server answered unless
+// Options.InterceptRequests is set, so pinned requests still reach the server and only its answer is replaced.
+// OPTIONS requests, such as CORS preflights, are never pinned. Pinning a pattern again keeps its copies
+func (d *Debugger) PinResponse(urlPattern string) {
+	d.pinsLock.Lock()
+	for _, p := range d.pins {
+		if p.pattern == urlPattern {
+			d.pinsLock.Unlock()
+			return
+		}
+	}
+	d.pins = append(d.pins, &pinnedResponses{
+		pattern:   urlPattern,
+		re:        wildcardRegexp(urlPattern),
+		snapshots: make(map[string]string),
+	})
+	d.pinsLock.Unlock()
+	if err := d.updateInterception(); err != nil {
+		d.log("[-] Unable to intercept responses to "+urlPattern, err)
+	}
+}
+
+// UnpinResponse stops freezing the responses to the urls matching urlPattern, as given to PinResponse, and
+// discards the copies kept for them
+func (d *Debugger) UnpinResponse(urlPattern string) {
+	d.pinsLock.Lock()
+	for i, p := range d.pins {
+		if p.pattern == urlPattern {
+			d.pins = append(d.pins[:i], d.pins[i+1:]...)
+			d.pinsLock.Unlock()
+			if err := d.updateInterception(); err != nil {
+				d.log("[-] Unable to stop intercepting responses to "+urlPattern, err)
+			}
+			return
+		}
+	}
+	d.pinsLock.Unlock()
+}
+
+// pinPatterns returns a pattern intercepting the responses to every pinned url pattern
+func (d *Debugger) pinPatterns() []*gcdapi.NetworkRequestPattern {
+	d.pinsLock.Lock()
+	defer d.pinsLock.Unlock()
+	var patterns []*gcdapi.NetworkRequestPattern
+	for _, p := range d.pins {
+		patterns = append(patterns, &gcdapi.NetworkRequestPattern{
+			UrlPattern:        p.pattern,
+			InterceptionStage: "HeadersReceived",
+		})
+	}
+	return patterns
+}
+
+// pinnedResponse returns the copy of the response kept for the method and url, empty when the url is not
+// pinned or its first response was not served yet
+func (d *Debugger) pinnedResponse(method string, url string) string {
+	if strings.EqualFold(method, http.MethodOptions) {
+		return ""
+	}
+	d.pinsLock.Lock()
+	defer d.pinsLock.Unlock()
+	for _, p := range d.pins {
+		if raw, ok := p.snapshots[pinKey(method, url)]; ok && p.re.MatchString(url) {
+			return raw
+		}
+	}
+	return ""
+}
+
+// pinSnapshot keeps raw, the response sent to the browser, when the url of data is pinned and has no copy for
+// its method yet. An empty raw stands for the response of the server forwarded as is
+func (d *Debugger) pinSnapshot(data modules.WebData, raw string) {
+	if strings.EqualFold(data.Method, http.MethodOptions) {
+		return
+	}
+	key := pinKey(data.Method, data.Url)
+	d.pinsLock.Lock()
+	defer d.pinsLock.Unlock()
+	for _, p := range d.pins {
+		if !p.re.MatchString(data.Url) {
+			continue
+		}
+		if _, ok := p.snapshots[key]; ok {
+			return
+		}
+		if raw == "" {
+			raw = rawResponse(data.Status, pinnedHeaders(data.Headers), data.Body)
+		}
+		p.snapshots[key] = raw
+		return
+	}
+}
+
+// pinnedHeaders returns the headers of a response forwarded as is, without those describing how the body was
+// sent, as Chrome hands bodies over decoded and the copy is sent whole
+func pinnedHeaders(headers map[string]interface{}) map[string]string {
+	kept := make(map[string]string, len(headers))
+	for k, v := range headers {
+		switch strings.ToLower(k) {
+		case "content-length", "content-encoding", "transfer-encoding":
+			continue
+		}
+		if strings.HasPrefix(k, ":") {
+			continue
+		}
+		kept[k] = fmt.Sprint(v)
+	}
+	return kept
+}
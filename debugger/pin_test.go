@@ -0,0 +1,111 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestPinResponse(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"version":1}`, "2": `{"version":2}`, "3": `{"version":3}`}}
+	d := Debugger{net: net}
+	d.PinResponse("*/api/*")
+
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	// the backend now answers differently
+	d.handleInterception(xhrResponse(t, "2", "r1"))
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 2)
+	assert.Equal(t, calls[0].RawResponse, "")
+	assert.Equal(t, decodeRaw(t, calls[1].RawResponse), "HTTP/1.1 200 OK\r\n"+
+		"Content-Type: application/json\r\nContent-Length: 13\r\n\r\n"+`{"version":1}`)
+
+	d.UnpinResponse("*/api/*")
+	d.handleInterception(xhrResponse(t, "3", "r1"))
+	calls = net.calls()
+	assert.Equal(t, calls[2].InterceptionId, "3")
+	assert.Equal(t, calls[2].RawResponse, "")
+}
+
+func TestPinResponseKeepsModifiedBody(t *testing.T) {
+	var processed []string
+	net := &mockNetwork{bodies: map[string]string{"1": "var a = 1;", "2": "var a = 2;"}}
+	d := Debugger{net: net, Modules: modules.Modules{Processors: []modules.ProcessorModule{
+		countingProcessor("marker", &processed),
+	}}}
+	d.PinResponse("https://example.com/app.js")
+
+	d.handleInterception(scriptResponse(t, "1", "top", "https://example.com/app.js"))
+	d.handleInterception(scriptResponse(t, "2", "top", "https://example.com/app.js"))
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 2)
+	assert.Equal(t, calls[1].RawResponse, calls[0].RawResponse)
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, calls[1].RawResponse), "var a = 1;/*marker*/"), true)
+	// the pinned copy is served without running processors again
+	assert.Equal(t, processed, []string{"https://example.com/app.js"})
+}
+
+func TestPinResponseByMethod(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"version":1}`, "2": `{"saved":true}`}}
+	d := Debugger{net: net}
+	d.PinResponse("*/api/*")
+
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"2","requestId":"r2","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/r1","method":"POST"},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"application/json"}}`))
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"3","requestId":"r3","resourceType":"Other",
+		"request":{"url":"https://example.com/api/r1","method":"OPTIONS",
+			"headers":{"Origin":"https://app.example.com","Access-Control-Request-Method":"POST"}},
+		"responseStatusCode":204}`))
+
+	// the POST and the preflight are not answered with the copy of the GET
+	calls := net.calls()
+	assert.Equal(t, len(calls), 3)
+	assert.Equal(t, calls[1].RawResponse, "")
+	assert.Equal(t, calls[2].RawResponse, "")
+	assert.Equal(t, d.pinnedResponse("POST", "https://example.com/api/r1") != "", true)
+	assert.Equal(t, d.pinnedResponse("OPTIONS", "https://example.com/api/r1"), "")
+}
+
+func TestPinResponsePatterns(t *testing.T) {
+	net := &mockNetwork{}
+	d := Debugger{net: net}
+	d.PinResponse("*/api/feed")
+	assert.Equal(t, len(net.patterns), 0)
+
+	// pinned urls are intercepted whatever their resource type, fetches included
+	d.patterns = InterceptionPatterns(Options{})
+	d.PinResponse("*/logo.png")
+	n := len(d.patterns)
+	assert.Equal(t, len(net.patterns), n+2)
+	assert.Equal(t, *net.patterns[n], gcdapi.NetworkRequestPattern{UrlPattern: "*/api/feed", InterceptionStage: "HeadersReceived"})
+	assert.Equal(t, *net.patterns[n+1], gcdapi.NetworkRequestPattern{UrlPattern: "*/logo.png", InterceptionStage: "HeadersReceived"})
+
+	d.UnpinResponse("*/api/feed")
+	assert.Equal(t, len(net.patterns), n+1)
+}
+
+func TestPinBinaryResponse(t *testing.T) {
+	first, second := encodePNG(t, color.White), encodePNG(t, color.Black)
+	net := &mockNetwork{bodies: map[string]string{"1": string(first), "2": string(second)}, encoded: true}
+	d := Debugger{net: net}
+	d.PinResponse("*/logo.png")
+
+	for _, iid := range []string{"1", "2"} {
+		d.handleInterception(interceptedEvent(t, `{"interceptionId":"`+iid+`","resourceType":"Image",
+			"request":{"url":"http://example.com/logo.png","method":"GET"},"responseStatusCode":200,
+			"responseHeaders":{"Content-Type":"image/png"}}`))
+	}
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 2)
+	assert.Equal(t, calls[0].RawResponse, "")
+	sent := strings.SplitN(decodeRaw(t, calls[1].RawResponse), "\r\n\r\n", 2)
+	assert.Equal(t, []byte(sent[1]), first)
+}
@@ -0,0 +1,82 @@
+package debugger
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// PostBodyRule rewrites the post data of requests whose url matches Url
+type PostBodyRule struct {
+	Url     string // Url pattern using Chrome interception wildcards, all requests when empty
+	Match   string // Regular expression matching the text to replace
+	Replace string // Replacement text, $1 and ${name} expand to the groups of Match
+}
+
+// compiledPostBodyRule is a post body rule whose patterns were compiled
+type compiledPostBodyRule struct {
+	url     *regexp.Regexp // Nil when the rule applies to all requests
+	match   *regexp.Regexp
+	replace string
+}
+
+// compiledPostBodyRules compiles the post body rules the first time they are needed, so that their patterns
+// are not compiled again for every request. Options.Validate rejects invalid patterns up front
+func (d *Debugger) compiledPostBodyRules() []compiledPostBodyRule {
+	d.postBodyRulesOnce.Do(func() {
+		for _, r := range d.Options.PostBodyRules {
+			rule := compiledPostBodyRule{match: regexp.MustCompile(r.Match), replace: r.Replace}
+			if r.Url != "" {
+				rule.url = wildcardRegexp(r.Url)
+			}
+			d.postBodyRules = append(d.postBodyRules, rule)
+		}
+	})
+	return d.postBodyRules
+}
+
+// rewritePostBody applies every matching post body rule to the body of a request. Url encoded forms are
+// rewritten field by field, with each rule matched against the decoded name=value pair, so that rules can be
+// written against the values the server sees. Other bodies, JSON included, are rewritten as they are.
+// It returns the new body, which is body itself when nothing changed
+func (d *Debugger) rewritePostBody(rawUrl string, contentType string, body string) string {
+	for _, r := range d.compiledPostBodyRules() {
+		if r.url != nil && !r.url.MatchString(rawUrl) {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(contentType), "application/x-www-form-urlencoded") {
+			body = rewriteForm(body, r.match, r.replace)
+		} else {
+			body = r.match.ReplaceAllString(body, r.replace)
+		}
+	}
+	return body
+}
+
+// rewriteForm applies a replacement to each decoded name=value pair of an url encoded form. Pairs left
+// unchanged keep their original encoding
+func rewriteForm(body string, p *regexp.Regexp, replace string) string {
+	pairs := strings.Split(body, "&")
+	for i, pair := range pairs {
+		decoded := pair
+		if kv := strings.SplitN(pair, "=", 2); len(kv) == 2 {
+			name, err1 := url.QueryUnescape(kv[0])
+			value, err2 := url.QueryUnescape(kv[1])
+			if err1 == nil && err2 == nil {
+				decoded = name + "=" + value
+			}
+		} else if name, err := url.QueryUnescape(pair); err == nil {
+			decoded = name
+		}
+		rewritten := p.ReplaceAllString(decoded, replace)
+		if rewritten == decoded {
+			continue
+		}
+		if kv := strings.SplitN(rewritten, "=", 2); len(kv) == 2 {
+			pairs[i] = url.QueryEscape(kv[0]) + "=" + url.QueryEscape(kv[1])
+		} else {
+			pairs[i] = url.QueryEscape(rewritten)
+		}
+	}
+	return strings.Join(pairs, "&")
+}
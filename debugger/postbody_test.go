@@ -0,0 +1,78 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestPostBodyRules(t *testing.T) {
+	net := &mockNetwork{}
+	d := Debugger{
+		net: net,
+		Options: Options{PostBodyRules: []PostBodyRule{
+			{Url: "*/api/profile", Match: `"role":"user"`, Replace: `"role":"admin"`},
+			{Url: "*/login", Match: `^role=user$`, Replace: "role=admin"},
+		}},
+	}
+
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/profile","method":"POST","postData":"{\"name\":\"a\",\"role\":\"user\"}",
+			"headers":{"Content-Type":"application/json"}}}`))
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"2","resourceType":"Document",
+		"request":{"url":"https://example.com/login","method":"POST","postData":"email=a%40b.c&role=user&next=%2Fhome",
+			"headers":{"Content-Type":"application/x-www-form-urlencoded"}}}`))
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"3","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/other","method":"POST","postData":"{\"role\":\"user\"}",
+			"headers":{"Content-Type":"application/json"}}}`))
+	// fetch("/api/profile", {method: "POST", body: ...})
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"4","resourceType":"Fetch",
+		"request":{"url":"https://example.com/api/profile","method":"POST","postData":"{\"role\":\"user\"}",
+			"headers":{"Content-Type":"application/json"}}}`))
+
+	calls := net.calls()
+	assert.Equal(t, len(calls), 4)
+	assert.Equal(t, calls[0].PostData, `{"name":"a","role":"admin"}`)
+	// untouched fields keep their encoding
+	assert.Equal(t, calls[1].PostData, "email=a%40b.c&role=admin&next=%2Fhome")
+	// requests forwarded unchanged send no post data
+	assert.Equal(t, calls[2].PostData, "")
+	assert.Equal(t, calls[3].PostData, `{"role":"admin"}`)
+}
+
+func TestInterceptionPatternsPostBodyRules(t *testing.T) {
+	found := map[string]bool{}
+	for _, p := range InterceptionPatterns(Options{PostBodyRules: []PostBodyRule{{Match: "user"}}}) {
+		if p.InterceptionStage == "Request" {
+			found[p.ResourceType] = true
+		}
+	}
+	assert.Equal(t, found, map[string]bool{"Document": true, "XHR": true, "Fetch": true})
+}
+
+func TestRewriteFormDecodesValues(t *testing.T) {
+	d := Debugger{Options: Options{PostBodyRules: []PostBodyRule{
+		{Match: `^redirect=/home$`, Replace: "redirect=https://evil.example/?a=b"},
+	}}}
+	body := d.rewritePostBody("https://example.com/login", "application/x-www-form-urlencoded; charset=UTF-8",
+		"user=bob&redirect=%2Fhome")
+	assert.Equal(t, body, "user=bob&redirect=https%3A%2F%2Fevil.example%2F%3Fa%3Db")
+}
+
+func TestValidatePostBodyRules(t *testing.T) {
+	err := Options{PostBodyRules: []PostBodyRule{{Url: "*"}, {Match: "("}}}.Validate()
+	assert.Equal(t, err.Error(), "invalid options: post body rule 1 has nothing to match; "+
+		"post body rule 2 has an invalid pattern: error parsing regexp: missing closing ): `(`")
+}
+
+func TestPostBodyRulesCompiledOnce(t *testing.T) {
+	d := Debugger{Options: Options{PostBodyRules: []PostBodyRule{
+		{Url: "*/api/*", Match: `"role":"user"`, Replace: `"role":"admin"`},
+		{Match: `debug=0`, Replace: `debug=1`},
+	}}}
+	rules := d.compiledPostBodyRules()
+	assert.Equal(t, len(rules), 2)
+	assert.Equal(t, rules[1].url == nil, true)
+	assert.Equal(t, d.compiledPostBodyRules()[0].match == rules[0].match, true)
+	assert.Equal(t, d.rewritePostBody("https://example.com/api/me", "application/json", `{"role":"user"}`), `{"role":"admin"}`)
+	assert.Equal(t, d.rewritePostBody("https://example.com/me", "application/json", `{"role":"user"}`), `{"role":"user"}`)
+}
@@ -0,0 +1,102 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// connection is what Chrome reported of the connection a response was served over
+type connection struct {
+	protocol   string // Such as "h2" or "http/1.1"
+	tlsVersion string // Such as "TLS 1.3", empty for plain http
+}
+
+// trackProtocols keeps track of the protocol and TLS version of every response, by request id and by origin.
+// Chrome only reports them once a response is handed to the page, after it was intercepted, so the
+// connection of a response is usually that of earlier responses from the same origin. It is empty for the
+// first responses from an origin
+func (d *Debugger) trackProtocols() {
+	d.Target.Subscribe("Network.responseReceived", func(target *gcd.ChromeTarget, v []byte) {
+		msg := &gcdapi.NetworkResponseReceivedEvent{}
+		err := json.Unmarshal(v, msg)
+		if err != nil {
+			log.Println("[-] Unable to read response event", err)
+			return
+		}
+		d.handleResponseReceived(msg)
+	})
+}
+
+func (d *Debugger) handleResponseReceived(msg *gcdapi.NetworkResponseReceivedEvent) {
+	res := msg.Params.Response
+	if msg.Params.RequestId == "" || res == nil || res.Protocol == "" {
+		return
+	}
+	c := connection{protocol: res.Protocol}
+	if res.SecurityDetails != nil {
+		c.tlsVersion = res.SecurityDetails.Protocol
+	}
+
+	d.connectionsLock.Lock()
+	defer d.connectionsLock.Unlock()
+	if d.connections == nil {
+		d.connections = make(map[string]connection)
+		d.originConnections = make(map[string]connection)
+	}
+	if _, ok := d.connections[msg.Params.RequestId]; !ok {
+		d.connectionOrder = append(d.connectionOrder, msg.Params.RequestId)
+	}
+	d.connections[msg.Params.RequestId] = c
+	for len(d.connectionOrder) > maxInitiators {
+		delete(d.connections, d.connectionOrder[0])
+		d.connectionOrder = d.connectionOrder[1:]
+	}
+	if origin := originOf(res.Url); origin != "" {
+		d.originConnections[origin] = c
+	}
+}
+
+// connectionFor returns the connection of the response to a request, or the last one seen for its origin
+// when Chrome has not reported it yet
+func (d *Debugger) connectionFor(requestId string, rawUrl string) connection {
+	d.connectionsLock.RLock()
+	defer d.connectionsLock.RUnlock()
+	if c, ok := d.connections[requestId]; ok {
+		return c
+	}
+	return d.originConnections[originOf(rawUrl)]
+}
+
+// unknownProtocol is the protocol filter entry matching requests whose protocol is not known yet
+const unknownProtocol = "unknown"
+
+// inProtocolScope reports whether requests over the given protocol should be processed according to
+// Options.ProtocolFilter. Requests whose protocol is not known yet are held back, unless the filter lists
+// "unknown"
+func (d *Debugger) inProtocolScope(protocol string) bool {
+	if len(d.Options.ProtocolFilter) == 0 {
+		return true
+	}
+	if protocol == "" {
+		protocol = unknownProtocol
+	}
+	for _, p := range d.Options.ProtocolFilter {
+		if strings.EqualFold(p, protocol) {
+			return true
+		}
+	}
+	return false
+}
+
+// originOf returns the scheme and host of a url, empty when it has none
+func originOf(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,71 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"testing"
+)
+
+func responseReceived(t *testing.T, params string) *gcdapi.NetworkResponseReceivedEvent {
+	msg := &gcdapi.NetworkResponseReceivedEvent{}
+	if err := json.Unmarshal([]byte(`{"method":"Network.responseReceived","Params":`+params+`}`), msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestResponseProtocol(t *testing.T) {
+	var seen []modules.WebData
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`, "2": `{"id":2}`}}
+	d := Debugger{
+		net:     net,
+		Options: Options{ProtocolFilter: []string{"HTTP/1.1"}},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{{
+			Registry: modules.Registry{Name: "protocols", DocTypes: []string{"XHR"}},
+			Process: func(webData modules.WebData) (string, error) {
+				seen = append(seen, webData)
+				return webData.Body, nil
+			},
+		}}},
+	}
+
+	d.handleResponseReceived(responseReceived(t, `{"requestId":"r1","type":"XHR","response":{
+		"url":"https://example.com/api/r1","status":200,"protocol":"http/1.1",
+		"securityDetails":{"protocol":"TLS 1.2","cipher":"AES_128_GCM"}}}`))
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+
+	assert.Equal(t, len(seen), 1)
+	assert.Equal(t, seen[0].Protocol, "http/1.1")
+	assert.Equal(t, seen[0].TLSVersion, "TLS 1.2")
+	log := d.RequestLog()
+	assert.Equal(t, log[0].Protocol, "http/1.1")
+	assert.Equal(t, log[0].TLSVersion, "TLS 1.2")
+
+	// the origin moved to h2, which is filtered out
+	d.handleResponseReceived(responseReceived(t, `{"requestId":"r0","type":"Document","response":{
+		"url":"https://example.com/","status":200,"protocol":"h2","securityDetails":{"protocol":"TLS 1.3"}}}`))
+	d.handleInterception(xhrResponse(t, "2", "r2"))
+
+	assert.Equal(t, len(seen), 1)
+	assert.Equal(t, d.connectionFor("r2", "https://example.com/api/r2"), connection{protocol: "h2", tlsVersion: "TLS 1.3"})
+	calls := net.calls()
+	assert.Equal(t, len(calls), 2)
+	assert.Equal(t, calls[1].InterceptionId, "2")
+	assert.Equal(t, calls[1].RawResponse, "")
+}
+
+func TestUnknownProtocol(t *testing.T) {
+	d := Debugger{Options: Options{ProtocolFilter: []string{"h2"}}}
+	assert.Equal(t, d.connectionFor("r1", "https://example.com/"), connection{})
+	assert.Equal(t, d.inProtocolScope(""), false)
+	assert.Equal(t, d.inProtocolScope("http/1.1"), false)
+
+	d.Options.ProtocolFilter = append(d.Options.ProtocolFilter, "unknown")
+	assert.Equal(t, d.inProtocolScope(""), true)
+	assert.Equal(t, d.inProtocolScope("http/1.1"), false)
+
+	d.Options.ProtocolFilter = nil
+	assert.Equal(t, d.inProtocolScope(""), true)
+}
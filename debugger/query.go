@@ -0,0 +1,80 @@
+package debugger
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// QueryOverride adds, overrides or removes query string parameters of requests whose url matches Url
+type QueryOverride struct {
+	Url    string            // Url pattern using Chrome interception wildcards
+	Set    map[string]string // Parameters to add, or to override when already present
+	Remove []string          // Parameters to remove
+}
+
+// overrideQuery applies every matching query override to rawUrl. Parameters that are not mentioned
+// by an override are kept as they were, in their original order and encoding.
+// It returns the new url, which is rawUrl itself when nothing changed
+func (d *Debugger) overrideQuery(rawUrl string) string {
+	result := rawUrl
+	for _, o := range d.Options.QueryOverrides {
+		if !wildcardRegexp(o.Url).MatchString(rawUrl) {
+			continue
+		}
+		u, err := url.Parse(result)
+		if err != nil {
+			d.log("[-] Unable to parse url "+result, err)
+			return rawUrl
+		}
+		u.RawQuery = applyQueryOverride(u.RawQuery, o)
+		result = u.String()
+	}
+	return result
+}
+
+func applyQueryOverride(rawQuery string, o QueryOverride) string {
+	remove := make(map[string]bool)
+	for _, name := range o.Remove {
+		remove[name] = true
+	}
+	set := make(map[string]bool)
+
+	var params []string
+	if rawQuery != "" {
+		for _, p := range strings.Split(rawQuery, "&") {
+			name := p
+			if i := strings.Index(p, "="); i != -1 {
+				name = p[:i]
+			}
+			if unescaped, err := url.QueryUnescape(name); err == nil {
+				name = unescaped
+			}
+			if remove[name] {
+				continue
+			}
+			if value, ok := o.Set[name]; ok {
+				if set[name] {
+					// the parameter was repeated, only the overridden value is kept
+					continue
+				}
+				set[name] = true
+				p = url.QueryEscape(name) + "=" + url.QueryEscape(value)
+			}
+			params = append(params, p)
+		}
+	}
+
+	// new parameters are appended in a stable order
+	var added []string
+	for name := range o.Set {
+		if !set[name] && !remove[name] {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		params = append(params, url.QueryEscape(name)+"="+url.QueryEscape(o.Set[name]))
+	}
+	return strings.Join(params, "&")
+}
@@ -0,0 +1,67 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordRawResponses(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-raw")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fixtures.json")
+
+	recorder, err := modules.NewFixtureRecorder(path)
+	assert.Equal(t, err, nil)
+	inspected := make(chan modules.WebData, 1)
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`}, encoded: true}
+	d := Debugger{
+		net:     net,
+		store:   recorder,
+		Options: Options{RecordRawResponses: true},
+		Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+			Registry: modules.Registry{Name: "spy"},
+			Inspect: func(webData modules.WebData) error {
+				inspected <- webData
+				return nil
+			},
+		}}},
+	}
+
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	webData := <-inspected
+	recorder.Close()
+	assert.Equal(t, webData.Body, `{"id":1}`)
+
+	fixtures, err := modules.LoadFixtures(path)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(fixtures), 1)
+	raw := fixtures[0].Raw
+	assert.Equal(t, raw.Status, 200)
+	assert.Equal(t, raw.Headers, map[string]interface{}{"Content-Type": "application/json"})
+	assert.Equal(t, raw.Base64, true)
+	assert.Equal(t, raw.Body, "eyJpZCI6MX0=")
+	body, err := raw.Bytes()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(body), `{"id":1}`)
+}
+
+func TestRawResponsesOffByDefault(t *testing.T) {
+	inspected := make(chan modules.WebData, 1)
+	d := Debugger{
+		net: &mockNetwork{bodies: map[string]string{"1": `{"id":1}`}},
+		Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+			Registry: modules.Registry{Name: "spy"},
+			Inspect: func(webData modules.WebData) error {
+				inspected <- webData
+				return nil
+			},
+		}}},
+	}
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	assert.Equal(t, (<-inspected).Raw == nil, true)
+}
@@ -0,0 +1,58 @@
+package debugger
+
+// defaultRecentRequests is the number of requests kept for RecentRequests when Options.RecentRequests is 0
+const defaultRecentRequests = 100
+
+// recentRequests is a fixed size ring buffer of the last intercepted requests, overwriting the oldest one
+// once full. It backs both RecentRequests and RequestLog, and is guarded by Debugger.requestsLock
+type recentRequests struct {
+	records []RequestRecord
+	next    int  // Index the next record is written to
+	full    bool // Whether every slot was written to at least once
+}
+
+// add stores r in place of the oldest record once the buffer holds size records. The buffer grows as records
+// are added, so that a large size costs nothing until it is reached
+func (b *recentRequests) add(r RequestRecord, size int) {
+	if !b.full && len(b.records) < size {
+		b.records = append(b.records, r)
+		b.next = len(b.records) % size
+		b.full = b.next == 0
+		return
+	}
+	b.records[b.next] = r
+	b.next = (b.next + 1) % len(b.records)
+}
+
+// last returns up to n of the newest records, oldest first. n <= 0 returns all of them
+func (b *recentRequests) last(n int) []RequestRecord {
+	count := b.next
+	if b.full {
+		count = len(b.records)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+	result := make([]RequestRecord, n)
+	for i := range result {
+		result[i] = b.records[(b.next-n+i+len(b.records))%len(b.records)]
+	}
+	return result
+}
+
+// recentSize returns the number of requests kept for RecentRequests
+func (d *Debugger) recentSize() int {
+	if d.Options.RecentRequests > 0 {
+		return d.Options.RecentRequests
+	}
+	return defaultRecentRequests
+}
+
+// RecentRequests returns up to n of the most recently intercepted requests, oldest first, or all of the retained
+// ones when n <= 0. Unlike RequestLog, only the last Options.RecentRequests requests are kept, so that it can be
+// polled by a live view of a long session. It is safe to call while requests are being intercepted
+func (d *Debugger) RecentRequests(n int) []RequestRecord {
+	d.requestsLock.Lock()
+	defer d.requestsLock.Unlock()
+	return d.recent.last(n)
+}
@@ -0,0 +1,63 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func recentUrls(records []RequestRecord) []string {
+	var urls []string
+	for _, r := range records {
+		urls = append(urls, r.Url)
+	}
+	return urls
+}
+
+func TestRecentRequests(t *testing.T) {
+	d := Debugger{Options: Options{RecentRequests: 3}}
+	assert.Equal(t, len(d.RecentRequests(0)), 0)
+
+	d.logRequest(xhrResponse(t, "1", "r1"))
+	d.logRequest(xhrResponse(t, "2", "r2"))
+	assert.Equal(t, recentUrls(d.RecentRequests(0)), []string{
+		"https://example.com/api/r1",
+		"https://example.com/api/r2",
+	})
+
+	for i := 3; i <= 7; i++ {
+		id := strconv.Itoa(i)
+		d.logRequest(xhrResponse(t, id, "r"+id))
+	}
+	assert.Equal(t, recentUrls(d.RecentRequests(0)), []string{
+		"https://example.com/api/r5",
+		"https://example.com/api/r6",
+		"https://example.com/api/r7",
+	})
+	assert.Equal(t, recentUrls(d.RecentRequests(2)), []string{
+		"https://example.com/api/r6",
+		"https://example.com/api/r7",
+	})
+	assert.Equal(t, len(d.RecentRequests(10)), 3)
+	// the full log is left alone
+	assert.Equal(t, len(d.RequestLog()), 7)
+}
+
+func TestRecentRequestsConcurrent(t *testing.T) {
+	d := Debugger{}
+	msg := xhrResponse(t, "1", "r1")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				d.logRequest(msg)
+				d.RecentRequests(10)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, len(d.RecentRequests(0)), defaultRecentRequests)
+}
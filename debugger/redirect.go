@@ -0,0 +1,36 @@
+package debugger
+
+// maxRedirectChains bounds the number of redirect chains kept, like maxInitiators
+const maxRedirectChains = 5000
+
+// addRedirect records that the request was redirected away from url. Chrome reports a redirect both when the
+// redirect response is intercepted and when the next request is about to be sent, it is only recorded once
+func (d *Debugger) addRedirect(requestId string, url string) {
+	if requestId == "" || url == "" {
+		return
+	}
+	d.redirectsLock.Lock()
+	defer d.redirectsLock.Unlock()
+	if d.redirects == nil {
+		d.redirects = make(map[string][]string)
+	}
+	chain, ok := d.redirects[requestId]
+	if !ok {
+		d.redirectOrder = append(d.redirectOrder, requestId)
+	}
+	if len(chain) > 0 && chain[len(chain)-1] == url {
+		return
+	}
+	d.redirects[requestId] = append(chain, url)
+	for len(d.redirectOrder) > maxRedirectChains {
+		delete(d.redirects, d.redirectOrder[0])
+		d.redirectOrder = d.redirectOrder[1:]
+	}
+}
+
+// redirectsFor returns the urls the request was redirected from, oldest first, nil when it was not redirected
+func (d *Debugger) redirectsFor(requestId string) []string {
+	d.redirectsLock.RLock()
+	defer d.redirectsLock.RUnlock()
+	return append([]string(nil), d.redirects[requestId]...)
+}
@@ -0,0 +1,56 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+func TestCrossOriginRedirect(t *testing.T) {
+	var seen []modules.WebData
+	net := &mockNetwork{bodies: map[string]string{"1": "<html>sso</html>"}}
+	d := Debugger{
+		net: net,
+		Options: Options{ProcessorMatchers: map[string]modules.Matcher{
+			"p": modules.MatchRedirects(modules.MatcherFunc(func(webData modules.WebData) bool {
+				return strings.HasPrefix(webData.Url, "https://example.com/")
+			})),
+		}},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{{
+			Registry: modules.Registry{Name: "p"},
+			Process: func(webData modules.WebData) (string, error) {
+				seen = append(seen, webData)
+				return webData.Body + "<!-- p -->", nil
+			},
+		}}},
+	}
+
+	// both legs are reported with the same interception and request ids
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","requestId":"r1","frameId":"top-frame",
+		"resourceType":"Document","isNavigationRequest":true,
+		"request":{"url":"https://example.com/login","method":"GET"},"responseStatusCode":302,
+		"responseHeaders":{"Location":"https://sso.example.net/auth"},"redirectUrl":"https://sso.example.net/auth"}`))
+	sent := requestWillBeSent(t, `{"requestId":"r1","request":{"url":"https://sso.example.net/auth","method":"GET"},
+		"redirectResponse":{"url":"https://example.com/login","status":302}}`)
+	d.addRedirect(sent.Params.RequestId, sent.Params.RedirectResponse.Url)
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","requestId":"r1","frameId":"top-frame",
+		"resourceType":"Document","isNavigationRequest":true,
+		"request":{"url":"https://sso.example.net/auth","method":"GET"},"responseStatusCode":200,
+		"responseHeaders":{"Content-Type":"text/html"}}`))
+
+	log := d.RequestLog()
+	assert.Equal(t, len(log), 2)
+	assert.Equal(t, log[0].Url, "https://example.com/login")
+	assert.Equal(t, log[0].Redirect, "https://sso.example.net/auth")
+	assert.Equal(t, log[1].Url, "https://sso.example.net/auth")
+
+	// the redirect is followed untouched, the processor runs on the other origin as the chain started in scope
+	calls := net.calls()
+	assert.Equal(t, calls[0], continued{InterceptionId: "1"})
+	assert.Equal(t, strings.HasSuffix(decodeRaw(t, calls[1].RawResponse), "<html>sso</html><!-- p -->"), true)
+	assert.Equal(t, len(seen), 1)
+	assert.Equal(t, seen[0].Url, "https://sso.example.net/auth")
+	assert.Equal(t, seen[0].Redirects, []string{"https://example.com/login"})
+	assert.Equal(t, d.redirectsFor("r2"), []string(nil))
+}
@@ -0,0 +1,43 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"io/ioutil"
+	"sort"
+)
+
+// scriptReplacement returns the path of the local file to serve in place of the script, if any.
+// Patterns are checked in sorted order so that overlapping patterns always resolve the same way
+func (d *Debugger) scriptReplacement(webData modules.WebData) string {
+	if webData.Type != "Script" || len(d.Options.ScriptReplacements) == 0 {
+		return ""
+	}
+	patterns := make([]string, 0, len(d.Options.ScriptReplacements))
+	for p := range d.Options.ScriptReplacements {
+		patterns = append(patterns, p)
+	}
+	sort.Strings(patterns)
+	for _, p := range patterns {
+		if wildcardRegexp(p).MatchString(webData.Url) {
+			return d.Options.ScriptReplacements[p]
+		}
+	}
+	return ""
+}
+
+// serveScriptReplacement sends the contents of the local file at path as the body of the intercepted script.
+// The original script is forwarded if the file cannot be read.
+func (d *Debugger) serveScriptReplacement(iid string, reason string, webData modules.WebData, path string) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		d.log("[-] Unable to read script replacement "+path, err)
+		d.continueRequest(iid, reason, "", "", "")
+		return
+	}
+	d.log("[+] Replacing "+webData.Url+" with "+path, nil)
+
+	webData.Body = string(body)
+	go d.CallInspectors(webData)
+
+	d.continueRequest(iid, reason, d.buildResponse(webData, webData.Body), "", "")
+}
@@ -0,0 +1,68 @@
+package debugger
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"strings"
+	"unicode/utf8"
+)
+
+// lossyPostData reports whether the post data of a request lost bytes on its way from Chrome, which passes it
+// as a UTF-8 string. Bytes that are not valid UTF-8, as in most protobuf and compressed bodies, are replaced
+// with U+FFFD, and the original body cannot be recovered from the event
+func lossyPostData(body string) bool {
+	return strings.ContainsRune(body, utf8.RuneError)
+}
+
+// decodeRequestBody returns the post data of a request decompressed according to its Content-Encoding header,
+// so that modules see the same plain text bodies they get for responses, along with the encoding to compress
+// the body with again. Bodies sent without a Content-Encoding, or with one other than gzip or deflate, are
+// returned as they are with an empty encoding
+func decodeRequestBody(headers map[string]interface{}, body string) (string, string, error) {
+	encoding := strings.ToLower(strings.TrimSpace(headerValue(headers, "content-encoding")))
+	var r io.Reader
+	switch encoding {
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(strings.NewReader(body))
+		if err != nil {
+			return body, "", err
+		}
+		r = gz
+	case "deflate":
+		// deflate is meant to be zlib wrapped, but some clients send raw deflate data
+		z, err := zlib.NewReader(strings.NewReader(body))
+		if err != nil {
+			r = flate.NewReader(strings.NewReader(body))
+		} else {
+			r = z
+		}
+	default:
+		return body, "", nil
+	}
+	decoded, err := ioutil.ReadAll(r)
+	if err != nil {
+		return body, "", err
+	}
+	return string(decoded), encoding, nil
+}
+
+// encodeRequestBody compresses body with an encoding returned by decodeRequestBody. Chrome takes post data back
+// as a UTF-8 string as well, so the result can only replace the body of a request when it is valid UTF-8
+func encodeRequestBody(body string, encoding string) (string, error) {
+	if encoding != "deflate" {
+		return gzipBody(body)
+	}
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,90 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestCompressedRequestBody(t *testing.T) {
+	compressed, err := gzipBody(`{"role":"user"}`)
+	assert.Equal(t, err, nil)
+
+	inspected := make(chan modules.WebData, 1)
+	net := &mockNetwork{}
+	d := Debugger{
+		net:     net,
+		Options: Options{InterceptRequests: true},
+		Modules: modules.Modules{
+			Inspectors: []modules.InspectorModule{{
+				Registry: modules.Registry{Name: "spy"},
+				Inspect: func(webData modules.WebData) error {
+					inspected <- webData
+					return nil
+				},
+			}},
+			Processors: []modules.ProcessorModule{{
+				Registry: modules.Registry{Name: "admin", DocTypes: []string{"Request"}},
+				Process: func(webData modules.WebData) (string, error) {
+					return webData.Body + "admin", nil
+				},
+			}},
+		},
+	}
+	// Chrome passes the post data as a string, the gzip bytes that are not valid UTF-8 are lost on the way
+	request, err := json.Marshal(map[string]interface{}{
+		"url":      "https://example.com/api/profile",
+		"method":   "POST",
+		"headers":  map[string]string{"Content-Type": "application/json", "Content-Encoding": "gzip"},
+		"postData": compressed,
+	})
+	assert.Equal(t, err, nil)
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"XHR","request":`+string(request)+`}`))
+
+	webData := <-inspected
+	assert.Equal(t, lossyPostData(webData.Body), true)
+	// the body cannot be decompressed, so it is sent as it came rather than altered
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	assert.Equal(t, calls[0].PostData, "")
+}
+
+func TestUnknownRequestEncodingReadOnly(t *testing.T) {
+	net := &mockNetwork{}
+	d := Debugger{
+		net:     net,
+		Options: Options{InterceptRequests: true},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{{
+			Registry: modules.Registry{Name: "admin", DocTypes: []string{"Request"}},
+			Process: func(webData modules.WebData) (string, error) {
+				return webData.Body + "admin", nil
+			},
+		}}},
+	}
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"1","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/profile","method":"POST","postData":"compressed",
+			"headers":{"Content-Encoding":"br"}}}`))
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	assert.Equal(t, calls[0].PostData, "")
+}
+
+func TestDecodeRequestBody(t *testing.T) {
+	deflated, err := encodeRequestBody("a=1&b=2", "deflate")
+	assert.Equal(t, err, nil)
+	body, encoding, err := decodeRequestBody(map[string]interface{}{"content-encoding": "Deflate"}, deflated)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, "a=1&b=2")
+	assert.Equal(t, encoding, "deflate")
+
+	// unknown encodings and broken bodies are left as they are
+	body, encoding, err = decodeRequestBody(map[string]interface{}{"Content-Encoding": "br"}, "raw")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, "raw")
+	assert.Equal(t, encoding, "")
+	body, encoding, err = decodeRequestBody(map[string]interface{}{"Content-Encoding": "gzip"}, "not gzip")
+	assert.Equal(t, err != nil, true)
+	assert.Equal(t, body, "not gzip")
+	assert.Equal(t, encoding, "")
+}
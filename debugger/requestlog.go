@@ -0,0 +1,85 @@
+package debugger
+
+import (
+	"github.com/wirepair/gcd/gcdapi"
+	"time"
+)
+
+// RequestRecord describes a request intercepted by the debugger
+type RequestRecord struct {
+	Url        string
+	Method     string
+	Type       string    // Resource type, such as "Document" or "Script"
+	Stage      string    // "Request" when intercepted before it was sent, "Response" once headers were received
+	Navigation bool      // Whether the request is a navigation of a frame
+	Redirect   string    // Url the response redirects to, for redirects
+	Protocol   string    // Protocol of the connection, such as "h2", empty when not known yet
+	TLSVersion string    // TLS version of the connection, such as "TLS 1.3", empty for plain http or when not known yet
+	Time       time.Time // When the request was intercepted
+
+	// Headers and Body are the headers and post data of the request as intercepted, for ResendRequest
+	Headers map[string]interface{}
+	Body    string
+}
+
+// defaultRequestLogSize is the number of requests kept for RequestLog when Options.RequestLogSize is 0
+const defaultRequestLogSize = 10000
+
+// logRequest appends an intercepted request to the request log, dropping the oldest one once the log holds
+// Options.RequestLogSize requests. A navigation of the top frame clears the log first when
+// Options.ClearRequestLog is set
+func (d *Debugger) logRequest(msg *gcdapi.NetworkRequestInterceptedEvent) {
+	r := RequestRecord{
+		Url:        msg.Params.Request.Url,
+		Method:     msg.Params.Request.Method,
+		Headers:    msg.Params.Request.Headers,
+		Body:       msg.Params.Request.PostData,
+		Type:       msg.Params.ResourceType,
+		Stage:      "Response",
+		Navigation: msg.Params.IsNavigationRequest,
+		Redirect:   msg.Params.RedirectUrl,
+		Time:       time.Now(),
+	}
+	if isRequestStage(msg) {
+		r.Stage = "Request"
+	}
+	conn := d.connectionFor(msg.Params.RequestId, r.Url)
+	r.Protocol, r.TLSVersion = conn.protocol, conn.tlsVersion
+
+	d.requestsLock.Lock()
+	defer d.requestsLock.Unlock()
+	if d.Options.ClearRequestLog && d.startsNavigation(msg) {
+		d.requests = recentRequests{}
+	}
+	d.requests.add(r, d.requestLogSize())
+	d.recent.add(r, d.recentSize())
+}
+
+// startsNavigation reports whether msg is the first interception of a navigation of the top frame. Documents
+// are intercepted a second time once the response arrives when requests are intercepted as well
+func (d *Debugger) startsNavigation(msg *gcdapi.NetworkRequestInterceptedEvent) bool {
+	if !msg.Params.IsNavigationRequest || msg.Params.ResourceType != "Document" {
+		return false
+	}
+	if interceptsRequests(d.Options) && !isRequestStage(msg) {
+		return false
+	}
+	top := d.topFrame()
+	return top == nil || top.Id == msg.Params.FrameId
+}
+
+// requestLogSize returns the number of requests kept for RequestLog
+func (d *Debugger) requestLogSize() int {
+	if d.Options.RequestLogSize > 0 {
+		return d.Options.RequestLogSize
+	}
+	return defaultRequestLogSize
+}
+
+// RequestLog returns the last Options.RequestLogSize requests intercepted, in the order they were intercepted.
+// Requests intercepted both before they are sent and once the response arrives show up once for each stage
+func (d *Debugger) RequestLog() []RequestRecord {
+	d.requestsLock.Lock()
+	defer d.requestsLock.Unlock()
+	return d.requests.last(0)
+}
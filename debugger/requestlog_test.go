@@ -0,0 +1,67 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"testing"
+)
+
+func navigation(t *testing.T, iid string, url string) *gcdapi.NetworkRequestInterceptedEvent {
+	return interceptedEvent(t, `{"interceptionId":"`+iid+`","requestId":"`+iid+`","frameId":"top-frame",
+		"resourceType":"Document","isNavigationRequest":true,"request":{"url":"`+url+`","method":"GET"}}`)
+}
+
+func requestLogUrls(d *Debugger) []string {
+	var urls []string
+	for _, r := range d.RequestLog() {
+		urls = append(urls, r.Method+" "+r.Url)
+	}
+	return urls
+}
+
+func TestRequestLog(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`, "2": `{"id":2}`, "3": `{"id":3}`}}
+	d := Debugger{net: net}
+
+	d.handleInterception(xhrResponse(t, "2", "r2"))
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	d.handleInterception(xhrResponse(t, "3", "r3"))
+
+	assert.Equal(t, requestLogUrls(&d), []string{
+		"GET https://example.com/api/r2",
+		"GET https://example.com/api/r1",
+		"GET https://example.com/api/r3",
+	})
+	log := d.RequestLog()
+	assert.Equal(t, log[0].Type, "XHR")
+	assert.Equal(t, log[0].Stage, "Response")
+	assert.Equal(t, log[0].Navigation, false)
+}
+
+func TestRequestLogClearedOnNavigation(t *testing.T) {
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`}}
+	d := Debugger{net: net, Options: Options{ClearRequestLog: true, InterceptRequests: true}}
+	d.addFrame(&gcdapi.PageFrame{Id: "top-frame"})
+
+	d.handleInterception(navigation(t, "n1", "https://example.com/"))
+	d.handleInterception(xhrResponse(t, "1", "r1"))
+	assert.Equal(t, requestLogUrls(&d), []string{"GET https://example.com/", "GET https://example.com/api/r1"})
+	assert.Equal(t, d.RequestLog()[0].Stage, "Request")
+
+	// navigations of other frames leave the log alone
+	frame := interceptedEvent(t, `{"interceptionId":"n2","requestId":"n2","frameId":"ad-frame",
+		"resourceType":"Document","isNavigationRequest":true,"request":{"url":"https://ads.example.net/","method":"GET"}}`)
+	d.handleInterception(frame)
+	assert.Equal(t, len(d.RequestLog()), 3)
+
+	d.handleInterception(navigation(t, "n3", "https://example.com/next"))
+	assert.Equal(t, requestLogUrls(&d), []string{"GET https://example.com/next"})
+}
+
+func TestRequestLogSize(t *testing.T) {
+	d := Debugger{Options: Options{RequestLogSize: 2}}
+	d.logRequest(xhrResponse(t, "1", "r1"))
+	d.logRequest(xhrResponse(t, "2", "r2"))
+	d.logRequest(xhrResponse(t, "3", "r3"))
+	assert.Equal(t, requestLogUrls(&d), []string{"GET https://example.com/api/r2", "GET https://example.com/api/r3"})
+}
@@ -0,0 +1,162 @@
+package debugger
+
+import (
+	"crypto/tls"
+	"fmt"
+	"github.com/wirepair/gcd/gcdapi"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// resendTimeout is the time allowed for the server to answer a resent request
+const resendTimeout = 30 * time.Second
+
+// RequestMods are the changes made to a request before ResendRequest sends it again
+type RequestMods struct {
+	Url           string            // Url the request is sent to, that of the request when empty
+	Method        string            // Method of the request, that of the request when empty
+	Headers       map[string]string // Headers set on the request, replacing those with the same name
+	RemoveHeaders []string          // Headers of the request left out
+	Body          *string           // Body sent in place of that of the request, nil to keep it
+}
+
+// ResponseRecord is the response to a request sent by ResendRequest
+type ResponseRecord struct {
+	Status   int
+	Headers  http.Header
+	Body     string
+	Duration time.Duration // Time taken by the server to answer, until the body was read
+}
+
+// ResendRequest sends a request from the request log again, with the changes in mods, from gorp rather than
+// from the browser, and returns the response. The browser's cookies for the url are sent along, unless the
+// request already has a Cookie header, and the request goes through Options.UpstreamProxy when it is set.
+// The Accept-Encoding header of the browser is left out, so that the body of the response comes back
+// decompressed. Redirects are returned rather than followed, so that every response can be looked at
+func (d *Debugger) ResendRequest(r RequestRecord, mods RequestMods) (ResponseRecord, error) {
+	target, method, body := r.Url, r.Method, r.Body
+	if mods.Url != "" {
+		target = mods.Url
+	}
+	if mods.Method != "" {
+		method = mods.Method
+	}
+	if mods.Body != nil {
+		body = *mods.Body
+	}
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	if err != nil {
+		return ResponseRecord{}, err
+	}
+	for k, v := range r.Headers {
+		if containsFold(hopByHopHeaders, k) || containsFold(mods.RemoveHeaders, k) ||
+			strings.EqualFold(k, "content-length") || strings.EqualFold(k, "accept-encoding") || strings.HasPrefix(k, ":") {
+			continue
+		}
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	for k, v := range mods.Headers {
+		req.Header.Set(k, v)
+	}
+	if req.Header.Get("Cookie") == "" && !containsFold(mods.RemoveHeaders, "Cookie") {
+		cookies, err := d.cookies().GetAllCookies()
+		if err != nil {
+			return ResponseRecord{}, fmt.Errorf("unable to get cookies: %s", err)
+		}
+		for _, c := range cookies {
+			if cookieMatches(c, req.URL) {
+				req.AddCookie(&http.Cookie{Name: c.Name, Value: c.Value})
+			}
+		}
+	}
+
+	client, err := d.resendClient()
+	if err != nil {
+		return ResponseRecord{}, err
+	}
+	start := time.Now()
+	res, err := client.Do(req)
+	if err != nil {
+		return ResponseRecord{}, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return ResponseRecord{}, err
+	}
+	return ResponseRecord{
+		Status:   res.StatusCode,
+		Headers:  res.Header,
+		Body:     string(resBody),
+		Duration: time.Since(start),
+	}, nil
+}
+
+// resendClient returns the client requests are resent with, going through Options.UpstreamProxy when it is
+// set, and through the proxy set in the environment otherwise. The client certificate in the options is
+// presented to servers asking for one
+func (d *Debugger) resendClient() (*http.Client, error) {
+	certs, err := clientCertificates(d.Options)
+	if err != nil {
+		return nil, err
+	}
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{Certificates: certs, RootCAs: d.resendRoots},
+	}
+	if d.Options.UpstreamProxy != "" {
+		u, err := url.Parse(d.Options.UpstreamProxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(u)
+		// proxies intercepting TLS use a certificate authority of their own
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+	return &http.Client{
+		Timeout:   resendTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}, nil
+}
+
+// clientCertificates loads the client certificate and key of the options, nil when there are none
+func clientCertificates(opts Options) ([]tls.Certificate, error) {
+	if opts.ClientCert == "" && opts.ClientKey == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load client certificate %s with key %s: %s", opts.ClientCert, opts.ClientKey, err)
+	}
+	return []tls.Certificate{cert}, nil
+}
+
+// cookieMatches reports whether the browser would send a cookie with a request to u
+func cookieMatches(c *gcdapi.NetworkCookie, u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	domain := strings.ToLower(c.Domain)
+	if strings.HasPrefix(domain, ".") {
+		if host != domain[1:] && !strings.HasSuffix(host, domain) {
+			return false
+		}
+	} else if host != domain {
+		return false
+	}
+	if c.Secure && u.Scheme != "https" {
+		return false
+	}
+	p := u.Path
+	if p == "" {
+		p = "/"
+	}
+	if c.Path == "" || c.Path == "/" || p == c.Path {
+		return true
+	}
+	return strings.HasPrefix(p, c.Path) && (strings.HasSuffix(c.Path, "/") || p[len(c.Path)] == '/')
+}
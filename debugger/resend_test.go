@@ -0,0 +1,68 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestResendRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, _ := r.Cookie("session")
+		w.Header().Set("X-Role", r.Header.Get("X-Role"))
+		w.Header().Set("X-Session", session.Value)
+		w.Write([]byte(r.Method + " " + r.URL.Path + " " + r.Header.Get("Accept")))
+	}))
+	defer server.Close()
+	host, _ := url.Parse(server.URL)
+
+	d := Debugger{ck: &mockCookies{cookies: []*gcdapi.NetworkCookie{
+		{Name: "session", Value: "s3cr3t", Domain: host.Hostname(), Path: "/"},
+		{Name: "other", Value: "x", Domain: ".example.com", Path: "/"},
+	}}}
+	d.logRequest(interceptedEvent(t, `{"interceptionId":"1","resourceType":"XHR","request":{
+		"url":"`+server.URL+`/api/me","method":"GET","headers":{"Accept":"application/json","X-Role":"user"}}}`))
+	captured := d.RequestLog()[0]
+
+	res, err := d.ResendRequest(captured, RequestMods{Headers: map[string]string{"X-Role": "admin"}})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, res.Headers.Get("X-Role"), "admin")
+	assert.Equal(t, res.Headers.Get("X-Session"), "s3cr3t")
+	assert.Equal(t, res.Body, "GET /api/me application/json")
+}
+
+func TestResendRequestDecompressed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Accept-Encoding", r.Header.Get("Accept-Encoding"))
+		if r.Header.Get("Accept-Encoding") == "gzip" {
+			compressed, _ := gzipBody(`{"id":1}`)
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write([]byte(compressed))
+			return
+		}
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	d := Debugger{ck: &mockCookies{}}
+	d.logRequest(interceptedEvent(t, `{"interceptionId":"1","resourceType":"XHR","request":{
+		"url":"`+server.URL+`/api/me","method":"GET","headers":{"Accept-Encoding":"gzip, deflate, br"}}}`))
+
+	res, err := d.ResendRequest(d.RequestLog()[0], RequestMods{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res.Headers.Get("X-Accept-Encoding"), "gzip")
+	assert.Equal(t, res.Body, `{"id":1}`)
+}
+
+func TestCookieMatches(t *testing.T) {
+	u, _ := url.Parse("http://app.example.com/account/settings")
+	assert.Equal(t, cookieMatches(&gcdapi.NetworkCookie{Domain: ".example.com", Path: "/"}, u), true)
+	assert.Equal(t, cookieMatches(&gcdapi.NetworkCookie{Domain: "example.com", Path: "/"}, u), false)
+	assert.Equal(t, cookieMatches(&gcdapi.NetworkCookie{Domain: "app.example.com", Path: "/account"}, u), true)
+	assert.Equal(t, cookieMatches(&gcdapi.NetworkCookie{Domain: "app.example.com", Path: "/acc"}, u), false)
+	assert.Equal(t, cookieMatches(&gcdapi.NetworkCookie{Domain: "app.example.com", Path: "/", Secure: true}, u), false)
+}
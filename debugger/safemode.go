@@ -0,0 +1,35 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"strings"
+)
+
+// syntaxCheck returns the check the body of data must pass in safe mode, nil when there is none
+func (d *Debugger) syntaxCheck(data modules.WebData) func(string) error {
+	contentType := headerValue(data.Headers, "content-type")
+	kind := modules.ContentKind(contentType)
+	switch {
+	case kind == "application/json" || strings.HasSuffix(kind, "+json"):
+		return api.CheckJSON
+	case kind == "text/html" || (kind == "" && data.Type == "Document"):
+		return api.CheckHTML
+	case d.Options.SafeModeScripts && (kind == modules.KindJavaScript || (kind == "" && data.Type == "Script")):
+		return api.CheckJavaScript
+	}
+	return nil
+}
+
+// checkOutput reports, in safe mode, why the body a processor returned no longer parses when its input did.
+// It returns nil when the output is fine, or when safe mode is off or the input was already broken
+func (d *Debugger) checkOutput(data modules.WebData, body string) error {
+	if !d.Options.SafeMode || body == data.Body {
+		return nil
+	}
+	check := d.syntaxCheck(data)
+	if check == nil || check(data.Body) != nil {
+		return nil
+	}
+	return check(body)
+}
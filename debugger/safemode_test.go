@@ -0,0 +1,83 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+// appendingProcessor appends suffix to every body
+func appendingProcessor(name string, suffix string) modules.ProcessorModule {
+	return modules.ProcessorModule{
+		Registry: modules.Registry{Name: name},
+		Process: func(webData modules.WebData) (string, error) {
+			return webData.Body + suffix, nil
+		},
+	}
+}
+
+func TestSafeModeDiscardsInvalidJSON(t *testing.T) {
+	d := Debugger{
+		Findings: &modules.Findings{},
+		Options:  Options{SafeMode: true},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{
+			appendingProcessor("broken", ","),
+			{
+				Registry: modules.Registry{Name: "admin"},
+				Process: func(webData modules.WebData) (string, error) {
+					return strings.Replace(webData.Body, `"admin": false`, `"admin": true`, 1), nil
+				},
+			},
+		}},
+	}
+	data := modules.WebData{
+		Body:      `{"admin": false}`,
+		Type:      "XHR",
+		Url:       "https://example.com/api/me",
+		RequestId: "1",
+		Headers:   map[string]interface{}{"Content-Type": "application/json; charset=utf-8"},
+	}
+
+	body, err := d.processBody(data)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, `{"admin": true}`)
+	changes := d.Findings.Changes("1")
+	assert.Equal(t, len(changes), 1)
+	assert.Equal(t, changes[0].Processor, "admin")
+
+	// without safe mode the broken body is served
+	d.Options.SafeMode = false
+	body, _ = d.processBody(data)
+	assert.Equal(t, body, `{"admin": true},`)
+}
+
+func TestSafeModeKeepsBrokenInput(t *testing.T) {
+	d := Debugger{
+		Options: Options{SafeMode: true},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{appendingProcessor("p", "}")}},
+	}
+	// the body was broken before the processor ran, so the processor is not to blame
+	body, _ := d.processBody(modules.WebData{
+		Body:    `{"a": {"b": 1}`,
+		Headers: map[string]interface{}{"Content-Type": "application/json"},
+	})
+	assert.Equal(t, body, `{"a": {"b": 1}}`)
+}
+
+func TestSafeModeChecks(t *testing.T) {
+	d := Debugger{Options: Options{SafeMode: true}}
+	html := modules.WebData{Body: "<html><body></body></html>", Type: "Document"}
+	assert.Equal(t, d.checkOutput(html, "<html><body><script>alert(1)</body></html>") != nil, true)
+	assert.Equal(t, d.checkOutput(html, "<html><body><script>alert(1)</script></body></html>"), nil)
+
+	script := modules.WebData{Body: "var a = [1];", Type: "Script",
+		Headers: map[string]interface{}{"Content-Type": "application/javascript"}}
+	// scripts are only checked when asked for
+	assert.Equal(t, d.checkOutput(script, "var a = [1;"), nil)
+	d.Options.SafeModeScripts = true
+	assert.Equal(t, d.checkOutput(script, "var a = [1;") != nil, true)
+
+	// other bodies are never checked
+	assert.Equal(t, d.checkOutput(modules.WebData{Body: "a", Type: "Image"}, "{"), nil)
+}
@@ -0,0 +1,74 @@
+package debugger
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/wirepair/gcd/gcdapi"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// SetupScreenshots captures a screenshot of the page every time it finishes loading and saves it
+// as a PNG file in Options.ScreenshotDir, named after the page url and the time of the capture
+func (d *Debugger) SetupScreenshots() {
+	if err := os.MkdirAll(d.Options.ScreenshotDir, 0755); err != nil {
+		d.log("[-] Unable to create screenshot directory", err)
+		return
+	}
+	d.trackFrames()
+	d.loadsLock.Lock()
+	d.screenshots = true
+	d.loadsLock.Unlock()
+	d.trackLoads()
+}
+
+// captureScreenshot captures the current page and writes it to the screenshot directory.
+// It returns the path of the file written and an error
+func (d *Debugger) captureScreenshot() (string, error) {
+	params := &gcdapi.PageCaptureScreenshotParams{Format: "png"}
+	if d.Options.FullPageScreenshots {
+		_, _, contentSize, err := d.page().GetLayoutMetrics()
+		if err != nil {
+			return "", err
+		}
+		params.Clip = &gcdapi.PageViewport{
+			Width:  contentSize.Width,
+			Height: contentSize.Height,
+			Scale:  1,
+		}
+	}
+
+	data, err := d.page().CaptureScreenshotWithParams(params)
+	if err != nil {
+		return "", err
+	}
+	png, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", err
+	}
+
+	url := "unknown"
+	if f := d.topFrame(); f != nil {
+		url = f.Url
+	}
+	path := filepath.Join(d.Options.ScreenshotDir, screenshotName(url, time.Now()))
+	if err = ioutil.WriteFile(path, png, 0644); err != nil {
+		return "", err
+	}
+	d.log("[+] Screenshot saved to "+path, nil)
+	return path, nil
+}
+
+// screenshotName returns a file name safe to use on any platform for a screenshot of url taken at time t
+func screenshotName(url string, t time.Time) string {
+	name := unsafeFileChars.ReplaceAllString(url, "_")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	return fmt.Sprintf("%s_%s.png", name, t.Format("20060102-150405.000"))
+}
@@ -0,0 +1,43 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCaptureScreenshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-screenshots")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	page := &mockPage{screenshot: []byte("\x89PNG\r\n"), contentSize: gcdapi.DOMRect{Width: 1200, Height: 4000}}
+	events := &mockEvents{}
+	d := Debugger{pg: page, ev: events, Options: Options{ScreenshotDir: dir, FullPageScreenshots: true}}
+	d.SetupScreenshots()
+
+	events.fire("Page.frameNavigated", `{"frame":{"id":"top","url":"https://example.com/account?id=1"}}`)
+	assert.Equal(t, len(page.captures), 0)
+	events.fire("Page.loadEventFired", `{"timestamp":1}`)
+
+	files, err := ioutil.ReadDir(dir)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(files), 1)
+	assert.Equal(t, strings.HasPrefix(files[0].Name(), "https_example.com_account_id_1_"), true)
+	written, _ := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.Equal(t, written, []byte("\x89PNG\r\n"))
+
+	assert.Equal(t, len(page.captures), 1)
+	assert.Equal(t, page.captures[0].Format, "png")
+	assert.Equal(t, *page.captures[0].Clip, gcdapi.PageViewport{Width: 1200, Height: 4000, Scale: 1})
+}
+
+func TestScreenshotName(t *testing.T) {
+	at := time.Date(2019, 10, 2, 15, 4, 5, 0, time.UTC)
+	assert.Equal(t, screenshotName("http://example.com/", at), "http_example.com__20191002-150405.000.png")
+}
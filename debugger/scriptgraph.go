@@ -0,0 +1,105 @@
+package debugger
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Graph holds the scripts parsed by the page and which of them loaded which
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a script of a Graph
+type GraphNode struct {
+	Id  string `json:"id"`  // Url of the script, or "script:" followed by its script id for scripts without a url
+	Url string `json:"url"` // Empty for inline and evaluated scripts
+}
+
+// GraphEdge tells that the script From caused the script To to be parsed
+type GraphEdge struct {
+	From string `json:"from"` // Id of the loading script
+	To   string `json:"to"`   // Id of the loaded script
+}
+
+// ScriptGraph returns the graph of the scripts parsed so far, with an edge from every script to each script its
+// code loaded, as told by the stack traces Chrome reports when scripts are parsed. Scripts are identified by
+// their url, so that a script parsed several times is a single node. Scripts can load each other, so the graph
+// may have cycles; a script loading itself is left out. Nodes and edges are in the order they were found
+func (d *Debugger) ScriptGraph() Graph {
+	scripts := d.Scripts()
+	urls := make(map[string]string, len(scripts))
+	for _, s := range scripts {
+		urls[s.ScriptId] = s.Url
+	}
+	nodeId := func(scriptId string, url string) (string, string) {
+		if url == "" {
+			url = urls[scriptId]
+		}
+		if url == "" {
+			return "script:" + scriptId, ""
+		}
+		return url, url
+	}
+
+	var g Graph
+	nodes := make(map[string]bool)
+	edges := make(map[GraphEdge]bool)
+	addNode := func(id string, url string) {
+		if !nodes[id] {
+			nodes[id] = true
+			g.Nodes = append(g.Nodes, GraphNode{Id: id, Url: url})
+		}
+	}
+	for _, s := range scripts {
+		addNode(nodeId(s.ScriptId, s.Url))
+	}
+	for _, s := range scripts {
+		if s.LoaderId == "" && s.Loader == "" {
+			continue
+		}
+		from, fromUrl := nodeId(s.LoaderId, s.Loader)
+		to, _ := nodeId(s.ScriptId, s.Url)
+		e := GraphEdge{From: from, To: to}
+		if from == to || edges[e] {
+			continue
+		}
+		addNode(from, fromUrl)
+		edges[e] = true
+		g.Edges = append(g.Edges, e)
+	}
+	return g
+}
+
+// DOT returns the graph in the DOT language of Graphviz
+func (g Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph scripts {\n")
+	for _, n := range g.Nodes {
+		b.WriteString("  " + strconv.Quote(n.Id) + ";\n")
+	}
+	for _, e := range g.Edges {
+		b.WriteString("  " + strconv.Quote(e.From) + " -> " + strconv.Quote(e.To) + ";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ExportScriptGraph writes the graph returned by ScriptGraph to path, in the DOT language when path ends with
+// .dot or .gv, and in JSON otherwise
+func (d *Debugger) ExportScriptGraph(path string) error {
+	g := d.ScriptGraph()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dot", ".gv":
+		return ioutil.WriteFile(path, []byte(g.DOT()), 0644)
+	}
+	encoded, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, encoded, 0644)
+}
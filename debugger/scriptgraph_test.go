@@ -0,0 +1,58 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"strings"
+	"testing"
+)
+
+// parsedScript builds the script a Debugger.scriptParsed event reports
+func parsedScript(t *testing.T, event string) ParsedScript {
+	msg := &gcdapi.DebuggerScriptParsedEvent{}
+	assert.Equal(t, json.Unmarshal([]byte(event), msg), nil)
+	script := ParsedScript{ScriptId: msg.Params.ScriptId, Url: msg.Params.Url}
+	script.LoaderId, script.Loader = scriptLoader(msg.Params.StackTrace, script.ScriptId)
+	return script
+}
+
+func TestScriptGraph(t *testing.T) {
+	d := Debugger{}
+	d.addScript(parsedScript(t, `{"method":"Debugger.scriptParsed","Params":{"scriptId":"1",
+		"url":"https://example.com/app.js"}}`))
+	d.addScript(parsedScript(t, `{"method":"Debugger.scriptParsed","Params":{"scriptId":"2",
+		"url":"https://cdn.example.com/chunk.js","stackTrace":{"callFrames":[
+		{"functionName":"load","scriptId":"1","url":"https://example.com/app.js"}]}}}`))
+
+	g := d.ScriptGraph()
+	assert.Equal(t, g.Nodes, []GraphNode{
+		{Id: "https://example.com/app.js", Url: "https://example.com/app.js"},
+		{Id: "https://cdn.example.com/chunk.js", Url: "https://cdn.example.com/chunk.js"},
+	})
+	assert.Equal(t, g.Edges, []GraphEdge{{From: "https://example.com/app.js", To: "https://cdn.example.com/chunk.js"}})
+	assert.Equal(t, strings.Contains(g.DOT(), `"https://example.com/app.js" -> "https://cdn.example.com/chunk.js";`), true)
+}
+
+func TestScriptGraphCycles(t *testing.T) {
+	d := Debugger{}
+	d.addScript(ParsedScript{ScriptId: "1", Url: "https://example.com/a.js", LoaderId: "2"})
+	d.addScript(ParsedScript{ScriptId: "2", Url: "https://example.com/b.js", LoaderId: "1"})
+	// parsed again from the same url, loading itself
+	d.addScript(ParsedScript{ScriptId: "3", Url: "https://example.com/a.js", LoaderId: "1"})
+	d.addScript(ParsedScript{ScriptId: "4", LoaderId: "2"})
+
+	g := d.ScriptGraph()
+	assert.Equal(t, len(g.Nodes), 3)
+	assert.Equal(t, g.Edges, []GraphEdge{
+		{From: "https://example.com/b.js", To: "https://example.com/a.js"},
+		{From: "https://example.com/a.js", To: "https://example.com/b.js"},
+		{From: "https://example.com/b.js", To: "script:4"},
+	})
+
+	encoded, err := json.Marshal(g)
+	assert.Equal(t, err, nil)
+	var decoded Graph
+	assert.Equal(t, json.Unmarshal(encoded, &decoded), nil)
+	assert.Equal(t, decoded, g)
+}
@@ -0,0 +1,121 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ParsedScript is a script parsed by the page, as reported by the Debugger domain
+type ParsedScript struct {
+	ScriptId string
+	Url      string // Empty for inline and evaluated scripts
+
+	// LoaderId and Loader are the id and url of the script whose code caused this one to be parsed, such as by
+	// calling eval or inserting a script tag, taken from the stack trace Chrome reports. Empty when unknown
+	LoaderId string
+	Loader   string
+}
+
+// trackScripts keeps track of every script parsed by the page, so that their sources can be saved with DumpScripts
+// and the scripts that loaded them found with ScriptGraph, and passes evaluated code to inspectors when
+// SetupEvalHooks was called
+func (d *Debugger) trackScripts() {
+	d.scriptsOnce.Do(func() {
+		d.events().Subscribe("Debugger.scriptParsed", func(target *gcd.ChromeTarget, v []byte) {
+			msg := &gcdapi.DebuggerScriptParsedEvent{}
+			err := json.Unmarshal(v, msg)
+			if err != nil {
+				log.Println("[-] Unable to read script parsed event", err)
+				return
+			}
+			script := ParsedScript{ScriptId: msg.Params.ScriptId, Url: msg.Params.Url}
+			script.LoaderId, script.Loader = scriptLoader(msg.Params.StackTrace, script.ScriptId)
+			d.addScript(script)
+			go d.handleEvalScript(msg)
+		})
+	})
+}
+
+// scriptLoader returns the id and url of the script at the top of a stack trace, other than the script itself,
+// looking into the asynchronous parents of the trace when needed. Both are empty when there is none
+func scriptLoader(stack *gcdapi.RuntimeStackTrace, scriptId string) (string, string) {
+	for ; stack != nil; stack = stack.Parent {
+		for _, f := range stack.CallFrames {
+			if f.ScriptId != scriptId && (f.ScriptId != "" || f.Url != "") {
+				return f.ScriptId, f.Url
+			}
+		}
+	}
+	return "", ""
+}
+
+func (d *Debugger) addScript(script ParsedScript) {
+	d.scriptsLock.Lock()
+	defer d.scriptsLock.Unlock()
+	d.scripts = append(d.scripts, script)
+}
+
+// Scripts returns the scripts parsed so far, in the order they were parsed
+func (d *Debugger) Scripts() []ParsedScript {
+	d.scriptsLock.Lock()
+	defer d.scriptsLock.Unlock()
+	return append([]ParsedScript(nil), d.scripts...)
+}
+
+// DumpScripts writes the source of every parsed script to dir, mirroring their urls: the source of
+// https://example.com/js/app.js is written to dir/example.com/js/app.js. Scripts without an http or https url
+// are written to dir/inline, named after their script id. Scripts parsed more than once from the same url are
+// written once, with their latest source.
+func (d *Debugger) DumpScripts(dir string) error {
+	for _, s := range d.Scripts() {
+		source, _, err := d.scriptSource().GetScriptSource(s.ScriptId)
+		if err != nil {
+			d.log("[-] Unable to get source of script "+s.ScriptId, err)
+			continue
+		}
+		p := filepath.Join(dir, scriptFileName(s))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(p, []byte(source), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scriptFileName returns the path, relative to the dump directory, where a script is saved
+func scriptFileName(s ParsedScript) string {
+	u, err := url.Parse(s.Url)
+	if s.Url == "" || err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return filepath.Join("inline", unsafeFileChars.ReplaceAllString(s.ScriptId, "_")+".js")
+	}
+	// cleaning a rooted path drops any ".." segments
+	p := path.Clean("/" + u.Path)
+	if strings.HasSuffix(u.Path, "/") || p == "/" {
+		p = path.Join(p, "index")
+	}
+	if u.RawQuery != "" {
+		p += "_" + unsafeFileChars.ReplaceAllString(u.RawQuery, "_")
+	}
+	if path.Ext(p) != ".js" {
+		p += ".js"
+	}
+	return filepath.Join(unsafeFileChars.ReplaceAllString(u.Host, "_"), filepath.FromSlash(p))
+}
+
+// scriptSource returns the Debugger domain used to get script sources
+func (d *Debugger) scriptSource() debuggerDomain {
+	if d.dbg == nil {
+		return d.Target.Debugger
+	}
+	return d.dbg
+}
@@ -0,0 +1,49 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// mockDebugger stands in for the Chrome Debugger domain, serving sources from a map of script ids
+type mockDebugger struct {
+	sources map[string]string
+}
+
+func (m *mockDebugger) GetScriptSource(scriptId string) (string, string, error) {
+	return m.sources[scriptId], "", nil
+}
+
+func TestDumpScripts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-scripts")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	d := Debugger{dbg: &mockDebugger{sources: map[string]string{
+		"11": "var app = 1;",
+		"12": "console.log('inline');",
+	}}}
+	d.addScript(ParsedScript{ScriptId: "11", Url: "https://example.com/static/js/app.js"})
+	d.addScript(ParsedScript{ScriptId: "12"})
+
+	assert.Equal(t, d.DumpScripts(dir), nil)
+
+	app, err := ioutil.ReadFile(filepath.Join(dir, "example.com", "static", "js", "app.js"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(app), "var app = 1;")
+	inline, err := ioutil.ReadFile(filepath.Join(dir, "inline", "12.js"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(inline), "console.log('inline');")
+}
+
+func TestScriptFileName(t *testing.T) {
+	assert.Equal(t, scriptFileName(ParsedScript{Url: "https://example.com/"}), filepath.Join("example.com", "index.js"))
+	assert.Equal(t, scriptFileName(ParsedScript{Url: "https://example.com:8443/app?v=2"}),
+		filepath.Join("example.com_8443", "app_v_2.js"))
+	assert.Equal(t, scriptFileName(ParsedScript{Url: "https://example.com/../../etc/passwd"}),
+		filepath.Join("example.com", "etc", "passwd.js"))
+	assert.Equal(t, scriptFileName(ParsedScript{ScriptId: "7", Url: "debugger://VM7"}), filepath.Join("inline", "7.js"))
+}
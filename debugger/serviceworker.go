@@ -0,0 +1,57 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"log"
+)
+
+// trackServiceWorkers keeps track of the scripts of registered service workers, so that later fetches of
+// those scripts can be recognized
+func (d *Debugger) trackServiceWorkers() {
+	if _, err := d.Target.ServiceWorker.Enable(); err != nil {
+		log.Println("[-] Unable to enable service worker events", err)
+		return
+	}
+	d.Target.Subscribe("ServiceWorker.workerVersionUpdated", func(target *gcd.ChromeTarget, v []byte) {
+		msg := &gcdapi.ServiceWorkerWorkerVersionUpdatedEvent{}
+		err := json.Unmarshal(v, msg)
+		if err != nil {
+			log.Println("[-] Unable to read service worker event", err)
+			return
+		}
+		for _, version := range msg.Params.Versions {
+			d.addWorker(version.ScriptURL)
+		}
+	})
+}
+
+func (d *Debugger) addWorker(scriptUrl string) {
+	if scriptUrl == "" {
+		return
+	}
+	d.workersLock.Lock()
+	defer d.workersLock.Unlock()
+	if d.workers == nil {
+		d.workers = make(map[string]bool)
+	}
+	d.workers[scriptUrl] = true
+}
+
+// isWorkerScript reports whether the intercepted request fetches a service worker script. Chrome marks
+// those fetches with a "Service-Worker: script" header, updates of known workers are matched by url. Requests
+// made by a worker are not seen by the page's interception, and whether a worker answered a request is only
+// reported once the response reached the page, so neither is recognized.
+func (d *Debugger) isWorkerScript(msg *gcdapi.NetworkRequestInterceptedEvent) bool {
+	req := msg.Params.Request
+	if req == nil {
+		return false
+	}
+	if headerValue(req.Headers, "service-worker") == "script" {
+		return true
+	}
+	d.workersLock.RLock()
+	defer d.workersLock.RUnlock()
+	return d.workers[req.Url]
+}
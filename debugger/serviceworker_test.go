@@ -0,0 +1,63 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"testing"
+)
+
+func serviceWorkerResponse(t *testing.T, iid string) *gcdapi.NetworkRequestInterceptedEvent {
+	return interceptedEvent(t, `{"interceptionId":"`+iid+`","frameId":"top-frame","resourceType":"Script",
+		"request":{"url":"http://example.com/sw.js","method":"GET","headers":{"Service-Worker":"script"}},
+		"responseStatusCode":200,"responseHeaders":{"Content-Type":"application/javascript"}}`)
+}
+
+// flagProcessor records the WorkerScript flag of every body it is given
+func flagProcessor(flags *[]bool) modules.ProcessorModule {
+	return modules.ProcessorModule{
+		Registry: modules.Registry{Name: "flags", DocTypes: []string{"Script"}},
+		Process: func(webData modules.WebData) (string, error) {
+			*flags = append(*flags, webData.WorkerScript)
+			return webData.Body, nil
+		},
+	}
+}
+
+func TestServiceWorkerForwardedByDefault(t *testing.T) {
+	var flags []bool
+	net := &mockNetwork{bodies: map[string]string{"1": "self.addEventListener('fetch', f);"}}
+	d := Debugger{
+		net:     net,
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{flagProcessor(&flags)}},
+	}
+
+	d.handleInterception(serviceWorkerResponse(t, "1"))
+
+	assert.Equal(t, len(flags), 0)
+	calls := net.calls()
+	assert.Equal(t, len(calls), 1)
+	assert.Equal(t, calls[0].RawResponse, "")
+}
+
+func TestServiceWorkerFlag(t *testing.T) {
+	var flags []bool
+	net := &mockNetwork{bodies: map[string]string{
+		"1": "self.addEventListener('fetch', f);",
+		"2": "self.addEventListener('push', f);",
+		"3": "var a;",
+	}}
+	d := Debugger{
+		net:     net,
+		Options: Options{ProcessWorkerScripts: true},
+		Modules: modules.Modules{Processors: []modules.ProcessorModule{flagProcessor(&flags)}},
+	}
+	d.addWorker("http://example.com/worker.js")
+
+	d.handleInterception(serviceWorkerResponse(t, "1"))
+	d.handleInterception(scriptResponse(t, "2", "", "http://example.com/worker.js"))
+	d.handleInterception(scriptResponse(t, "3", "top-frame", "http://example.com/app.js"))
+
+	assert.Equal(t, flags, []bool{true, true, false})
+	assert.Equal(t, len(net.calls()), 3)
+}
@@ -0,0 +1,306 @@
+package debugger
+
+import (
+	"fmt"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"os"
+)
+
+// New returns a debugger configured with opts, ready to have modules registered and to be started.
+// This is the entry point for programs embedding gorp. Invalid options are reported as a *ValidationError.
+// It returns a pointer to the debugger and an error
+func New(opts Options) (*Debugger, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	d := &Debugger{
+		Options:  opts,
+		Findings: &modules.Findings{Dedup: opts.DedupFindings},
+		Done:     make(chan bool),
+		console:  newConsole(opts.Color, os.Stderr),
+	}
+	if opts.LogFile != "" {
+		if err := d.setupFileLogger(); err != nil {
+			return nil, fmt.Errorf("unable to open log file: %s", err)
+		}
+	}
+	d.store = opts.SessionStore
+	if opts.RecordFixtures != "" {
+		s, err := modules.NewFileStore(opts.RecordFixtures)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open fixture file: %s", err)
+		}
+		d.store = s
+	}
+	// the client certificate is loaded upfront so that a broken pair is reported before the session starts
+	certs, err := clientCertificates(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.UpstreamProxy != "" {
+		b, err := newProxyBridge(opts.UpstreamProxy, certs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set up upstream proxy: %s", err)
+		}
+		d.bridge = b
+	}
+	return d, nil
+}
+
+// AddProcessor initializes, configures and registers a processor that is not loaded as a plugin.
+// It returns an error, leaving the processor out, when its Configure rejects the options it was initialized with
+func (d *Debugger) AddProcessor(p modules.Processor) error {
+	m := modules.NewProcessorModule(p)
+	if err := m.ApplyOptions(nil); err != nil {
+		return err
+	}
+	d.Modules.Processors = append(d.Modules.Processors, *m)
+	return nil
+}
+
+// AddInspector initializes, configures and registers an inspector that is not loaded as a plugin.
+// It returns an error, leaving the inspector out, when its Configure rejects the options it was initialized with
+func (d *Debugger) AddInspector(i modules.Inspector) error {
+	m := modules.NewInspectorModule(i)
+	if err := m.ApplyOptions(nil); err != nil {
+		return err
+	}
+	d.Modules.Inspectors = append(d.Modules.Inspectors, *m)
+	return nil
+}
+
+// Start restores the module states saved to Options.StateFile, launches Chrome and opens a new tab, or connects to
+// the Chrome already listening on Options.Port and attaches to Options.TargetId when set, and starts intercepting
+// requests. Done is closed once the session is stopped with Stop.
+func (d *Debugger) Start() error {
+	if err := d.Options.Validate(); err != nil {
+		return err
+	}
+	if err := d.loadStateFile(); err != nil {
+		return fmt.Errorf("unable to load module states: %s", err)
+	}
+	d.ChromeProxy = gcd.NewChromeDebugger()
+	if d.Options.TargetId != "" {
+		// the tab lives in a Chrome started by hand, which is left running when the session stops
+		if err := d.ChromeProxy.ConnectToInstance("localhost", d.Options.Port); err != nil {
+			return fmt.Errorf("unable to connect to chrome: %s", err)
+		}
+		return d.AttachToTarget(d.Options.TargetId)
+	}
+	d.ChromeProxy.AddFlags(d.Options.Flags)
+	if err := d.ChromeProxy.StartProcess(d.Options.ChromePath, d.Options.UserDir, d.Options.Port); err != nil {
+		return fmt.Errorf("unable to start chrome: %s", err)
+	}
+	if err := d.start(); err != nil {
+		d.ChromeProxy.ExitProcess()
+		return err
+	}
+	return nil
+}
+
+func (d *Debugger) start() error {
+	if err := d.StartTarget(); err != nil {
+		return err
+	}
+	return d.setupTarget()
+}
+
+// setupTarget sets up interception and the hooks enabled in the options on the debugger target
+func (d *Debugger) setupTarget() error {
+	params := &gcdapi.NetworkSetRequestInterceptionParams{
+		Patterns: InterceptionPatterns(d.Options),
+	}
+	if err := d.SetupRequestInterception(params); err != nil {
+		return err
+	}
+	d.SetupDOMDebugger()
+	d.trackFrames()
+	d.trackScripts()
+	d.trackEventStreams()
+	d.trackFailures()
+	d.trackLoads()
+	if d.Options.DialogAction != "" {
+		d.trackDialogs()
+	}
+	if d.Options.HookEval {
+		if err := d.SetupEvalHooks(); err != nil {
+			return err
+		}
+	}
+	if d.Options.HookStorage {
+		if err := d.SetupStorageHooks(); err != nil {
+			return err
+		}
+	}
+	if d.Options.CSPViolations {
+		if err := d.SetupCSPViolations(); err != nil {
+			return err
+		}
+	}
+	if d.Options.Screenshots {
+		d.SetupScreenshots()
+	}
+	if d.Options.Device != "" {
+		if err := d.EmulateDevicePreset(d.Options.Device); err != nil {
+			return err
+		}
+	}
+	if d.Options.CPUThrottle != 0 {
+		if err := d.SetCPUThrottling(d.Options.CPUThrottle); err != nil {
+			return err
+		}
+	}
+	d.limitDuration()
+	d.startWatchdog()
+	return nil
+}
+
+// Stop ends the session, printing the module summary, saving module states to Options.StateFile, the observed API
+// to Options.OpenAPIFile and scripts to Options.ScriptDumpDir if set, closing the session store and Chrome, unless
+// it was attached to with Options.TargetId, and signaling Done. It is safe to call Stop more than once
+func (d *Debugger) Stop() error {
+	var err error
+	d.stopOnce.Do(func() {
+		d.PrintSummary()
+		if d.Options.StateFile != "" {
+			if saveErr := d.SaveState(d.Options.StateFile); saveErr != nil {
+				d.log("[-] Unable to save module states", saveErr)
+			}
+		}
+		if d.Options.OpenAPIFile != "" {
+			if exportErr := d.ExportOpenAPI(d.Options.OpenAPIFile); exportErr != nil {
+				d.log("[-] Unable to export OpenAPI document", exportErr)
+			}
+		}
+		if d.Options.ScriptDumpDir != "" && d.Target != nil {
+			if dumpErr := d.DumpScripts(d.Options.ScriptDumpDir); dumpErr != nil {
+				d.log("[-] Unable to dump scripts", dumpErr)
+			}
+		}
+		if d.store != nil {
+			if closeErr := d.store.Close(); closeErr != nil {
+				d.log("[-] Unable to close session store", closeErr)
+			}
+		}
+		if d.bridge != nil {
+			d.bridge.close()
+		}
+		if d.ChromeProxy != nil && d.Options.TargetId == "" {
+			err = d.ChromeProxy.ExitProcess()
+		}
+		if d.Done != nil {
+			close(d.Done)
+		}
+	})
+	return err
+}
+
+// InterceptionPatterns returns the patterns used to intercept documents, scripts, XHR and flash files
+// in opts.Scope. An empty scope intercepts everything. Documents, XHR and fetches are also intercepted before
+// being sent when opts.InterceptRequests or post body rules are set, so that request bodies can be inspected
+// and altered. Images, media,
+// fonts and fetches are intercepted when opts.InterceptBinary is set, fetches also when opts.OpenAPIFile is set,
+// web app manifests when opts.InterceptManifests is set, and CORS preflights when opts.AnswerPreflights is set.
+func InterceptionPatterns(opts Options) []*gcdapi.NetworkRequestPattern {
+	scope := opts.Scope
+	//Default is everything!
+	docPattern := "*"
+	jsPattern := "*"
+	xhrPattern := "*"
+	if scope != "" {
+		docPattern = "*" + scope + "/*"
+		jsPattern = "*" + scope + "*.js"
+		xhrPattern = "*" + scope + "/*"
+	}
+	patterns := []*gcdapi.NetworkRequestPattern{
+		{
+			UrlPattern:        docPattern,
+			ResourceType:      "Document",
+			InterceptionStage: "HeadersReceived",
+		},
+		{
+			UrlPattern:        jsPattern,
+			ResourceType:      "Script",
+			InterceptionStage: "HeadersReceived",
+		},
+		{
+			UrlPattern:        xhrPattern,
+			ResourceType:      "XHR",
+			InterceptionStage: "HeadersReceived",
+		},
+		{
+			UrlPattern:        "*" + scope + "*.swf",
+			ResourceType:      "Other",
+			InterceptionStage: "HeadersReceived",
+		},
+	}
+	if opts.InterceptBinary {
+		for _, t := range []string{"Image", "Media", "Font", "Fetch"} {
+			patterns = append(patterns, &gcdapi.NetworkRequestPattern{
+				UrlPattern:        xhrPattern,
+				ResourceType:      t,
+				InterceptionStage: "HeadersReceived",
+			})
+		}
+	}
+	if opts.OpenAPIFile != "" && !opts.InterceptBinary {
+		// the endpoints of the exported document are observed in fetch responses as well
+		patterns = append(patterns, &gcdapi.NetworkRequestPattern{
+			UrlPattern:        xhrPattern,
+			ResourceType:      "Fetch",
+			InterceptionStage: "HeadersReceived",
+		})
+	}
+	if opts.InterceptManifests {
+		patterns = append(patterns, &gcdapi.NetworkRequestPattern{
+			UrlPattern:        xhrPattern,
+			ResourceType:      "Manifest",
+			InterceptionStage: "HeadersReceived",
+		})
+	}
+	if opts.AnswerPreflights {
+		// older versions of Chrome report preflights as "Other"
+		for _, t := range []string{"Preflight", "Other"} {
+			patterns = append(patterns, &gcdapi.NetworkRequestPattern{
+				UrlPattern:        xhrPattern,
+				ResourceType:      t,
+				InterceptionStage: "Request",
+			})
+		}
+	}
+	if interceptsRequests(opts) {
+		patterns = append(patterns,
+			&gcdapi.NetworkRequestPattern{
+				UrlPattern:        docPattern,
+				ResourceType:      "Document",
+				InterceptionStage: "Request",
+			},
+			&gcdapi.NetworkRequestPattern{
+				UrlPattern:        xhrPattern,
+				ResourceType:      "XHR",
+				InterceptionStage: "Request",
+			},
+			&gcdapi.NetworkRequestPattern{
+				UrlPattern:        xhrPattern,
+				ResourceType:      "Fetch",
+				InterceptionStage: "Request",
+			},
+		)
+	}
+	return patterns
+}
+
+// interceptsRequests reports whether requests are intercepted before being sent, which post body rules need
+// as much as InterceptRequests does
+func interceptsRequests(opts Options) bool {
+	return opts.InterceptRequests || len(opts.PostBodyRules) > 0
+}
+
+// interceptsRequestsOf reports whether requests of the given resource type are intercepted before being sent so
+// that modules can see them, as set up by InterceptionPatterns. Other requests intercepted at that stage, such as
+// those caught by the patterns for preflights, are forwarded untouched
+func interceptsRequestsOf(opts Options, resourceType string) bool {
+	return interceptsRequests(opts) && (resourceType == "Document" || resourceType == "XHR" || resourceType == "Fetch")
+}
@@ -0,0 +1,29 @@
+package debugger
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/DharmaOfCode/gorp/modules"
+	"os"
+)
+
+// ExportFixtures writes the web data recorded to the session store so far to path, as a fixture file that
+// modules.LoadFixtures reads. Only sessions recorded with Options.SessionStore or Options.RecordFixtures can be
+// exported, and path must not be the file the session is being recorded to
+func (d *Debugger) ExportFixtures(path string) error {
+	if d.store == nil {
+		return errors.New("the session is not recorded to a store")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	err = d.store.Iterate(func(webData modules.WebData) error {
+		return enc.Encode(webData)
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
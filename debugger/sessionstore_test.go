@@ -0,0 +1,62 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSessionStoreExports(t *testing.T) {
+	inspected := make(chan modules.WebData, 2)
+	net := &mockNetwork{bodies: map[string]string{"1": `{"id":1}`, "2": `{"ok":true}`}}
+	d := Debugger{
+		net:   net,
+		store: modules.NewMemoryStore(),
+		Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+			Registry: modules.Registry{Name: "spy"},
+			Inspect: func(webData modules.WebData) error {
+				inspected <- webData
+				return nil
+			},
+		}}},
+	}
+
+	d.handleInterception(xhrResponse(t, "1", "users"))
+	d.handleInterception(interceptedEvent(t, `{"interceptionId":"2","resourceType":"XHR",
+		"request":{"url":"https://example.com/api/login","method":"POST",
+		"headers":{"Content-Type":"application/json"},"postData":"{\"user\":\"alice\"}"},
+		"responseStatusCode":201,"responseHeaders":{"Content-Type":"application/json"}}`))
+	<-inspected
+	<-inspected
+
+	// the endpoints are read back from the store rather than recorded as they are seen
+	assert.Equal(t, len(d.endpoints.operations), 0)
+	doc, err := d.openAPI()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(doc.Paths), 2)
+	login := doc.Paths["/api/login"]["post"]
+	assert.Equal(t, login.Responses["201"] != nil, true)
+	assert.Equal(t, login.RequestBody.Content["application/json"].Schema.Properties["user"].Type, "string")
+
+	dir, err := ioutil.TempDir("", "gorp-store")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fixtures.json")
+	assert.Equal(t, d.ExportFixtures(path), nil)
+	fixtures, err := modules.LoadFixtures(path)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(fixtures), 2)
+	urls := map[string]int{}
+	for _, f := range fixtures {
+		urls[f.Url] = f.Status
+	}
+	assert.Equal(t, urls, map[string]int{"https://example.com/api/users": 200, "https://example.com/api/login": 201})
+}
+
+func TestExportFixturesWithoutStore(t *testing.T) {
+	d := Debugger{}
+	assert.Equal(t, d.ExportFixtures(filepath.Join(os.TempDir(), "gorp-unused.json")) != nil, true)
+}
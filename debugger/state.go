@@ -0,0 +1,104 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// stateKey identifies the state of a module in a state file, the same way the session summary does
+func stateKey(kind string, name string) string {
+	return kind + "/" + name
+}
+
+// SaveState writes what every stateful module accumulated during the session to path, so that LoadState can
+// restore it in the next session. Modules that do not implement modules.Stateful are left out
+func (d *Debugger) SaveState(path string) error {
+	states := make(map[string][]byte)
+	for _, p := range d.Modules.Processors {
+		if p.MarshalState == nil {
+			continue
+		}
+		state, err := p.MarshalState()
+		if err != nil {
+			return fmt.Errorf("unable to save state of processor %s: %s", p.Registry.Name, err)
+		}
+		states[stateKey("processor", p.Registry.Name)] = state
+	}
+	for _, i := range d.Modules.Inspectors {
+		if i.MarshalState == nil {
+			continue
+		}
+		state, err := i.MarshalState()
+		if err != nil {
+			return fmt.Errorf("unable to save state of inspector %s: %s", i.Registry.Name, err)
+		}
+		states[stateKey("inspector", i.Registry.Name)] = state
+	}
+	encoded, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, encoded, 0644)
+}
+
+// LoadState restores the states saved to path by SaveState into the registered modules. Modules without a
+// saved state keep their own, and states of modules that are not registered are ignored
+func (d *Debugger) LoadState(path string) error {
+	encoded, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	states := make(map[string][]byte)
+	if err := json.Unmarshal(encoded, &states); err != nil {
+		return fmt.Errorf("invalid state file %s: %s", path, err)
+	}
+	for _, p := range d.Modules.Processors {
+		state, ok := states[stateKey("processor", p.Registry.Name)]
+		if !ok || p.UnmarshalState == nil {
+			continue
+		}
+		if err := p.UnmarshalState(state); err != nil {
+			return fmt.Errorf("unable to restore state of processor %s: %s", p.Registry.Name, err)
+		}
+	}
+	for _, i := range d.Modules.Inspectors {
+		state, ok := states[stateKey("inspector", i.Registry.Name)]
+		if !ok || i.UnmarshalState == nil {
+			continue
+		}
+		if err := i.UnmarshalState(state); err != nil {
+			return fmt.Errorf("unable to restore state of inspector %s: %s", i.Registry.Name, err)
+		}
+	}
+	return nil
+}
+
+// ModuleState returns the state of the named processor or inspector, as SaveState would save it, so that what a
+// module accumulated, such as the routes found by RouteMapper, can be read while the session runs
+func (d *Debugger) ModuleState(name string) ([]byte, error) {
+	for _, i := range d.Modules.Inspectors {
+		if i.Registry.Name == name && i.MarshalState != nil {
+			return i.MarshalState()
+		}
+	}
+	for _, p := range d.Modules.Processors {
+		if p.Registry.Name == name && p.MarshalState != nil {
+			return p.MarshalState()
+		}
+	}
+	return nil, fmt.Errorf("no stateful module named %s", name)
+}
+
+// loadStateFile restores the module states from Options.StateFile, unless this is the first session using it
+func (d *Debugger) loadStateFile() error {
+	if d.Options.StateFile == "" {
+		return nil
+	}
+	err := d.LoadState(d.Options.StateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
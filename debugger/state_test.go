@@ -0,0 +1,103 @@
+package debugger
+
+import (
+	"encoding/json"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+)
+
+var endpointRegex = regexp.MustCompile(`"(/api/[\w/]+)"`)
+
+// endpointCollector collects the api endpoints referenced by scripts across sessions
+type endpointCollector struct {
+	endpoints map[string]bool
+}
+
+func (e *endpointCollector) Init()                        { e.endpoints = make(map[string]bool) }
+func (e *endpointCollector) GetOptions() []modules.Option { return nil }
+func (e *endpointCollector) GetRegistry() modules.Registry {
+	return modules.Registry{Name: "Endpoints"}
+}
+
+func (e *endpointCollector) Inspect(webData modules.WebData) error {
+	for _, m := range endpointRegex.FindAllStringSubmatch(webData.Body, -1) {
+		e.endpoints[m[1]] = true
+	}
+	return nil
+}
+
+func (e *endpointCollector) MarshalState() ([]byte, error) {
+	var found []string
+	for endpoint := range e.endpoints {
+		found = append(found, endpoint)
+	}
+	sort.Strings(found)
+	return json.Marshal(found)
+}
+
+func (e *endpointCollector) UnmarshalState(data []byte) error {
+	var found []string
+	if err := json.Unmarshal(data, &found); err != nil {
+		return err
+	}
+	for _, endpoint := range found {
+		e.endpoints[endpoint] = true
+	}
+	return nil
+}
+
+func TestSaveAndLoadState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-state")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.json")
+
+	first := &endpointCollector{}
+	d := Debugger{}
+	d.AddInspector(first)
+	d.CallInspectors(modules.WebData{Body: `fetch("/api/users"); fetch("/api/orders/1")`, Type: "Script"})
+	assert.Equal(t, d.SaveState(path), nil)
+
+	// the next session starts from what the first one found
+	second := &endpointCollector{}
+	d = Debugger{Options: Options{StateFile: path}}
+	d.AddInspector(second)
+	assert.Equal(t, d.loadStateFile(), nil)
+	d.CallInspectors(modules.WebData{Body: `fetch("/api/admin")`, Type: "Script"})
+	state, err := second.MarshalState()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(state), `["/api/admin","/api/orders/1","/api/users"]`)
+}
+
+func TestLoadStateFile(t *testing.T) {
+	// the first session has nothing to restore
+	d := Debugger{Options: Options{StateFile: filepath.Join(os.TempDir(), "gorp-state-never-written.json")}}
+	d.AddInspector(&endpointCollector{})
+	assert.Equal(t, d.loadStateFile(), nil)
+
+	f, err := ioutil.TempFile("", "gorp-state-")
+	assert.Equal(t, err, nil)
+	defer os.Remove(f.Name())
+	f.WriteString(`{"inspector/Endpoints": "bm90IGpzb24="}`)
+	f.Close()
+	d.Options.StateFile = f.Name()
+	assert.Equal(t, d.loadStateFile() != nil, true)
+}
+
+func TestModuleState(t *testing.T) {
+	d := Debugger{}
+	d.AddInspector(&endpointCollector{})
+	d.CallInspectors(modules.WebData{Body: `fetch("/api/users")`, Type: "Script"})
+	state, err := d.ModuleState("Endpoints")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(state), `["/api/users"]`)
+
+	_, err = d.ModuleState("Unknown")
+	assert.Equal(t, err != nil, true)
+}
@@ -0,0 +1,97 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/wirepair/gcd"
+	"github.com/wirepair/gcd/gcdapi"
+	"log"
+)
+
+// storageBinding is the name of the binding the storage hooks report through
+const storageBinding = "__gorpStorage"
+
+// storageHookScript wraps Storage.prototype.setItem so that every write to localStorage and sessionStorage is
+// reported to the storageBinding before it happens. Values assigned as properties, such as localStorage.token = t,
+// do not go through setItem and are not reported
+const storageHookScript = `(function() {
+	var binding = window.` + storageBinding + `;
+	if (typeof binding !== "function" || typeof Storage === "undefined") {
+		return;
+	}
+	var originalSetItem = Storage.prototype.setItem;
+	var nativeToString = Function.prototype.toString;
+	var hookedSetItem = function(key, value) {
+		try {
+			var storage = this === window.sessionStorage ? "sessionStorage" : "localStorage";
+			binding(JSON.stringify({storage: storage, key: String(key), value: String(value), url: location.href}));
+		} catch (e) {}
+		return originalSetItem.apply(this, arguments);
+	};
+	hookedSetItem.toString = function() { return nativeToString.call(originalSetItem); };
+	Storage.prototype.setItem = hookedSetItem;
+})();`
+
+// storageWrite is the payload sent by storageHookScript
+type storageWrite struct {
+	Storage string `json:"storage"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Url     string `json:"url"`
+}
+
+// SetupStorageHooks reports writes to localStorage and sessionStorage at runtime to inspectors, as web data of
+// type "Storage" whose body is the equivalent setItem call, such as localStorage.setItem("token", "abc"), and url
+// the page that wrote it.
+func (d *Debugger) SetupStorageHooks() error {
+	if _, err := d.Target.Runtime.AddBindingWithParams(&gcdapi.RuntimeAddBindingParams{Name: storageBinding}); err != nil {
+		return fmt.Errorf("unable to add storage binding: %s", err)
+	}
+	d.subscribeBindings()
+	script := storageHookScript
+	d.InjectScriptAsPageObject(&script)
+	return nil
+}
+
+// subscribeBindings passes the calls to the bindings of the hooks to their handlers. Chrome reports the calls
+// to every binding as the same event, which can only be subscribed to once
+func (d *Debugger) subscribeBindings() {
+	d.bindingsOnce.Do(func() {
+		d.Target.Subscribe("Runtime.bindingCalled", func(target *gcd.ChromeTarget, v []byte) {
+			msg := &gcdapi.RuntimeBindingCalledEvent{}
+			err := json.Unmarshal(v, msg)
+			if err != nil {
+				log.Println("[-] Unable to read binding event", err)
+				return
+			}
+			d.handleStorageWrite(msg)
+		})
+	})
+}
+
+// handleStorageWrite passes the writes reported by the storage hooks to inspectors
+func (d *Debugger) handleStorageWrite(msg *gcdapi.RuntimeBindingCalledEvent) {
+	if msg.Params.Name != storageBinding {
+		return
+	}
+	write := storageWrite{}
+	if err := json.Unmarshal([]byte(msg.Params.Payload), &write); err != nil {
+		d.log("[-] Unable to read storage write", err)
+		return
+	}
+	if write.Storage != "localStorage" && write.Storage != "sessionStorage" {
+		d.log("[-] Unknown storage "+write.Storage+" written to on "+write.Url, nil)
+		return
+	}
+	key, _ := json.Marshal(write.Key)
+	value, _ := json.Marshal(write.Value)
+	d.log(fmt.Sprintf("[+] %s item %s written on %s", write.Storage, key, write.Url), nil)
+
+	d.CallInspectors(modules.WebData{
+		Body:     write.Storage + ".setItem(" + string(key) + ", " + string(value) + ")",
+		Type:     "Storage",
+		Url:      write.Url,
+		Findings: d.Findings,
+	})
+}
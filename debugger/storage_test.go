@@ -0,0 +1,52 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/api"
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd/gcdapi"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func bindingCalledEvent(name string, payload string) *gcdapi.RuntimeBindingCalledEvent {
+	msg := &gcdapi.RuntimeBindingCalledEvent{}
+	msg.Params.Name = name
+	msg.Params.Payload = payload
+	return msg
+}
+
+func TestStorageWriteCaptured(t *testing.T) {
+	var mu sync.Mutex
+	var captured []modules.WebData
+	d := Debugger{Modules: modules.Modules{Inspectors: []modules.InspectorModule{{
+		Registry: modules.Registry{Name: "StorageLogger"},
+		Inspect: func(webData modules.WebData) error {
+			mu.Lock()
+			defer mu.Unlock()
+			captured = append(captured, webData)
+			return nil
+		},
+	}}}}
+
+	// what the wrapped setItem sends for localStorage.setItem("token", "eyJhbGciOi\"x")
+	d.handleStorageWrite(bindingCalledEvent(storageBinding,
+		`{"storage":"localStorage","key":"token","value":"eyJhbGciOi\"x","url":"https://example.com/login"}`))
+	// calls to other bindings are not ours
+	d.handleStorageWrite(bindingCalledEvent("otherBinding", `{"storage":"localStorage","key":"x"}`))
+
+	assert.Equal(t, len(captured), 1)
+	assert.Equal(t, captured[0].Type, "Storage")
+	assert.Equal(t, captured[0].Body, `localStorage.setItem("token", "eyJhbGciOi\"x")`)
+	assert.Equal(t, captured[0].Url, "https://example.com/login")
+
+	writes := api.FindSensitiveStorageWrites(captured[0].Body)
+	assert.Equal(t, len(writes), 1)
+	assert.Equal(t, writes[0].Key, "token")
+}
+
+func TestStorageHookScript(t *testing.T) {
+	assert.Equal(t, strings.Contains(storageHookScript, "window."+storageBinding+";"), true)
+	assert.Equal(t, strings.Contains(storageHookScript, "Storage.prototype.setItem = "), true)
+}
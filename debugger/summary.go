@@ -0,0 +1,90 @@
+package debugger
+
+import (
+	"fmt"
+	"github.com/DharmaOfCode/gorp/modules"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// ModuleSummary describes what a module did during a session
+type ModuleSummary struct {
+	Module       string
+	Kind         string // "processor" or "inspector"
+	Urls         int    // Number of distinct urls changed by a processor, or inspected by an inspector
+	Changes      int    // Number of bodies changed by a processor
+	Replacements int    // Number of changed regions, bodies that changed too much to count are left out
+	ByteDelta    int    // Total difference in size of the changed bodies
+}
+
+// moduleStats accumulates the counters of a ModuleSummary
+type moduleStats struct {
+	summary ModuleSummary
+	urls    map[string]bool
+}
+
+// recordModule updates the counters of a module for a processed or inspected url.
+// change is nil for inspectors and for processors that left the body untouched
+func (d *Debugger) recordModule(name string, kind string, url string, change *modules.Change) {
+	d.statsLock.Lock()
+	defer d.statsLock.Unlock()
+	if d.stats == nil {
+		d.stats = make(map[string]*moduleStats)
+	}
+	key := kind + "/" + name
+	s, ok := d.stats[key]
+	if !ok {
+		s = &moduleStats{summary: ModuleSummary{Module: name, Kind: kind}, urls: make(map[string]bool)}
+		d.stats[key] = s
+	}
+	if kind == "processor" && change == nil {
+		return
+	}
+	s.urls[url] = true
+	s.summary.Urls = len(s.urls)
+	if change != nil {
+		s.summary.Changes++
+		if change.Replacements > 0 {
+			s.summary.Replacements += change.Replacements
+		}
+		s.summary.ByteDelta += change.ByteDelta
+	}
+}
+
+// Summary returns what each module did during the session, processors first, sorted by name
+func (d *Debugger) Summary() []ModuleSummary {
+	d.statsLock.Lock()
+	defer d.statsLock.Unlock()
+	result := make([]ModuleSummary, 0, len(d.stats))
+	for _, s := range d.stats {
+		result = append(result, s.summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind == "processor"
+		}
+		return result[i].Module < result[j].Module
+	})
+	return result
+}
+
+// PrintSummary prints the session summary as a table, through the debugger log so that it also ends up in
+// Options.LogFile
+func (d *Debugger) PrintSummary() {
+	for _, line := range strings.Split(strings.TrimSuffix(d.summaryTable(), "\n"), "\n") {
+		d.log(line, nil)
+	}
+}
+
+// summaryTable formats the session summary as a table with aligned columns
+func (d *Debugger) summaryTable() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tKIND\tURLS\tCHANGES\tREPLACEMENTS\tBYTES")
+	for _, s := range d.Summary() {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%+d\n", s.Module, s.Kind, s.Urls, s.Changes, s.Replacements, s.ByteDelta)
+	}
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package debugger
+
+import (
+	"github.com/DharmaOfCode/gorp/modules"
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	d := Debugger{
+		Modules: modules.Modules{
+			Processors: []modules.ProcessorModule{
+				{
+					Registry: modules.Registry{Name: "Unhider"},
+					Process: func(webData modules.WebData) (string, error) {
+						return strings.Replace(webData.Body, "hidden", "", -1), nil
+					},
+				},
+				{
+					Registry: modules.Registry{Name: "Banner"},
+					Process: func(webData modules.WebData) (string, error) {
+						return webData.Body + "<!-- gorp -->", nil
+					},
+				},
+			},
+			Inspectors: []modules.InspectorModule{
+				{
+					Registry: modules.Registry{Name: "Noop"},
+					Inspect:  func(webData modules.WebData) error { return nil },
+				},
+			},
+		},
+	}
+
+	bodies := map[string]string{
+		"http://example.com/":        `<div hidden></div><p hidden></p>`,
+		"http://example.com/about":   `<p>about</p>`,
+		"http://example.com/contact": `<p>contact</p>`,
+	}
+	for url, body := range bodies {
+		data := modules.WebData{Body: body, Url: url, Type: "Document"}
+		_, err := d.CallProcessors(data)
+		assert.Equal(t, err, nil)
+		d.CallInspectors(data)
+	}
+	// the same url again only counts once
+	d.processBody(modules.WebData{Body: "hidden", Url: "http://example.com/", Type: "Document"})
+
+	assert.Equal(t, d.Summary(), []ModuleSummary{
+		{Module: "Banner", Kind: "processor", Urls: 3, Changes: 4, Replacements: 4, ByteDelta: 4 * len("<!-- gorp -->")},
+		{Module: "Unhider", Kind: "processor", Urls: 1, Changes: 2, Replacements: 3, ByteDelta: -3 * len("hidden")},
+		{Module: "Noop", Kind: "inspector", Urls: 3},
+	})
+}
+
+func TestPrintSummary(t *testing.T) {
+	d := Debugger{MessageChan: make(chan string, 10)}
+	d.recordModule("Unhider", "processor", "http://example.com/", &modules.Change{Replacements: 2, ByteDelta: -12})
+	d.PrintSummary()
+	close(d.MessageChan)
+
+	var lines []string
+	for l := range d.MessageChan {
+		lines = append(lines, l)
+	}
+	assert.Equal(t, lines, []string{
+		"MODULE   KIND       URLS  CHANGES  REPLACEMENTS  BYTES\n",
+		"Unhider  processor  1     1        2             -12\n",
+	})
+}
@@ -0,0 +1,79 @@
+package debugger
+
+import (
+	"fmt"
+	"github.com/wirepair/gcd"
+)
+
+// TargetInfo describes a Chrome Dev Tools target, such as an open tab
+type TargetInfo struct {
+	Id    string // Id to pass to AttachToTarget
+	Type  string // Kind of target, "page" for tabs
+	Title string
+	Url   string
+}
+
+// targetSource is the subset of gcd.Gcd used to enumerate the targets of a running Chrome.
+// It is implemented by gcd.Gcd.
+type targetSource interface {
+	GetTargets() ([]*gcd.ChromeTarget, error)
+}
+
+// ListTargets returns the targets of the running Chrome, in the order Chrome lists them
+func (d *Debugger) ListTargets() ([]TargetInfo, error) {
+	targets, err := d.chromeTargets()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]TargetInfo, 0, len(targets))
+	for _, t := range targets {
+		if t.Target == nil {
+			continue
+		}
+		infos = append(infos, TargetInfo{Id: t.Target.Id, Type: t.Target.Type, Title: t.Target.Title, Url: t.Target.Url})
+	}
+	return infos, nil
+}
+
+// AttachToTarget makes the target with the given id, as returned by ListTargets, the debugger target and starts
+// intercepting its requests. gorp must already be connected to Chrome, which Start takes care of when
+// Options.TargetId is set
+func (d *Debugger) AttachToTarget(targetID string) error {
+	target, err := d.findTarget(targetID)
+	if err != nil {
+		return err
+	}
+	if err := d.enableTarget(target); err != nil {
+		return err
+	}
+	return d.setupTarget()
+}
+
+// findTarget returns the target of the running Chrome with the given id
+func (d *Debugger) findTarget(targetID string) (*gcd.ChromeTarget, error) {
+	targets, err := d.chromeTargets()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range targets {
+		if t.Target != nil && t.Target.Id == targetID {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("no target with id %s", targetID)
+}
+
+func (d *Debugger) chromeTargets() ([]*gcd.ChromeTarget, error) {
+	source := d.targets
+	if source == nil {
+		if d.ChromeProxy == nil {
+			return nil, fmt.Errorf("chrome is not running")
+		}
+		source = d.ChromeProxy
+	}
+	targets, err := source.GetTargets()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list targets: %s", err)
+	}
+	return targets, nil
+}
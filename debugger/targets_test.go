@@ -0,0 +1,52 @@
+package debugger
+
+import (
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"github.com/wirepair/gcd"
+	"testing"
+)
+
+// mockTargets stands in for gcd.Gcd, listing a fixed set of targets
+type mockTargets struct {
+	targets []*gcd.ChromeTarget
+	err     error
+}
+
+func (m *mockTargets) GetTargets() ([]*gcd.ChromeTarget, error) {
+	return m.targets, m.err
+}
+
+func chromeTarget(id string, kind string, title string, url string) *gcd.ChromeTarget {
+	return &gcd.ChromeTarget{Target: &gcd.TargetInfo{Id: id, Type: kind, Title: title, Url: url}}
+}
+
+func TestListTargets(t *testing.T) {
+	d := Debugger{targets: &mockTargets{targets: []*gcd.ChromeTarget{
+		chromeTarget("A1", "page", "Inbox", "https://mail.example.com/"),
+		chromeTarget("B2", "service_worker", "", "https://mail.example.com/sw.js"),
+	}}}
+
+	infos, err := d.ListTargets()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, infos, []TargetInfo{
+		{Id: "A1", Type: "page", Title: "Inbox", Url: "https://mail.example.com/"},
+		{Id: "B2", Type: "service_worker", Url: "https://mail.example.com/sw.js"},
+	})
+
+	target, err := d.findTarget("A1")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, target.Target.Title, "Inbox")
+}
+
+func TestAttachToUnknownTarget(t *testing.T) {
+	d := Debugger{targets: &mockTargets{targets: []*gcd.ChromeTarget{chromeTarget("A1", "page", "Inbox", "")}}}
+	assert.Equal(t, d.AttachToTarget("C3").Error(), "no target with id C3")
+	assert.Equal(t, d.Target == nil, true)
+
+	d = Debugger{targets: &mockTargets{err: errors.New("connection refused")}}
+	assert.Equal(t, d.AttachToTarget("A1").Error(), "unable to list targets: connection refused")
+
+	_, err := (&Debugger{}).ListTargets()
+	assert.Equal(t, err.Error(), "chrome is not running")
+}
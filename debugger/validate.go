@@ -0,0 +1,205 @@
+package debugger
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidationError lists every problem found in a set of options
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid options: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the options for invalid values and combinations, so that they are reported before Chrome
+// is started rather than halfway through a session.
+// It returns a *ValidationError listing all problems found, or nil
+func (o Options) Validate() error {
+	var problems []string
+	addf := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+
+	if o.Port != "" {
+		if p, err := strconv.Atoi(o.Port); err != nil || p < 1 || p > 65535 {
+			addf("port %q is not a valid port number", o.Port)
+		}
+	}
+	if o.UpstreamProxy != "" {
+		if u, err := url.Parse(o.UpstreamProxy); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			addf("upstreamProxy %q is not an http or https proxy url", o.UpstreamProxy)
+		}
+	}
+	if (o.ClientCert == "") != (o.ClientKey == "") {
+		addf("clientCert and clientKey must be set together")
+	}
+	if o.MaxInflight < 0 {
+		addf("maxInflight must not be negative, got %d", o.MaxInflight)
+	}
+	if o.DumpProcessorInputs && o.ProcessorInputDir == "" {
+		addf("dumpProcessorInputs requires processorInputDir")
+	}
+	if _, ok := DevicePresets[o.Device]; o.Device != "" && !ok {
+		addf("device %q is not one of %s", o.Device, devicePresetNames())
+	}
+	if o.CPUThrottle != 0 && o.CPUThrottle < 1 {
+		addf("cpuThrottle must be 1 or more, got %g", o.CPUThrottle)
+	}
+	if o.DialogAction != "" && o.DialogAction != DialogAccept && o.DialogAction != DialogDismiss {
+		addf("dialogAction %q is not one of %s or %s", o.DialogAction, DialogAccept, DialogDismiss)
+	}
+	if o.SessionStore != nil && o.RecordFixtures != "" {
+		addf("sessionStore and recordFixtures cannot both be set")
+	}
+	if o.RequestLogSize < 0 {
+		addf("requestLogSize must not be negative, got %d", o.RequestLogSize)
+	}
+	if o.RecentRequests < 0 {
+		addf("recentRequests must not be negative, got %d", o.RecentRequests)
+	}
+	if o.NavigationRetries < 0 {
+		addf("navigationRetries must not be negative, got %d", o.NavigationRetries)
+	}
+	if o.NavigationTimeout < 0 {
+		addf("navigationTimeout must not be negative, got %s", o.NavigationTimeout)
+	}
+	if o.MaxRequests < 0 {
+		addf("maxRequests must not be negative, got %d", o.MaxRequests)
+	}
+	if o.Sampling && (o.SampleRate < 0 || o.SampleRate > 1) {
+		addf("sampleRate must be between 0 and 1, got %g", o.SampleRate)
+	}
+	if o.MaxDuration < 0 {
+		addf("maxDuration must not be negative, got %s", o.MaxDuration)
+	}
+	if o.InterceptionWatchdog < 0 {
+		addf("interceptionWatchdog must not be negative, got %s", o.InterceptionWatchdog)
+	}
+	if o.ProcessorTimeout < 0 {
+		addf("processorTimeout must not be negative, got %s", o.ProcessorTimeout)
+	}
+	if o.Screenshots && o.ScreenshotDir == "" {
+		addf("screenshots are enabled without a screenshot directory")
+	}
+	for _, f := range o.FrameFilter {
+		if strings.TrimSpace(f) == "" {
+			addf("frameFilter contains an empty frame")
+		}
+	}
+	for _, p := range o.ProtocolFilter {
+		if strings.TrimSpace(p) == "" {
+			addf("protocolFilter contains an empty protocol")
+		}
+	}
+	if o.OverlayDir != "" {
+		if fi, err := os.Stat(o.OverlayDir); err != nil || !fi.IsDir() {
+			addf("overlayDir %q is not a directory", o.OverlayDir)
+		}
+	} else if o.OverlayHost != "" {
+		addf("overlayHost requires overlayDir")
+	}
+	patterns := make([]string, 0, len(o.ScriptReplacements))
+	for p := range o.ScriptReplacements {
+		patterns = append(patterns, p)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		path := o.ScriptReplacements[pattern]
+		if pattern == "" {
+			addf("script replacement %s has no url pattern", path)
+		}
+		if path == "" {
+			addf("script replacement for %s has no file", pattern)
+		}
+	}
+	for i, q := range o.QueryOverrides {
+		if q.Url == "" {
+			addf("query override %d has no url pattern", i+1)
+		}
+		for _, name := range q.Remove {
+			if _, ok := q.Set[name]; ok {
+				addf("query override for %s both sets and removes parameter %s", q.Url, name)
+			}
+		}
+	}
+	for i, c := range o.ContentTypeOverrides {
+		if c.Url == "" {
+			addf("content type override %d has no url pattern", i+1)
+		}
+		if _, _, err := mime.ParseMediaType(c.ContentType); err != nil {
+			addf("content type override %d has an invalid content type %q", i+1, c.ContentType)
+		}
+	}
+	for i, r := range o.PostBodyRules {
+		if r.Match == "" {
+			addf("post body rule %d has nothing to match", i+1)
+		} else if _, err := regexp.Compile(r.Match); err != nil {
+			addf("post body rule %d has an invalid pattern: %s", i+1, err)
+		}
+	}
+	for i, r := range o.BodyMatchRules {
+		if r.Match == "" {
+			addf("body match rule %d has nothing to match", i+1)
+		} else if _, err := regexp.Compile(r.Match); err != nil {
+			addf("body match rule %d has an invalid pattern: %s", i+1, err)
+		}
+		if len(r.Processors) == 0 {
+			addf("body match rule %d has no processors", i+1)
+		}
+	}
+	for i, c := range o.HeaderConditions {
+		if strings.TrimSpace(c.Header) == "" {
+			addf("header condition %d has no header", i+1)
+		}
+	}
+	for i, f := range o.FaultRules {
+		if f.Url == "" {
+			addf("fault rule %d has no url pattern", i+1)
+		}
+		switch f.Fault {
+		case FaultError, FaultTruncate, FaultDrop:
+		case FaultDelay:
+			if f.Delay <= 0 {
+				addf("delay fault for %s has no delay", f.Url)
+			}
+		default:
+			addf("fault rule %d has unknown fault %q", i+1, f.Fault)
+		}
+		if f.Probability <= 0 || f.Probability > 1 {
+			addf("fault rule for %s has probability %v, expected a value above 0 and up to 1", f.Url, f.Probability)
+		}
+	}
+	for t, l := range o.Latency {
+		if l.Min < 0 || (l.Max != 0 && l.Max < l.Min) {
+			addf("latency of %s must range from a non-negative delay to a longer one, got %s to %s", t, l.Min, l.Max)
+		}
+	}
+	for i, c := range o.Credentials {
+		// a catch-all pattern would hand the credentials to any site asking for them
+		if c.Url == "" || strings.Trim(c.Url, "*") == "" {
+			addf("credentials %d must be limited to a url pattern", i+1)
+		}
+		if c.Username == "" {
+			addf("credentials %d have no username", i+1)
+		}
+	}
+	for _, h := range o.AllowHeaders {
+		if containsFold(o.DenyHeaders, h) {
+			addf("header %s is both allowed and denied", h)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
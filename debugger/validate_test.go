@@ -0,0 +1,69 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+	"time"
+)
+
+func TestValidateValidOptions(t *testing.T) {
+	opts := Options{
+		Port:               "9222",
+		MaxInflight:        4,
+		ProcessorTimeout:   time.Second,
+		Screenshots:        true,
+		ScreenshotDir:      "./screenshots",
+		ScriptReplacements: map[string]string{"*app.js": "./app.js"},
+		AllowHeaders:       []string{"Content-Type"},
+		DenyHeaders:        []string{"Content-Security-Policy"},
+	}
+	assert.Equal(t, opts.Validate(), nil)
+	assert.Equal(t, Options{}.Validate(), nil)
+}
+
+func TestValidateInvalidOptions(t *testing.T) {
+	opts := Options{
+		Port:               "http",
+		MaxInflight:        -1,
+		NavigationRetries:  -1,
+		ProcessorTimeout:   -time.Second,
+		Screenshots:        true,
+		ScriptReplacements: map[string]string{"*app.js": ""},
+		QueryOverrides: []QueryOverride{
+			{Url: "*example.com*", Set: map[string]string{"debug": "1"}, Remove: []string{"debug"}},
+			{Remove: []string{"token"}},
+		},
+		HeaderConditions: []HeaderCondition{{Value: "1"}},
+		FaultRules: []FaultRule{
+			{Url: "*/api/*", Fault: FaultDelay, Probability: 1.5},
+			{Fault: "crash", Probability: 1},
+		},
+		AllowHeaders: []string{"Set-Cookie"},
+		DenyHeaders:  []string{"set-cookie"},
+	}
+
+	err, ok := opts.Validate().(*ValidationError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, err.Problems, []string{
+		`port "http" is not a valid port number`,
+		"maxInflight must not be negative, got -1",
+		"navigationRetries must not be negative, got -1",
+		"processorTimeout must not be negative, got -1s",
+		"screenshots are enabled without a screenshot directory",
+		"script replacement for *app.js has no file",
+		"query override for *example.com* both sets and removes parameter debug",
+		"query override 2 has no url pattern",
+		"header condition 1 has no header",
+		"delay fault for */api/* has no delay",
+		"fault rule for */api/* has probability 1.5, expected a value above 0 and up to 1",
+		"fault rule 2 has no url pattern",
+		`fault rule 2 has unknown fault "crash"`,
+		"header Set-Cookie is both allowed and denied",
+	})
+}
+
+func TestNewValidatesOptions(t *testing.T) {
+	d, err := New(Options{Port: "70000"})
+	assert.Equal(t, d == nil, true)
+	assert.Equal(t, err.Error(), `invalid options: port "70000" is not a valid port number`)
+}
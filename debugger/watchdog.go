@@ -0,0 +1,87 @@
+package debugger
+
+import (
+	"fmt"
+	"github.com/wirepair/gcd/gcdapi"
+	"strings"
+	"time"
+)
+
+// OnInterceptionStall registers a callback run when the watchdog enabled by Options.InterceptionWatchdog finds
+// that a page is being loaded but nothing was intercepted for that long. idle is the time since the document
+// request was sent, or since the last interception if there was one after it
+func (d *Debugger) OnInterceptionStall(fn func(idle time.Duration)) {
+	d.watchdogLock.Lock()
+	defer d.watchdogLock.Unlock()
+	d.onStall = fn
+}
+
+// startWatchdog periodically checks that interception keeps up with the documents Chrome requests, until the
+// session is stopped
+func (d *Debugger) startWatchdog() {
+	if d.Options.InterceptionWatchdog <= 0 {
+		return
+	}
+	ticker := time.NewTicker(d.Options.InterceptionWatchdog / 4)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				d.checkWatchdog(now)
+			case <-d.Done:
+				return
+			}
+		}
+	}()
+}
+
+// requestSent starts the watchdog countdown when Chrome sends a document request that should be intercepted
+func (d *Debugger) requestSent(msg *gcdapi.NetworkRequestWillBeSentEvent) {
+	if d.Options.InterceptionWatchdog <= 0 || msg.Params.Type != "Document" || msg.Params.Request == nil {
+		return
+	}
+	if d.Options.Scope != "" && !strings.Contains(msg.Params.Request.Url, d.Options.Scope+"/") {
+		return
+	}
+	d.watchdogLock.Lock()
+	defer d.watchdogLock.Unlock()
+	if d.pendingSince.IsZero() {
+		d.pendingSince = time.Now()
+		d.stallReported = false
+	}
+}
+
+// interceptionSeen stops the watchdog countdown, as interception is working
+func (d *Debugger) interceptionSeen() {
+	if d.Options.InterceptionWatchdog <= 0 {
+		return
+	}
+	d.watchdogLock.Lock()
+	defer d.watchdogLock.Unlock()
+	d.pendingSince = time.Time{}
+}
+
+// checkWatchdog warns, once per stall, when a document request was sent over Options.InterceptionWatchdog
+// before now and nothing has been intercepted since
+func (d *Debugger) checkWatchdog(now time.Time) {
+	d.watchdogLock.Lock()
+	if d.pendingSince.IsZero() || d.stallReported {
+		d.watchdogLock.Unlock()
+		return
+	}
+	idle := now.Sub(d.pendingSince)
+	if idle < d.Options.InterceptionWatchdog {
+		d.watchdogLock.Unlock()
+		return
+	}
+	d.stallReported = true
+	fn := d.onStall
+	d.watchdogLock.Unlock()
+
+	d.log(fmt.Sprintf("[-] Nothing intercepted for %s while a page is loading, interception may be broken",
+		idle.Round(time.Millisecond)), nil)
+	if fn != nil {
+		fn(idle)
+	}
+}
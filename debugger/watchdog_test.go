@@ -0,0 +1,62 @@
+package debugger
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+	"time"
+)
+
+func documentSent(t *testing.T, d *Debugger, url string) {
+	d.requestSent(requestWillBeSent(t, `{"requestId":"1","type":"Document","request":{"url":"`+url+`","method":"GET"}}`))
+}
+
+func TestWatchdogStall(t *testing.T) {
+	stalls := make(chan time.Duration, 1)
+	d := &Debugger{
+		Options: Options{InterceptionWatchdog: 40 * time.Millisecond},
+		Done:    make(chan bool),
+	}
+	defer close(d.Done)
+	d.OnInterceptionStall(func(idle time.Duration) {
+		stalls <- idle
+	})
+	d.startWatchdog()
+
+	// the document request is sent, but never intercepted
+	documentSent(t, d, "https://example.com/")
+	select {
+	case idle := <-stalls:
+		assert.Equal(t, idle >= 40*time.Millisecond, true)
+	case <-time.After(time.Second):
+		t.Fatal("the watchdog did not fire")
+	}
+
+	// a stall is only reported once
+	d.checkWatchdog(time.Now().Add(time.Minute))
+	assert.Equal(t, len(stalls), 0)
+}
+
+func TestWatchdogIntercepted(t *testing.T) {
+	var stalls int
+	d := &Debugger{Options: Options{InterceptionWatchdog: time.Second, Scope: "example.com"}}
+	d.OnInterceptionStall(func(idle time.Duration) {
+		stalls++
+	})
+
+	documentSent(t, d, "https://example.com/")
+	d.checkWatchdog(time.Now())
+	assert.Equal(t, stalls, 0)
+
+	d.interceptionSeen()
+	d.checkWatchdog(time.Now().Add(time.Minute))
+	assert.Equal(t, stalls, 0)
+
+	// pages out of scope are not intercepted
+	documentSent(t, d, "https://other.com/")
+	d.checkWatchdog(time.Now().Add(time.Minute))
+	assert.Equal(t, stalls, 0)
+
+	documentSent(t, d, "https://example.com/next")
+	d.checkWatchdog(time.Now().Add(time.Minute))
+	assert.Equal(t, stalls, 1)
+}
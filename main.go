@@ -7,8 +7,6 @@ import (
 	"github.com/DharmaOfCode/gorp/debugger"
 	"github.com/DharmaOfCode/gorp/modules"
 	"github.com/spf13/viper"
-	"github.com/wirepair/gcd"
-	"github.com/wirepair/gcd/gcdapi"
 	"io/ioutil"
 	"log"
 	"os"
@@ -19,7 +17,7 @@ import (
 
 // State identifies the state of a gorp session.
 type State struct {
-	Debugger         debugger.Debugger // Debugger object
+	Debugger         *debugger.Debugger // Debugger object
 	Modules          modules.Modules   //Selected modules
 	ModPath          string            // Module path
 	Run              bool              // Whether to run a session
@@ -102,63 +100,140 @@ func RunGorp(s *State) {
 		os.Exit(1)
 	}
 	// Setup the debugger
-	s.Debugger = debugger.Debugger{
-		Modules: s.Modules,
+	opts := debugger.Options{
+		Verbose:                   config.Verbose,
+		Color:                     config.Color,
+		EnableConsole:             true,
+		Scope:                     config.Scope,
+		LogFile:                   "./logs/testlogs.txt",
+		ChromePath:                chromePath,
+		UserDir:                   dumpDir,
+		Port:                      debugPort,
+		TargetId:                  config.TargetId,
+		Flags:                     config.Flags,
+		Device:                    config.Device,
+		CPUThrottle:               config.CPUThrottle,
+		DialogAction:              config.DialogAction,
+		DialogPromptText:          config.DialogPromptText,
+		InterceptRequests:         config.InterceptRequests,
+		AnswerPreflights:          config.AnswerPreflights,
+		InterceptBinary:           config.InterceptBinary,
+		ProcessDataURIs:           config.ProcessDataURIs,
+		ProcessInlineScripts:      config.ProcessInlineScripts,
+		HookEval:                  config.HookEval,
+		HookStorage:               config.HookStorage,
+		CSPViolations:             config.CSPViolations,
+		ScriptDumpDir:             config.DumpScripts,
+		RecordFixtures:            config.RecordFixtures,
+		StateFile:                 config.StateFile,
+		OpenAPIFile:               config.OpenAPIFile,
+		RecordRawResponses:        config.RecordRawResponses,
+		UpstreamProxy:             config.UpstreamProxy,
+		ClientCert:                config.ClientCert,
+		ClientKey:                 config.ClientKey,
+		DedupFindings:             config.DedupFindings,
+		RecompressResponse:        config.RecompressResponse,
+		KeepDateHeader:            config.KeepDateHeader,
+		InterceptManifests:        config.InterceptManifests,
+		ProcessWorkerScripts:      config.ProcessWorkerScripts,
+		FrameFilter:               config.FrameFilter,
+		FirstPartyOnly:            config.FirstPartyOnly,
+		FirstPartySubdomains:      config.FirstPartySubdomains,
+		InitiatorFilter:           config.InitiatorFilter,
+		ProtocolFilter:            config.ProtocolFilter,
+		MaxInflight:               config.MaxInflight,
+		MaxRequests:               config.MaxRequests,
+		MaxDuration:               config.MaxDuration,
+		InterceptionWatchdog:      config.InterceptionWatchdog,
+		AllowHeaders:              config.AllowHeaders,
+		DenyHeaders:               config.DenyHeaders,
+		ProcessorTimeout:          config.ProcessorTimeout,
+		SafeMode:                  config.SafeMode,
+		SafeModeScripts:           config.SafeModeScripts,
+		ResetFindingsOnNavigation: config.ResetFindingsOnNavigation,
+		NavigationProcessors:      config.NavigationProcessors,
+		NavigationRetries:         config.NavigationRetries,
+		NavigationTimeout:         config.NavigationTimeout,
+		ProcessorInputDir:         config.ProcessorInputDir,
+		DumpProcessorInputs:       config.DumpProcessorInputs,
+		ScriptReplacements:        make(map[string]string),
+		OverlayDir:                config.OverlayDir,
+		OverlayHost:               config.OverlayHost,
+	}
+	if config.SampleRate != nil {
+		opts.Sampling = true
+		opts.SampleRate = *config.SampleRate
+		opts.SampleSeed = config.SampleSeed
+	}
+	for _, c := range config.HeaderConditions {
+		opts.HeaderConditions = append(opts.HeaderConditions, debugger.HeaderCondition(c))
+	}
+	for _, c := range config.ContentTypeOverrides {
+		opts.ContentTypeOverrides = append(opts.ContentTypeOverrides, debugger.ContentTypeOverride(c))
+	}
+	for _, r := range config.PostBodyRules {
+		opts.PostBodyRules = append(opts.PostBodyRules, debugger.PostBodyRule(r))
+	}
+	for _, r := range config.BodyMatchRules {
+		opts.BodyMatchRules = append(opts.BodyMatchRules, debugger.BodyMatchRule(r))
+	}
+	for _, f := range config.FaultRules {
+		opts.FaultRules = append(opts.FaultRules, debugger.FaultRule(f))
+	}
+	for t, l := range config.Latency {
+		if opts.Latency == nil {
+			opts.Latency = make(map[string]debugger.LatencyRange)
+		}
+		opts.Latency[t] = debugger.LatencyRange(l)
+	}
+	for _, c := range config.Credentials {
+		opts.Credentials = append(opts.Credentials, debugger.Credentials(c))
+	}
+	for _, r := range config.ScriptReplacements {
+		opts.ScriptReplacements[r.Url] = r.Path
+	}
+	for _, o := range config.QueryOverrides {
+		override := debugger.QueryOverride{Url: o.Url, Set: make(map[string]string), Remove: o.Remove}
+		for _, p := range o.Set {
+			kv := strings.SplitN(p, "=", 2)
+			if len(kv) != 2 {
+				log.Fatalf("[-] Invalid query parameter %s, expected name=value", p)
+			}
+			override.Set[kv[0]] = kv[1]
+		}
+		opts.QueryOverrides = append(opts.QueryOverrides, override)
+	}
+	if config.Screenshots != nil {
+		opts.Screenshots = true
+		opts.ScreenshotDir = config.Screenshots.Dir
+		if opts.ScreenshotDir == "" {
+			opts.ScreenshotDir = "./screenshots"
+		}
+		opts.FullPageScreenshots = config.Screenshots.FullPage
 	}
 
-	s.Debugger.Options = debugger.Options{
-		Verbose:       config.Verbose,
-		EnableConsole: true,
-		LogFile:  "./logs/testlogs.txt",
+	s.Debugger, err = debugger.New(opts)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
-	s.Debugger.SetupFileLogger()
+	s.Debugger.Modules = s.Modules
 	s.Debugger.XHRBreakPoints = config.XHRBreakPoints
 
-	// TODO: This should be abstracted out in the debugger struct
-	s.Debugger.ChromeProxy = startGcd()
-	defer s.Debugger.ChromeProxy.ExitProcess()
-
-	s.Debugger.StartTarget()
-	//Create a channel to be able to signal a termination to our Chrome connection
-	s.Debugger.Done = make(chan bool)
+	err = s.Debugger.Start()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer s.Debugger.Stop()
 	shouldWait := true
 
-	//Default is everything!
-	docPattern := "*"
-	jsPattern := "*"
-	xhrPattern := "*"
-	if config.Scope != "" {
-		docPattern = "*" + config.Scope + "/*"
-		jsPattern = "*" + config.Scope + "*.js"
-		xhrPattern = "*" + config.Scope + "/*"
-	}
-	patterns := []*gcdapi.NetworkRequestPattern{
-		{
-			UrlPattern:        docPattern,
-			ResourceType:      "Document",
-			InterceptionStage: "HeadersReceived",
-		},
-		{
-			UrlPattern:        jsPattern,
-			ResourceType:      "Script",
-			InterceptionStage: "HeadersReceived",
-		},
-		{
-			UrlPattern:        xhrPattern,
-			ResourceType:      "XHR",
-			InterceptionStage: "HeadersReceived",
-		},
-		{
-			UrlPattern:        "*" + config.Scope + "*.swf",
-			ResourceType:      "Other",
-			InterceptionStage: "HeadersReceived",
-		},
+	for _, b := range config.Breakpoints {
+		if _, err := s.Debugger.SetBreakpointByURL(b.Url, b.Line); err != nil {
+			log.Println("[-] Unable to set breakpoint on "+b.Url, err)
+		}
 	}
 
-	interceptParams := &gcdapi.NetworkSetRequestInterceptionParams{Patterns: patterns}
-
-	s.Debugger.SetupRequestInterception(interceptParams)
-	s.Debugger.SetupDOMDebugger()
 	//Now setup script injector
 	if config.Script != nil{
 		if err != nil{
@@ -177,6 +252,13 @@ func RunGorp(s *State) {
 			}
 		}
 	}
+
+	if config.StartUrl != "" {
+		if err := s.Debugger.Navigate(config.StartUrl); err != nil {
+			log.Println("[-] Unable to open "+config.StartUrl, err)
+		}
+	}
+
 	if shouldWait {
 		log.Println("[+] Waiting for events...")
 
@@ -249,22 +331,6 @@ func initConfig() {
 	err = viper.Unmarshal(&config)
 }
 
-// TODO: Move this to debugger
-func startGcd() *gcd.Gcd {
-	dumpDir = "/tmp/chrome-testing"
-	debugPort = "9222"
-	chromeDebugger := gcd.NewChromeDebugger()
-	//debugger.DeleteProfileOnExit()
-	chromeDebugger.AddFlags(config.Flags)
-	err := chromeDebugger.StartProcess(chromePath, dumpDir, debugPort)
-	if err != nil {
-		panic(fmt.Errorf("Fatal error starting chrome debugger: %s \n", err))
-		os.Exit(1)
-	}
-
-	return chromeDebugger
-}
-
 func containsGorpPlugin(path string) bool {
 	if _, err := os.Stat(path + "/gorpmod.go"); err == nil {
 		return true
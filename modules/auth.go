@@ -0,0 +1,70 @@
+package modules
+
+import (
+	"strings"
+)
+
+// AuthChallenge holds an HTTP authentication challenge, as sent by a server in a WWW-Authenticate header
+type AuthChallenge struct {
+	Scheme string // Authentication scheme in lowercase, such as "basic" or "digest"
+	Realm  string // Protection space the credentials apply to, if given
+	Origin string // Origin of the server or proxy sending the challenge
+	Proxy  bool   // Whether the challenge comes from a proxy, in a Proxy-Authenticate header
+}
+
+// ParseAuthenticate reads the first challenge of a WWW-Authenticate or Proxy-Authenticate header value.
+// It returns nil if the value holds no challenge
+func ParseAuthenticate(value string) *AuthChallenge {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	scheme := value
+	params := ""
+	if i := strings.IndexAny(value, " \t"); i >= 0 {
+		scheme, params = value[:i], value[i+1:]
+	}
+	c := &AuthChallenge{Scheme: strings.ToLower(scheme)}
+	for params != "" {
+		params = strings.TrimLeft(params, " \t,")
+		eq := strings.IndexByte(params, '=')
+		if eq < 0 {
+			break
+		}
+		name := strings.ToLower(strings.TrimSpace(params[:eq]))
+		params = strings.TrimLeft(params[eq+1:], " \t")
+		var v string
+		if strings.HasPrefix(params, `"`) {
+			v, params = quotedString(params[1:])
+		} else if end := strings.IndexByte(params, ','); end >= 0 {
+			v, params = strings.TrimSpace(params[:end]), params[end+1:]
+		} else {
+			v, params = strings.TrimSpace(params), ""
+		}
+		if name == "realm" {
+			c.Realm = v
+			break
+		}
+	}
+	return c
+}
+
+// quotedString reads a quoted string whose opening quote was consumed, unescaping backslashes.
+// It returns the string and what follows the closing quote
+func quotedString(s string) (string, string) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:]
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), ""
+}
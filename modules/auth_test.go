@@ -0,0 +1,13 @@
+package modules
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestParseAuthenticate(t *testing.T) {
+	assert.Equal(t, ParseAuthenticate(`Basic realm="Admin \"area\"", charset="UTF-8"`), &AuthChallenge{Scheme: "basic", Realm: `Admin "area"`})
+	assert.Equal(t, ParseAuthenticate(`Digest qop="auth", realm=files, nonce="abc"`), &AuthChallenge{Scheme: "digest", Realm: "files"})
+	assert.Equal(t, ParseAuthenticate("Negotiate"), &AuthChallenge{Scheme: "negotiate"})
+	assert.Equal(t, ParseAuthenticate(" "), (*AuthChallenge)(nil))
+}
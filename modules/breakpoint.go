@@ -0,0 +1,25 @@
+package modules
+
+// PausedState holds the state of the page when it paused on a breakpoint
+type PausedState struct {
+	Reason      string      // Reason reported by Chrome for pausing, "other" for breakpoints
+	Breakpoints []string    // Ids of the breakpoints that were hit
+	CallFrames  []CallFrame // Call stack, innermost frame first
+}
+
+// CallFrame is a single frame of the call stack of a paused page
+type CallFrame struct {
+	FunctionName string // Empty for top level code
+	Url          string
+	Line         int // 1-based line of the current statement
+	Column       int // 1-based column of the current statement
+	Scopes       []Scope
+}
+
+// Scope holds the variables of a scope of a call frame. Primitive values are kept as is, the way
+// encoding/json decodes them, objects and functions are described by a string such as "Array(3)"
+type Scope struct {
+	Type      string // "local", "closure", "block", "catch", "script", "with" or "module"
+	Name      string // Name of the function for closures, when known
+	Variables map[string]interface{}
+}
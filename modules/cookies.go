@@ -0,0 +1,29 @@
+package modules
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ParseResponseCookies parses the Set-Cookie headers of a response. Chrome reports repeated headers as a single
+// header whose values are separated by newlines. Malformed cookies are skipped.
+// It returns the cookies in the order they were set
+func ParseResponseCookies(headers map[string]interface{}) []*http.Cookie {
+	var lines []string
+	for k, v := range headers {
+		s, ok := v.(string)
+		if !ok || !strings.EqualFold(k, "set-cookie") {
+			continue
+		}
+		for _, l := range strings.Split(s, "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				lines = append(lines, l)
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	res := http.Response{Header: http.Header{"Set-Cookie": lines}}
+	return res.Cookies()
+}
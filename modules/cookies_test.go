@@ -0,0 +1,37 @@
+package modules
+
+import (
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseResponseCookies(t *testing.T) {
+	cookies := ParseResponseCookies(map[string]interface{}{
+		"Content-Type": "text/html",
+		"set-cookie": "session=abc123; Path=/; Secure; HttpOnly; SameSite=Strict\n" +
+			"theme=dark; Expires=Wed, 21 Oct 2026 07:28:00 GMT; Domain=example.com\n" +
+			"tracking=1; Max-Age=3600; SameSite=Lax",
+	})
+	assert.Equal(t, len(cookies), 3)
+
+	assert.Equal(t, cookies[0].Name, "session")
+	assert.Equal(t, cookies[0].Value, "abc123")
+	assert.Equal(t, cookies[0].Path, "/")
+	assert.Equal(t, cookies[0].Secure, true)
+	assert.Equal(t, cookies[0].HttpOnly, true)
+	assert.Equal(t, cookies[0].SameSite, http.SameSiteStrictMode)
+
+	assert.Equal(t, cookies[1].Name, "theme")
+	assert.Equal(t, cookies[1].Domain, "example.com")
+	assert.Equal(t, cookies[1].Expires, time.Date(2026, 10, 21, 7, 28, 0, 0, time.UTC))
+	assert.Equal(t, cookies[1].Secure, false)
+
+	assert.Equal(t, cookies[2].MaxAge, 3600)
+	assert.Equal(t, cookies[2].SameSite, http.SameSiteLaxMode)
+}
+
+func TestParseResponseCookiesNone(t *testing.T) {
+	assert.Equal(t, len(ParseResponseCookies(map[string]interface{}{"Content-Type": "text/html"})), 0)
+}
@@ -0,0 +1,45 @@
+package modules
+
+import (
+	"regexp"
+	"strings"
+)
+
+// cspDirectiveRegex finds the directive in the messages Chrome logs for CSP violations
+var cspDirectiveRegex = regexp.MustCompile(`Content Security Policy directive: "([^"]*)"`)
+
+// cspBlockedRegex finds the url of the resource that was refused, quoted after the kind of resource
+var cspBlockedRegex = regexp.MustCompile(`^(?:\[Report Only\] )?Refused to [^']*'([^']*)' because`)
+
+// CSPViolation holds a violation of the Content Security Policy of a page, as reported by the browser
+type CSPViolation struct {
+	Directive  string // Violated directive, such as "script-src 'self'"
+	BlockedUrl string // Url of the resource that was refused, empty for inline scripts and eval
+	ReportOnly bool   // Whether the policy only reports violations, the resource being loaded anyway
+	Message    string // Message logged by the browser
+}
+
+// ParseCSPViolation reads a CSP violation from a message logged by the browser, such as
+// Refused to load the script 'https://cdn.example.com/a.js' because it violates the following Content Security
+// Policy directive: "script-src 'self'".
+// It returns the violation and whether the message reports one
+func ParseCSPViolation(message string) (*CSPViolation, bool) {
+	m := cspDirectiveRegex.FindStringSubmatch(message)
+	if m == nil {
+		return nil, false
+	}
+	v := &CSPViolation{
+		Directive:  m[1],
+		ReportOnly: strings.HasPrefix(message, "[Report Only]"),
+		Message:    message,
+	}
+	if b := cspBlockedRegex.FindStringSubmatch(message); b != nil {
+		v.BlockedUrl = b[1]
+	}
+	return v, true
+}
+
+// DirectiveName returns the name of the violated directive, such as "script-src"
+func (v *CSPViolation) DirectiveName() string {
+	return strings.SplitN(v.Directive, " ", 2)[0]
+}
@@ -0,0 +1,31 @@
+package modules
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestParseCSPViolation(t *testing.T) {
+	msg := `Refused to load the script 'https://cdn.example.com/a.js' because it violates the following ` +
+		`Content Security Policy directive: "script-src 'self' https://static.example.com". Note that ` +
+		`'script-src-elem' was not explicitly set, so 'script-src' is used as a fallback.`
+	v, ok := ParseCSPViolation(msg)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, *v, CSPViolation{
+		Directive:  "script-src 'self' https://static.example.com",
+		BlockedUrl: "https://cdn.example.com/a.js",
+		Message:    msg,
+	})
+	assert.Equal(t, v.DirectiveName(), "script-src")
+
+	v, ok = ParseCSPViolation(`[Report Only] Refused to execute inline script because it violates the ` +
+		`following Content Security Policy directive: "default-src 'self'". Either the 'unsafe-inline' keyword, ` +
+		`a hash ('sha256-abc='), or a nonce ('nonce-...') is required to enable inline execution.`)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, v.ReportOnly, true)
+	assert.Equal(t, v.BlockedUrl, "")
+	assert.Equal(t, v.DirectiveName(), "default-src")
+
+	_, ok = ParseCSPViolation("Uncaught TypeError: undefined is not a function")
+	assert.Equal(t, ok, false)
+}
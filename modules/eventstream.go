@@ -0,0 +1,8 @@
+package modules
+
+// ServerSentEvent holds a single event of a text/event-stream response
+type ServerSentEvent struct {
+	Id    string // Last event id, as set by the id field
+	Event string // Event type, "message" when the stream does not name it
+	Data  string // Data lines of the event, joined by newlines
+}
@@ -0,0 +1,10 @@
+package modules
+
+// LoadingFailure describes a request that did not complete, as reported by the browser
+type LoadingFailure struct {
+	ErrorText     string // Error reported by the browser, such as "net::ERR_NAME_NOT_RESOLVED"
+	BlockedReason string // Why the browser blocked the request, such as "csp" or "mixed-content", empty when it did not
+	ResourceType  string // Type of the resource that failed to load, such as "Script"
+	Canceled      bool   // Whether the request was canceled, by the page or by navigating away
+	Cause         string // "blocked" or "fault" when gorp failed the request on purpose, empty for genuine failures
+}
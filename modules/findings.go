@@ -0,0 +1,115 @@
+package modules
+
+import (
+	"crypto/sha256"
+	"sync"
+)
+
+// Finding is a piece of information reported by an inspector
+type Finding struct {
+	Rule   string // Name of the module or rule that produced the finding
+	Url    string // URL of the request or response the finding was made on
+	Detail string // What was found
+	Count  int    // Number of times the finding was reported, always 1 unless findings are deduplicated
+}
+
+// findingKey identifies duplicate findings. Details are hashed as they can be large, whole scripts at times
+type findingKey struct {
+	rule   string
+	url    string
+	detail [sha256.Size]byte
+}
+
+// Change summarizes the modifications a processor made to the body of a request or response
+type Change struct {
+	Processor    string // Name of the processor
+	Url          string
+	RequestId    string
+	Replacements int // Number of changed regions of the body, -1 if the body changed too much to count them
+	ByteDelta    int // Difference in size of the body
+}
+
+// Findings collects the findings reported by inspectors and the changes made by processors during a gorp
+// session. It is safe for concurrent use, as inspectors run in their own goroutines.
+type Findings struct {
+	// Dedup reports each finding once, counting how many times it was reported with the same rule, url and
+	// detail. It must be set before any finding is reported
+	Dedup bool
+
+	mu      sync.Mutex
+	list    []Finding
+	index   map[findingKey]int // Position of deduplicated findings in list
+	changes []Change
+}
+
+// Report adds a finding to the collection. It does nothing on a nil Findings so that
+// modules can report findings regardless of how they are run.
+func (f *Findings) Report(finding Finding) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	finding.Count = 1
+	if f.Dedup {
+		key := findingKey{finding.Rule, finding.Url, sha256.Sum256([]byte(finding.Detail))}
+		if i, ok := f.index[key]; ok {
+			f.list[i].Count++
+			return
+		}
+		if f.index == nil {
+			f.index = make(map[findingKey]int)
+		}
+		f.index[key] = len(f.list)
+	}
+	f.list = append(f.list, finding)
+}
+
+// All returns a copy of the findings reported so far
+func (f *Findings) All() []Finding {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Finding(nil), f.list...)
+}
+
+// RecordChange adds a processor change to the change log. It does nothing on a nil Findings
+func (f *Findings) RecordChange(change Change) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.changes = append(f.changes, change)
+}
+
+// Changes returns the changes made to the given request in the order processors made them.
+// All changes are returned when requestId is empty
+func (f *Findings) Changes(requestId string) []Change {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []Change
+	for _, c := range f.changes {
+		if requestId == "" || c.RequestId == requestId {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Reset discards the findings and changes collected so far. It does nothing on a nil Findings
+func (f *Findings) Reset() {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.list = nil
+	f.index = nil
+	f.changes = nil
+}
@@ -0,0 +1,50 @@
+package modules
+
+import (
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFindingsDedup(t *testing.T) {
+	f := &Findings{Dedup: true}
+	secret := Finding{Rule: "JWTFinder", Url: "https://example.com/app.js", Detail: "eyJhbGciOiJIUzI1NiJ9." + strings.Repeat("a", 1000)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.Report(secret)
+		}()
+	}
+	wg.Wait()
+	f.Report(Finding{Rule: "JWTFinder", Url: "https://example.com/other.js", Detail: secret.Detail})
+
+	all := f.All()
+	assert.Equal(t, len(all), 2)
+	assert.Equal(t, all[0].Url, secret.Url)
+	assert.Equal(t, all[0].Count, 50)
+	assert.Equal(t, all[1].Count, 1)
+}
+
+func TestFindingsWithoutDedup(t *testing.T) {
+	f := &Findings{}
+	f.Report(Finding{Rule: "a", Url: "u", Detail: "d"})
+	f.Report(Finding{Rule: "a", Url: "u", Detail: "d"})
+	assert.Equal(t, len(f.All()), 2)
+	assert.Equal(t, f.All()[1].Count, 1)
+}
+
+func TestFindingsReset(t *testing.T) {
+	f := &Findings{Dedup: true}
+	f.Report(Finding{Rule: "a", Url: "https://example.com/", Detail: "x"})
+	f.RecordChange(Change{Processor: "p", RequestId: "1"})
+	f.Reset()
+	assert.Equal(t, len(f.All()), 0)
+	assert.Equal(t, len(f.Changes("")), 0)
+
+	f.Report(Finding{Rule: "a", Url: "https://example.com/", Detail: "x"})
+	assert.Equal(t, f.All()[0].Count, 1)
+}
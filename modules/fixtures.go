@@ -0,0 +1,106 @@
+package modules
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+// FixtureRecorder writes web data to a fixture file, one JSON object per line, so that real traffic can be
+// replayed through modules in unit tests with LoadFixtures. It is the file backed SessionStore
+type FixtureRecorder = FileStore
+
+// NewFixtureRecorder creates the fixture file at path, or appends to it if it already exists.
+// It returns a pointer to a FixtureRecorder and an error
+func NewFixtureRecorder(path string) (*FixtureRecorder, error) {
+	return NewFileStore(path)
+}
+
+// Record adds webData to the fixture file, like Append
+func (s *FileStore) Record(webData WebData) error {
+	return s.Append(webData)
+}
+
+// LoadFixtures reads the web data recorded in a fixture file. Files holding a JSON array of web data are
+// read as well, for fixtures written by hand. Multipart request bodies are parsed again, so that fixtures can be
+// passed to modules as they would be during a session.
+// It returns the list of web data and an error
+func LoadFixtures(path string) ([]WebData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var result []WebData
+	err = decodeFixtures(f, func(webData WebData) error {
+		result = append(result, webData)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// decodeFixtures calls fn with every web data of a fixture file, JSON objects or arrays of them, as
+// LoadFixtures returns them
+func decodeFixtures(r io.Reader, fn func(webData WebData) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		err := dec.Decode(&raw)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var list []WebData
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return err
+			}
+		} else {
+			var w WebData
+			if err := json.Unmarshal(raw, &w); err != nil {
+				return err
+			}
+			list = append(list, w)
+		}
+		for _, w := range list {
+			restoreWebData(&w)
+			if err := fn(w); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// restoreWebData parses again what is left out of fixtures, response cookies and multipart request bodies.
+// The body is already held in memory, so parts are never spooled to temporary files that nobody would remove.
+func restoreWebData(w *WebData) {
+	if w.Type != "Request" {
+		w.ResponseCookies = ParseResponseCookies(w.Headers)
+	}
+	contentType := headerValue(w.Headers, "content-type")
+	if w.Type == "Request" && strings.HasPrefix(contentType, "multipart/form-data") {
+		form, err := ParseMultipart(contentType, strings.NewReader(w.Body), int64(len(w.Body)))
+		if err == nil {
+			w.Multipart = form
+		}
+	}
+}
+
+// headerValue returns the value of a header regardless of the casing used for its name
+func headerValue(headers map[string]interface{}, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			if s, ok := v.(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
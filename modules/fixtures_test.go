@@ -0,0 +1,73 @@
+package modules
+
+import (
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixturesRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-fixtures")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fixtures.json")
+
+	recorded := []WebData{
+		{
+			Body:           `var isAdmin=false;`,
+			Headers:        map[string]interface{}{"Content-Type": "application/javascript"},
+			RequestHeaders: map[string]interface{}{"X-RE": "1"},
+			Type:           "Script",
+			Url:            "https://example.com/app.js",
+			Method:         "GET",
+			RequestId:      "1",
+			Findings:       &Findings{},
+		},
+		{
+			Body:      multipartBody,
+			Headers:   map[string]interface{}{"Content-Type": multipartType},
+			Type:      "Request",
+			Url:       "https://example.com/upload",
+			Method:    "POST",
+			RequestId: "2",
+		},
+	}
+	r, err := NewFixtureRecorder(path)
+	assert.Equal(t, err, nil)
+	for _, w := range recorded {
+		assert.Equal(t, r.Record(w), nil)
+	}
+	assert.Equal(t, r.Close(), nil)
+
+	fixtures, err := LoadFixtures(path)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(fixtures), 2)
+	assert.Equal(t, fixtures[0].Url, "https://example.com/app.js")
+	assert.Equal(t, fixtures[0].RequestHeaders["X-RE"], "1")
+	assert.Equal(t, fixtures[0].Findings == nil, true)
+	assert.Equal(t, fixtures[1].Multipart.Parts[1].FileName, "avatar.svg")
+	assert.Equal(t, fixtures[1].Multipart.Parts[1].FilePath, "")
+
+	processor := ProcessorModule{Process: func(webData WebData) (string, error) {
+		return strings.Replace(webData.Body, "isAdmin=false", "isAdmin=true", -1), nil
+	}}
+	body, err := processor.Process(fixtures[0])
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body, `var isAdmin=true;`)
+}
+
+func TestLoadFixturesArray(t *testing.T) {
+	f, err := ioutil.TempFile("", "gorp-fixtures")
+	assert.Equal(t, err, nil)
+	defer os.Remove(f.Name())
+	f.WriteString(`[{"Body": "<html></html>", "Type": "Document", "Url": "https://example.com/"}]`)
+	f.Close()
+
+	fixtures, err := LoadFixtures(f.Name())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(fixtures), 1)
+	assert.Equal(t, fixtures[0].Type, "Document")
+}
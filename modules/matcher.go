@@ -0,0 +1,34 @@
+package modules
+
+// Matcher selects web data by arbitrary criteria, for programs embedding gorp that need more than the url
+// patterns and filters of the options
+type Matcher interface {
+	Match(webData WebData) bool // Match reports whether the web data is selected
+}
+
+// MatcherFunc adapts an ordinary function to a Matcher
+type MatcherFunc func(webData WebData) bool
+
+// Match calls f(webData)
+func (f MatcherFunc) Match(webData WebData) bool {
+	return f(webData)
+}
+
+// MatchRedirects wraps a matcher so that it also selects web data when it would select any of the urls the
+// request was redirected from. This keeps rules written for an origin applying to requests it redirects to
+// other origins
+func MatchRedirects(m Matcher) Matcher {
+	return MatcherFunc(func(webData WebData) bool {
+		if m.Match(webData) {
+			return true
+		}
+		for _, url := range webData.Redirects {
+			leg := webData
+			leg.Url = url
+			if m.Match(leg) {
+				return true
+			}
+		}
+		return false
+	})
+}
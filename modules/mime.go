@@ -0,0 +1,64 @@
+package modules
+
+import (
+	"mime"
+	"strings"
+)
+
+// KindJavaScript is the content kind of responses served with any of the JavaScript MIME types
+const KindJavaScript = "javascript"
+
+// KindIcon is the content kind of .ico files, such as favicons, served with any of the MIME types used for them
+const KindIcon = "icon"
+
+// javaScriptTypes are the MIME types browsers treat as JavaScript, as listed by the MIME Sniffing standard
+var javaScriptTypes = map[string]bool{
+	"application/ecmascript":   true,
+	"application/javascript":   true,
+	"application/x-ecmascript": true,
+	"application/x-javascript": true,
+	"text/ecmascript":          true,
+	"text/javascript":          true,
+	"text/javascript1.0":       true,
+	"text/javascript1.1":       true,
+	"text/javascript1.2":       true,
+	"text/javascript1.3":       true,
+	"text/javascript1.4":       true,
+	"text/javascript1.5":       true,
+	"text/jscript":             true,
+	"text/livescript":          true,
+	"text/x-ecmascript":        true,
+	"text/x-javascript":        true,
+}
+
+// ContentKind classifies the value of a Content-Type header. Every JavaScript MIME type is classified as
+// KindJavaScript, and every .ico one as KindIcon, so that modules do not have to know which one a server uses.
+// Any other type is returned as its lowercase media type, without parameters such as the charset
+func ContentKind(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	}
+	if javaScriptTypes[mediaType] {
+		return KindJavaScript
+	}
+	if mediaType == "image/x-icon" || mediaType == "image/vnd.microsoft.icon" {
+		return KindIcon
+	}
+	return mediaType
+}
+
+// IsJavaScript reports whether the value of a Content-Type header is one of the JavaScript MIME types
+func IsJavaScript(contentType string) bool {
+	return ContentKind(contentType) == KindJavaScript
+}
+
+// IsManifest reports whether the value of a Content-Type header is the one used for web app manifests
+func IsManifest(contentType string) bool {
+	return ContentKind(contentType) == "application/manifest+json"
+}
+
+// IsIcon reports whether the value of a Content-Type header is one used for .ico files, such as favicons
+func IsIcon(contentType string) bool {
+	return ContentKind(contentType) == KindIcon
+}
@@ -0,0 +1,31 @@
+package modules
+
+import (
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestContentKind(t *testing.T) {
+	for _, contentType := range []string{
+		"text/javascript",
+		"application/javascript",
+		"application/x-javascript",
+		"Text/JavaScript; charset=UTF-8",
+		"text/ecmascript",
+	} {
+		assert.Equal(t, ContentKind(contentType), KindJavaScript, contentType)
+		assert.Equal(t, IsJavaScript(contentType), true, contentType)
+	}
+	assert.Equal(t, ContentKind("application/json; charset=utf-8"), "application/json")
+	assert.Equal(t, ContentKind("TEXT/HTML;;"), "text/html")
+	assert.Equal(t, IsJavaScript(""), false)
+}
+
+func TestManifestAndIconTypes(t *testing.T) {
+	assert.Equal(t, IsManifest("application/manifest+json; charset=utf-8"), true)
+	assert.Equal(t, IsManifest("application/json"), false)
+	assert.Equal(t, IsIcon("image/x-icon"), true)
+	assert.Equal(t, IsIcon("image/vnd.microsoft.icon"), true)
+	assert.Equal(t, IsIcon("image/png"), false)
+	assert.Equal(t, ContentKind("image/vnd.microsoft.icon"), KindIcon)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/DharmaOfCode/gorp/base"
 	"github.com/fatih/color"
+	"net/http"
 	"plugin"
 )
 
@@ -33,19 +34,30 @@ type Option struct {
 	Description string `json:"description"` // A description of the option
 }
 
-// ProcessorModule represents a processor module. Processor modules alter the body of a request or response
+// ProcessorModule represents a processor module. Processor modules alter the body of a request or response.
+// Binary processor modules set ProcessBinary rather than Process.
 type ProcessorModule struct {
-	Process  func(webData WebData) (string, error)
-	Registry Registry
-	Options  []Option `json:"options"` // A list of configurable options/arguments for the module
+	Process        func(webData WebData) (string, error)
+	ProcessBinary  func(webData WebData, body []byte) ([]byte, error)
+	ProcessLine    func(webData WebData, line string, index int) (string, error)
+	ContentType    func(webData WebData) string // Content-Type the processor serves responses with, nil when it keeps it
+	Configure      func(options []Option) error // Checks and reads the options once they are set, nil when the processor does not
+	MarshalState   func() ([]byte, error)       // Returns what the processor accumulated, nil when it is stateless
+	UnmarshalState func(data []byte) error      // Restores what MarshalState returned, nil when the processor is stateless
+	Registry       Registry
+	Options        []Option `json:"options"` // A list of configurable options/arguments for the module
 }
 
 // InspectorModule represents an inspector module. Inspectors analyse responses to answer questions about the
 // application or to discover different types of information found in HTML documents, JavaScript comments and code
 type InspectorModule struct {
-	Inspect  func(webData WebData) error
-	Registry Registry
-	Options  []Option
+	Inspect        func(webData WebData) error
+	InspectLine    func(webData WebData, line string, index int) error
+	Configure      func(options []Option) error // Checks and reads the options once they are set, nil when the inspector does not
+	MarshalState   func() ([]byte, error)       // Returns what the inspector accumulated, nil when it is stateless
+	UnmarshalState func(data []byte) error      // Restores what MarshalState returned, nil when the inspector is stateless
+	Registry       Registry
+	Options        []Option
 }
 
 // Processor identifies the functions that all processor modules must implement.
@@ -56,6 +68,44 @@ type Processor interface {
 	Process(webData WebData) (string, error) // Process alters the body of a request
 }
 
+// BinaryProcessor identifies the functions that processor modules altering binary content, such as images,
+// fonts or WebAssembly modules, must implement. Binary content is only ever passed to binary processors.
+type BinaryProcessor interface {
+	Init()                                                      // Init Initializes module data
+	GetOptions() []Option                                       // GetOptions returns a list of available options for the module
+	GetRegistry() Registry                                      // GetRegistry returns an object with meta data describing the module
+	ProcessBinary(webData WebData, body []byte) ([]byte, error) // ProcessBinary alters the raw bytes of a response body
+}
+
+// ContentTyper can be implemented by processors that change the type of the responses they process, such as
+// serving JSON as HTML. ContentType returns the Content-Type header value used for the rebuilt response, or an
+// empty string to keep the original one. It is called once every processor has run, with the final body
+type ContentTyper interface {
+	ContentType(webData WebData) string
+}
+
+// LineProcessor can be implemented by processors rewriting newline-delimited JSON bodies, such as streamed API
+// responses, one JSON line at a time. ProcessLine is called in place of Process for such bodies, once per line
+// with the line without its line ending and its index among the non-blank lines, and returns the new line
+type LineProcessor interface {
+	ProcessLine(webData WebData, line string, index int) (string, error)
+}
+
+// LineInspector can be implemented by inspectors wanting each JSON line of newline-delimited JSON bodies.
+// InspectLine is called once per line, with the line without its line ending and its index among the non-blank
+// lines, after Inspect has been called with the whole body
+type LineInspector interface {
+	InspectLine(webData WebData, line string, index int) error
+}
+
+// Stateful can be implemented by processors and inspectors accumulating state across requests, such as the
+// endpoints or hashes found so far, so that it carries over to the next session. MarshalState returns the
+// state to save, and UnmarshalState restores a state previously returned by MarshalState
+type Stateful interface {
+	MarshalState() ([]byte, error)
+	UnmarshalState(data []byte) error
+}
+
 // Inspector identifies the functions that all inspector modules must implement.
 type Inspector interface {
 	Init()                         // Init Initializes module data
@@ -64,13 +114,40 @@ type Inspector interface {
 	Inspect(webData WebData) error // Inspect inspects web content for discovery and recon purposes
 }
 
+// Initiator describes what caused a request to be made, as reported by Chrome
+type Initiator struct {
+	Type string // "parser", "script", "preload", "preflight" or "other". Empty when unknown
+	Url  string // Url of the document or script that made the request, when known
+}
+
 // WebData identifies a web request or response object. The type can be either "Document," "Script," or "Request"
 type WebData struct {
-	Body    string
-	Headers map[string]interface{}
-	Type    string
-	Url     string
-	Method  string
+	Body            string
+	Headers         map[string]interface{}
+	RequestHeaders  map[string]interface{} // Headers of the request, for both requests and responses
+	Type            string
+	Url             string
+	Method          string
+	RequestBody     string           `json:",omitempty"` // Body of the request a response answers, empty for requests
+	Status          int              `json:",omitempty"` // Status code of the response, 0 for requests
+	Protocol        string           `json:",omitempty"` // Protocol of the connection, such as "h2" or "http/1.1", empty when not known yet
+	TLSVersion      string           `json:",omitempty"` // TLS version of the connection, such as "TLS 1.3", empty for plain http or when not known yet
+	RequestId       string           // Id shared by the request and response of a single network request
+	FrameId         string           // Id of the frame the request was made by
+	Navigation      bool             // Whether the request loads the document of a frame
+	WorkerScript    bool             // Whether the request fetches a service worker script, not whether a worker made or answered it
+	Initiator       Initiator        // What caused the request to be made
+	Redirects       []string         `json:",omitempty"` // Urls the request was redirected from, oldest first
+	ResponseCookies []*http.Cookie   `json:"-"`          // Cookies set by the response, nil for requests
+	Multipart       *Multipart       `json:"-"`          // Parts of a multipart/form-data request body, nil for any other content
+	Protobuf        []ProtoMessage   `json:"-"`          // Messages of a protobuf or gRPC-Web body, nil for any other content
+	Event           *ServerSentEvent `json:",omitempty"` // Event received on a text/event-stream, for "EventSource" web data
+	Paused          *PausedState     `json:",omitempty"` // State of the page when it hit a breakpoint, for "Paused" web data
+	Violation       *CSPViolation    `json:",omitempty"` // Content Security Policy violation, for "CSPViolation" web data
+	Failure         *LoadingFailure  `json:",omitempty"` // Why the request did not complete, for "LoadingFailed" web data
+	Challenge       *AuthChallenge   `json:",omitempty"` // Authentication challenge of 401 and 407 responses, and of "AuthChallenge" web data
+	Raw             *RawResponse     `json:",omitempty"` // Response as returned by Chrome, when raw responses are recorded
+	Findings        *Findings        `json:"-"`          // Collection inspectors report their findings to
 }
 
 // InitProcessors initializes modules selected for a gorp session
@@ -81,11 +158,8 @@ func (m *Modules) InitProcessors(mods []base.ModuleConfig) error {
 			return err
 		}
 
-		for option, value := range v.Options {
-			err := module.SetOption(option, value)
-			if err != nil {
-				return err
-			}
+		if err := module.ApplyOptions(v.Options); err != nil {
+			return err
 		}
 		printOptions(module.Options)
 		m.Processors = append(m.Processors, *module)
@@ -96,7 +170,6 @@ func (m *Modules) InitProcessors(mods []base.ModuleConfig) error {
 // GetProcessor looks up and loads a processor module as Go plugins.
 // It returns a pointer to the processor module
 func (m *Modules) GetProcessor(path string) (*ProcessorModule, error) {
-	module := ProcessorModule{}
 	fmt.Println("[+] Loading module: " + path)
 	mod := "." + path + "gorpmod.so"
 	plug, err := plugin.Open(mod)
@@ -117,14 +190,56 @@ func (m *Modules) GetProcessor(path string) (*ProcessorModule, error) {
 	//processor = new(modules.Processor)
 	processor, ok := symProcessor.(Processor)
 	if !ok {
+		if binary, ok := symProcessor.(BinaryProcessor); ok {
+			return NewBinaryProcessorModule(binary), nil
+		}
 		fmt.Println("unexpected type from processor symbol")
 		return nil, err
 	}
+	return NewProcessorModule(processor), nil
+}
+
+// NewProcessorModule initializes a processor and wraps it in a processor module. It can be used to
+// register processors that are compiled in rather than loaded as plugins.
+// It returns a pointer to the processor module
+func NewProcessorModule(processor Processor) *ProcessorModule {
 	processor.Init()
-	module.Registry = processor.GetRegistry()
-	module.Options = processor.GetOptions()
-	module.Process = processor.Process
-	return &module, nil
+	m := &ProcessorModule{
+		Registry: processor.GetRegistry(),
+		Options:  processor.GetOptions(),
+		Process:  processor.Process,
+	}
+	if l, ok := processor.(LineProcessor); ok {
+		m.ProcessLine = l.ProcessLine
+	}
+	if c, ok := processor.(ContentTyper); ok {
+		m.ContentType = c.ContentType
+	}
+	if c, ok := processor.(Configurable); ok {
+		m.Configure = c.Configure
+	}
+	if s, ok := processor.(Stateful); ok {
+		m.MarshalState, m.UnmarshalState = s.MarshalState, s.UnmarshalState
+	}
+	return m
+}
+
+// NewBinaryProcessorModule initializes a binary processor and wraps it in a processor module.
+// It returns a pointer to the processor module
+func NewBinaryProcessorModule(processor BinaryProcessor) *ProcessorModule {
+	processor.Init()
+	m := &ProcessorModule{
+		Registry:      processor.GetRegistry(),
+		Options:       processor.GetOptions(),
+		ProcessBinary: processor.ProcessBinary,
+	}
+	if c, ok := processor.(Configurable); ok {
+		m.Configure = c.Configure
+	}
+	if s, ok := processor.(Stateful); ok {
+		m.MarshalState, m.UnmarshalState = s.MarshalState, s.UnmarshalState
+	}
+	return m
 }
 
 // InitInspectors  loads a list of inspector modules.
@@ -135,11 +250,8 @@ func (m *Modules) InitInspectors(mods []base.ModuleConfig) error {
 			return err
 		}
 
-		for option, value := range v.Options {
-			err := module.SetOption(option, value)
-			if err != nil {
-				return err
-			}
+		if err := module.ApplyOptions(v.Options); err != nil {
+			return err
 		}
 		printOptions(module.Options)
 		m.Inspectors = append(m.Inspectors, *module)
@@ -150,7 +262,6 @@ func (m *Modules) InitInspectors(mods []base.ModuleConfig) error {
 // GetInspector looks up and loads an inspector module as Go plugins.
 // It returns a pointer to the inspector module
 func (m *Modules) GetInspector(path string) (*InspectorModule, error) {
-	module := InspectorModule{}
 	fmt.Println("[+] Loading module: " + path)
 	mod := "." + path + "gorpmod.so"
 	plug, err := plugin.Open(mod)
@@ -174,11 +285,29 @@ func (m *Modules) GetInspector(path string) (*InspectorModule, error) {
 		fmt.Println("unexpected type from processor symbol")
 		return nil, err
 	}
+	return NewInspectorModule(inspector), nil
+}
+
+// NewInspectorModule initializes an inspector and wraps it in an inspector module. It can be used to
+// register inspectors that are compiled in rather than loaded as plugins.
+// It returns a pointer to the inspector module
+func NewInspectorModule(inspector Inspector) *InspectorModule {
 	inspector.Init()
-	module.Registry = inspector.GetRegistry()
-	module.Options = inspector.GetOptions()
-	module.Inspect = inspector.Inspect
-	return &module, nil
+	m := &InspectorModule{
+		Registry: inspector.GetRegistry(),
+		Options:  inspector.GetOptions(),
+		Inspect:  inspector.Inspect,
+	}
+	if l, ok := inspector.(LineInspector); ok {
+		m.InspectLine = l.InspectLine
+	}
+	if c, ok := inspector.(Configurable); ok {
+		m.Configure = c.Configure
+	}
+	if s, ok := inspector.(Stateful); ok {
+		m.MarshalState, m.UnmarshalState = s.MarshalState, s.UnmarshalState
+	}
+	return m
 }
 
 // ShowInfo displays the information for the given processor module
@@ -203,6 +332,30 @@ func (i *InspectorModule) SetOption(name string, value string) error {
 	return setModuleOption(i.Options, name, value)
 }
 
+// ApplyOptions sets the options of a processor module, as read from the config file, then lets the processor
+// check and read them when it implements Configurable.
+// It returns an error if an option does not exist or is rejected by the processor
+func (p *ProcessorModule) ApplyOptions(options map[string]string) error {
+	for name, value := range options {
+		if err := p.SetOption(name, value); err != nil {
+			return err
+		}
+	}
+	return configure(p.Registry.Name, p.Options, p.Configure)
+}
+
+// ApplyOptions sets the options of an inspector module, as read from the config file, then lets the inspector
+// check and read them when it implements Configurable.
+// It returns an error if an option does not exist or is rejected by the inspector
+func (i *InspectorModule) ApplyOptions(options map[string]string) error {
+	for name, value := range options {
+		if err := i.SetOption(name, value); err != nil {
+			return err
+		}
+	}
+	return configure(i.Registry.Name, i.Options, i.Configure)
+}
+
 // GetModuleOptionValue is used for obtaining the value of a given module option.
 // It returns the value for the option name requested and an error if the option cannot be found.
 func GetModuleOption(p []Option, name string) (string, error) {
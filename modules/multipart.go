@@ -0,0 +1,147 @@
+package modules
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"strings"
+)
+
+// DefaultFormMemory is the number of bytes of a multipart part kept in memory before it is spooled to disk
+const DefaultFormMemory = 10 << 20
+
+// Multipart holds the parts of a multipart/form-data body
+type Multipart struct {
+	Boundary string
+	Parts    []*FormPart
+}
+
+// FormPart holds a single field or file of a multipart/form-data body. Small parts are kept in Content,
+// large parts are written to a temporary file located at FilePath.
+type FormPart struct {
+	Header    textproto.MIMEHeader
+	FieldName string
+	FileName  string
+	Content   []byte
+	FilePath  string
+}
+
+// ParseMultipart reads a multipart/form-data body using the boundary found in contentType.
+// Parts larger than maxMemory bytes are spooled to temporary files, which must be removed with RemoveAll.
+// It returns a pointer to a Multipart object and an error
+func ParseMultipart(contentType string, body io.Reader, maxMemory int64) (*Multipart, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	if mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return nil, fmt.Errorf("not a multipart/form-data content type: %s", contentType)
+	}
+
+	m := &Multipart{Boundary: params["boundary"]}
+	r := multipart.NewReader(body, m.Boundary)
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			return m, nil
+		}
+		if err != nil {
+			m.RemoveAll()
+			return nil, err
+		}
+
+		part := &FormPart{
+			Header:    p.Header,
+			FieldName: p.FormName(),
+			FileName:  p.FileName(),
+		}
+		var buf bytes.Buffer
+		n, err := io.CopyN(&buf, p, maxMemory+1)
+		if err != nil && err != io.EOF {
+			m.RemoveAll()
+			return nil, err
+		}
+		if n > maxMemory {
+			// too big to keep around, write what we have read so far and the rest of the part to disk
+			err = part.spool(io.MultiReader(&buf, p))
+			if err != nil {
+				m.RemoveAll()
+				return nil, err
+			}
+		} else {
+			part.Content = buf.Bytes()
+		}
+		m.Parts = append(m.Parts, part)
+	}
+}
+
+// Encode serializes the parts back into a multipart/form-data body using the original boundary, so that the
+// Content-Type header of the request remains valid. Processors can use it after rewriting individual parts.
+func (m *Multipart) Encode() (string, error) {
+	var b strings.Builder
+	w := multipart.NewWriter(&b)
+	if err := w.SetBoundary(m.Boundary); err != nil {
+		return "", err
+	}
+	for _, p := range m.Parts {
+		pw, err := w.CreatePart(p.Header)
+		if err != nil {
+			return "", err
+		}
+		r, err := p.Open()
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(pw, r)
+		r.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// RemoveAll removes any temporary files created for large parts
+func (m *Multipart) RemoveAll() {
+	for _, p := range m.Parts {
+		if p.FilePath != "" {
+			os.Remove(p.FilePath)
+		}
+	}
+}
+
+// Open returns a reader for the content of the part, whether it is held in memory or on disk
+func (p *FormPart) Open() (io.ReadCloser, error) {
+	if p.FilePath != "" {
+		return os.Open(p.FilePath)
+	}
+	return ioutil.NopCloser(bytes.NewReader(p.Content)), nil
+}
+
+// SetContent replaces the content of the part with c, discarding any temporary file
+func (p *FormPart) SetContent(c []byte) {
+	if p.FilePath != "" {
+		os.Remove(p.FilePath)
+		p.FilePath = ""
+	}
+	p.Content = c
+}
+
+func (p *FormPart) spool(r io.Reader) error {
+	f, err := ioutil.TempFile("", "gorp-multipart-")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	p.FilePath = f.Name()
+	_, err = io.Copy(f, r)
+	return err
+}
@@ -0,0 +1,64 @@
+package modules
+
+import (
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+const multipartType = "multipart/form-data; boundary=----gorpBoundary"
+
+const multipartBody = "------gorpBoundary\r\n" +
+	"Content-Disposition: form-data; name=\"description\"\r\n" +
+	"\r\n" +
+	"profile picture\r\n" +
+	"------gorpBoundary\r\n" +
+	"Content-Disposition: form-data; name=\"avatar\"; filename=\"avatar.svg\"\r\n" +
+	"Content-Type: image/svg+xml\r\n" +
+	"\r\n" +
+	"<svg onload=\"alert(1)\"></svg>\r\n" +
+	"------gorpBoundary--\r\n"
+
+func TestParseMultipart(t *testing.T) {
+	form, err := ParseMultipart(multipartType, strings.NewReader(multipartBody), DefaultFormMemory)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(form.Parts), 2)
+
+	assert.Equal(t, form.Parts[0].FieldName, "description")
+	assert.Equal(t, form.Parts[0].FileName, "")
+	assert.Equal(t, string(form.Parts[0].Content), "profile picture")
+
+	assert.Equal(t, form.Parts[1].FieldName, "avatar")
+	assert.Equal(t, form.Parts[1].FileName, "avatar.svg")
+	assert.Equal(t, form.Parts[1].Header.Get("Content-Type"), "image/svg+xml")
+	assert.Equal(t, string(form.Parts[1].Content), `<svg onload="alert(1)"></svg>`)
+}
+
+func TestParseMultipartSpoolsLargeParts(t *testing.T) {
+	form, err := ParseMultipart(multipartType, strings.NewReader(multipartBody), 16)
+	assert.Equal(t, err, nil)
+	defer form.RemoveAll()
+
+	avatar := form.Parts[1]
+	assert.Equal(t, len(avatar.Content), 0)
+	r, err := avatar.Open()
+	assert.Equal(t, err, nil)
+	c, _ := ioutil.ReadAll(r)
+	r.Close()
+	assert.Equal(t, string(c), `<svg onload="alert(1)"></svg>`)
+}
+
+func TestMultipartEncode(t *testing.T) {
+	form, _ := ParseMultipart(multipartType, strings.NewReader(multipartBody), DefaultFormMemory)
+	form.Parts[1].SetContent([]byte("<svg></svg>"))
+
+	body, err := form.Encode()
+	assert.Equal(t, err, nil)
+
+	rewritten, err := ParseMultipart(multipartType, strings.NewReader(body), DefaultFormMemory)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(rewritten.Parts[0].Content), "profile picture")
+	assert.Equal(t, rewritten.Parts[1].FileName, "avatar.svg")
+	assert.Equal(t, string(rewritten.Parts[1].Content), "<svg></svg>")
+}
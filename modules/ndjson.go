@@ -0,0 +1,87 @@
+package modules
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ndjsonTypes are the content types of newline-delimited JSON bodies, one JSON value per line
+var ndjsonTypes = map[string]bool{
+	"application/x-ndjson":    true,
+	"application/ndjson":      true,
+	"application/jsonl":       true,
+	"application/jsonlines":   true,
+	"application/x-jsonlines": true,
+	"application/stream+json": true,
+}
+
+// IsNDJSON reports whether the value of a Content-Type header is one used for newline-delimited JSON
+func IsNDJSON(contentType string) bool {
+	return ndjsonTypes[ContentKind(contentType)]
+}
+
+// IsNDJSONBody reports whether a body served with contentType is newline-delimited JSON, either because of its
+// content type or, for bodies served as JSON, plain text or without a type, because it looks like it
+func IsNDJSONBody(contentType string, body string) bool {
+	if IsNDJSON(contentType) {
+		return true
+	}
+	switch ContentKind(contentType) {
+	case "", "text/plain", "application/json":
+		return LooksLikeNDJSON(body)
+	}
+	return false
+}
+
+// LooksLikeNDJSON reports whether body holds at least two lines, every one of them being a JSON object or array
+// once blank lines are left out. A body that is a single JSON value spanning several lines is not NDJSON
+func LooksLikeNDJSON(body string) bool {
+	count := 0
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if (line[0] != '{' && line[0] != '[') || !json.Valid([]byte(line)) {
+			return false
+		}
+		count++
+	}
+	return count >= 2
+}
+
+// NDJSONLines returns the lines of a newline-delimited JSON body, without their line endings and leaving blank
+// lines out
+func NDJSONLines(body string) []string {
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		if line = strings.TrimRight(line, "\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// MapNDJSON rewrites every line of a newline-delimited JSON body with fn, called with the line without its line
+// ending and its index among the lines NDJSONLines returns. Line endings and blank lines are kept.
+// It returns the rewritten body and the first error returned by fn
+func MapNDJSON(body string, fn func(line string, index int) (string, error)) (string, error) {
+	lines := strings.Split(body, "\n")
+	index := 0
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		cr := strings.HasSuffix(line, "\r")
+		rewritten, err := fn(strings.TrimSuffix(line, "\r"), index)
+		if err != nil {
+			return "", err
+		}
+		if cr {
+			rewritten += "\r"
+		}
+		lines[i] = rewritten
+		index++
+	}
+	return strings.Join(lines, "\n"), nil
+}
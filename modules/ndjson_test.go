@@ -0,0 +1,40 @@
+package modules
+
+import (
+	"github.com/magiconair/properties/assert"
+	"strings"
+	"testing"
+)
+
+const ndjsonBody = "{\"id\": 1, \"status\": \"queued\"}\r\n\n{\"id\": 1, \"status\": \"done\"}\r\n[1, 2]\n"
+
+func TestIsNDJSONBody(t *testing.T) {
+	assert.Equal(t, IsNDJSON("application/x-ndjson; charset=utf-8"), true)
+	assert.Equal(t, IsNDJSONBody("application/x-ndjson", "{}"), true)
+	assert.Equal(t, IsNDJSONBody("application/json", ndjsonBody), true)
+	assert.Equal(t, IsNDJSONBody("", ndjsonBody), true)
+	assert.Equal(t, IsNDJSONBody("text/html", ndjsonBody), false)
+
+	// a single value, even spread over several lines, is plain JSON
+	assert.Equal(t, LooksLikeNDJSON("{\"id\": 1}\n"), false)
+	assert.Equal(t, LooksLikeNDJSON("{\n  \"id\": 1\n}\n"), false)
+	assert.Equal(t, LooksLikeNDJSON("{\"id\": 1}\nnot json\n"), false)
+	assert.Equal(t, LooksLikeNDJSON("1\n2\n"), false)
+}
+
+func TestMapNDJSON(t *testing.T) {
+	assert.Equal(t, NDJSONLines(ndjsonBody), []string{
+		`{"id": 1, "status": "queued"}`,
+		`{"id": 1, "status": "done"}`,
+		`[1, 2]`,
+	})
+
+	var indexes []int
+	body, err := MapNDJSON(ndjsonBody, func(line string, index int) (string, error) {
+		indexes = append(indexes, index)
+		return strings.Replace(line, "queued", "done", 1), nil
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, indexes, []int{0, 1, 2})
+	assert.Equal(t, body, "{\"id\": 1, \"status\": \"done\"}\r\n\n{\"id\": 1, \"status\": \"done\"}\r\n[1, 2]\n")
+}
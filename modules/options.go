@@ -0,0 +1,97 @@
+package modules
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Configurable can be implemented by processors and inspectors that check and read their options once, when
+// they are registered, rather than every time they are called. Configure is called once the options of the
+// config file are set, or by Debugger.AddProcessor and Debugger.AddInspector for modules that are not loaded as
+// plugins. The session does not start, or the module is not registered, when it returns an error
+type Configurable interface {
+	Configure(options []Option) error
+}
+
+// configure passes the options of a module to its Configure function, if it has one
+func configure(name string, options []Option, fn func(options []Option) error) error {
+	if fn == nil {
+		return nil
+	}
+	if err := fn(options); err != nil {
+		return fmt.Errorf("invalid options for module %s: %s", name, err)
+	}
+	return nil
+}
+
+// GetRequiredModuleOption is used for obtaining the value of a module option that cannot be left empty.
+// It returns the value and an error if the option cannot be found or is empty
+func GetRequiredModuleOption(p []Option, name string) (string, error) {
+	v, err := GetModuleOption(p, name)
+	if err != nil {
+		return "", err
+	}
+	if v == "" {
+		return "", fmt.Errorf("option %s is required", name)
+	}
+	return v, nil
+}
+
+// GetModuleBool is used for obtaining the value of a module option holding a boolean, such as "true".
+// It returns the value and an error if the option cannot be found or is not a boolean
+func GetModuleBool(p []Option, name string) (bool, error) {
+	v, err := GetModuleOption(p, name)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("option %s must be true or false, got %q", name, v)
+	}
+	return b, nil
+}
+
+// GetModuleInt is used for obtaining the value of a module option holding an integer.
+// It returns the value and an error if the option cannot be found or is not an integer
+func GetModuleInt(p []Option, name string) (int, error) {
+	v, err := GetModuleOption(p, name)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("option %s must be an integer, got %q", name, v)
+	}
+	return i, nil
+}
+
+// GetModuleDuration is used for obtaining the value of a module option holding a duration, such as "10s".
+// It returns the value and an error if the option cannot be found or is not a duration
+func GetModuleDuration(p []Option, name string) (time.Duration, error) {
+	v, err := GetModuleOption(p, name)
+	if err != nil {
+		return 0, err
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("option %s must be a duration such as 10s, got %q", name, v)
+	}
+	return d, nil
+}
+
+// GetModuleRegexp is used for obtaining the value of a module option holding a regular expression.
+// It returns the compiled expression, nil when the option is empty, and an error if the option cannot be found
+// or does not compile
+func GetModuleRegexp(p []Option, name string) (*regexp.Regexp, error) {
+	v, err := GetModuleOption(p, name)
+	if err != nil || v == "" {
+		return nil, err
+	}
+	r, err := regexp.Compile(v)
+	if err != nil {
+		return nil, fmt.Errorf("option %s is not a valid regular expression: %s", name, err)
+	}
+	return r, nil
+}
@@ -0,0 +1,96 @@
+package modules
+
+import (
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"testing"
+	"time"
+)
+
+// keywordInspector reads its options once, when it is registered, and refuses to run without keywords
+type keywordInspector struct {
+	Options  []Option
+	keywords string
+	max      int
+	timeout  time.Duration
+}
+
+func (k *keywordInspector) Init() {
+	k.Options = []Option{
+		{Name: "Keywords", Value: "", Required: true, Description: "Words to look for"},
+		{Name: "MaxFindings", Value: "10", Required: true, Description: "Findings reported per url"},
+		{Name: "Timeout", Value: "1s", Required: true, Description: "How long to search a body for"},
+	}
+}
+
+func (k *keywordInspector) Configure(options []Option) error {
+	var err error
+	if k.keywords, err = GetRequiredModuleOption(options, "Keywords"); err != nil {
+		return err
+	}
+	if k.max, err = GetModuleInt(options, "MaxFindings"); err != nil {
+		return err
+	}
+	if k.max < 1 {
+		return errors.New("MaxFindings must be at least 1")
+	}
+	k.timeout, err = GetModuleDuration(options, "Timeout")
+	return err
+}
+
+func (k *keywordInspector) GetOptions() []Option    { return k.Options }
+func (k *keywordInspector) GetRegistry() Registry   { return Registry{Name: "Keywords"} }
+func (k *keywordInspector) Inspect(w WebData) error { return nil }
+
+func TestApplyOptionsConfiguresModule(t *testing.T) {
+	k := &keywordInspector{}
+	m := NewInspectorModule(k)
+	err := m.ApplyOptions(map[string]string{"Keywords": "admin,debug", "MaxFindings": "3"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, k.keywords, "admin,debug")
+	assert.Equal(t, k.max, 3)
+	assert.Equal(t, k.timeout, time.Second)
+}
+
+func TestApplyOptionsRejectsInvalidOptions(t *testing.T) {
+	m := NewInspectorModule(&keywordInspector{})
+	err := m.ApplyOptions(map[string]string{"MaxFindings": "3"})
+	assert.Equal(t, err.Error(), "invalid options for module Keywords: option Keywords is required")
+
+	m = NewInspectorModule(&keywordInspector{})
+	err = m.ApplyOptions(map[string]string{"Keywords": "admin", "MaxFindings": "many"})
+	assert.Equal(t, err.Error(), `invalid options for module Keywords: option MaxFindings must be an integer, got "many"`)
+
+	m = NewInspectorModule(&keywordInspector{})
+	err = m.ApplyOptions(map[string]string{"Keywords": "admin", "MaxFindings": "0"})
+	assert.Equal(t, err.Error(), "invalid options for module Keywords: MaxFindings must be at least 1")
+
+	// unknown options are reported before the module gets to check them
+	m = NewInspectorModule(&keywordInspector{})
+	assert.Equal(t, m.ApplyOptions(map[string]string{"Keyword": "admin"}).Error(), "invalid module option: Keyword")
+}
+
+func TestTypedModuleOptions(t *testing.T) {
+	options := []Option{
+		{Name: "Print", Value: "true"},
+		{Name: "Pattern", Value: `\d+`},
+		{Name: "Empty", Value: ""},
+		{Name: "Broken", Value: "("},
+	}
+	b, err := GetModuleBool(options, "Print")
+	assert.Equal(t, b, true)
+	assert.Equal(t, err, nil)
+	_, err = GetModuleBool(options, "Pattern")
+	assert.Equal(t, err != nil, true)
+
+	r, err := GetModuleRegexp(options, "Pattern")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, r.MatchString("a1"), true)
+	r, err = GetModuleRegexp(options, "Empty")
+	assert.Equal(t, r == nil, true)
+	assert.Equal(t, err, nil)
+	_, err = GetModuleRegexp(options, "Broken")
+	assert.Equal(t, err != nil, true)
+	_, err = GetModuleRegexp(options, "Missing")
+	assert.Equal(t, err.Error(), "option with key Missing not found")
+}
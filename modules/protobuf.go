@@ -0,0 +1,223 @@
+package modules
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Protobuf wire types
+const (
+	WireVarint  = 0
+	WireFixed64 = 1
+	WireBytes   = 2
+	WireFixed32 = 5
+)
+
+// maxFieldNumber is the largest field number allowed by the protobuf language
+const maxFieldNumber = 1<<29 - 1
+
+// maxProtoDepth bounds how deep length-delimited fields are tried as nested messages
+const maxProtoDepth = 16
+
+// protobufTypes are the content types of bodies holding protobuf messages
+var protobufTypes = map[string]bool{
+	"application/x-protobuf":          true,
+	"application/protobuf":            true,
+	"application/x-protobuffer":       true,
+	"application/vnd.google.protobuf": true,
+	"application/grpc-web":            true,
+	"application/grpc-web+proto":      true,
+	"application/grpc-web-text":       true,
+	"application/grpc-web-text+proto": true,
+}
+
+// ProtoField is a field of a protobuf message, decoded from the wire format without knowing its definition
+type ProtoField struct {
+	Number   int
+	WireType int          // One of WireVarint, WireFixed64, WireBytes or WireFixed32
+	Value    uint64       // Value of varint and fixed size fields
+	Bytes    []byte       // Content of length-delimited fields
+	Message  ProtoMessage // Content of length-delimited fields that parse as a message, nil otherwise
+	Name     string       // Name of the field, when the message was named with a ProtoSchema
+	Type     string       // Type of the field, such as "string" or "sint64", when the message was named with a ProtoSchema
+}
+
+// ProtoMessage is a protobuf message as decoded from the wire, its fields in the order they were found
+type ProtoMessage []ProtoField
+
+// IsProtobuf reports whether the value of a Content-Type header is one used for protobuf or gRPC-Web bodies
+func IsProtobuf(contentType string) bool {
+	return protobufTypes[ContentKind(contentType)]
+}
+
+// DecodeProtobufBody decodes the protobuf messages of a body served with contentType. gRPC-Web bodies hold
+// several length prefixed messages, base64 encoded for the -text variants, and trailers that are skipped.
+// Other bodies hold a single message.
+// It returns the messages and an error if the body is not valid protobuf
+func DecodeProtobufBody(contentType string, body []byte) ([]ProtoMessage, error) {
+	kind := ContentKind(contentType)
+	if !strings.HasPrefix(kind, "application/grpc-web") {
+		m, err := DecodeProtobuf(body)
+		if err != nil {
+			return nil, err
+		}
+		return []ProtoMessage{m}, nil
+	}
+	if strings.HasPrefix(kind, "application/grpc-web-text") {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(body)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid grpc-web-text body: %s", err)
+		}
+		body = decoded
+	}
+	var result []ProtoMessage
+	for len(body) > 0 {
+		if len(body) < 5 {
+			return nil, errors.New("truncated grpc-web frame header")
+		}
+		flags, length := body[0], binary.BigEndian.Uint32(body[1:5])
+		if uint64(length) > uint64(len(body)-5) {
+			return nil, errors.New("truncated grpc-web frame")
+		}
+		frame := body[5 : 5+length]
+		body = body[5+length:]
+		if flags&0x80 != 0 {
+			// trailers, sent as HTTP headers
+			continue
+		}
+		if flags&0x01 != 0 {
+			return nil, errors.New("compressed grpc-web frames are not supported")
+		}
+		m, err := DecodeProtobuf(frame)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// DecodeProtobuf decodes a protobuf message from the wire format. Without the message definition, the type of
+// the fields is unknown: length-delimited fields are kept as bytes, and decoded as nested messages as well when
+// they parse as one, which strings sometimes do.
+// It returns the message and an error if data is not a valid message
+func DecodeProtobuf(data []byte) (ProtoMessage, error) {
+	return decodeProtobuf(data, 0)
+}
+
+func decodeProtobuf(data []byte, depth int) (ProtoMessage, error) {
+	m := ProtoMessage{}
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.New("invalid field key")
+		}
+		data = data[n:]
+		if key>>3 < 1 || key>>3 > maxFieldNumber {
+			return nil, fmt.Errorf("invalid field number %d", key>>3)
+		}
+		f := ProtoField{Number: int(key >> 3), WireType: int(key & 7)}
+		switch f.WireType {
+		case WireVarint:
+			f.Value, n = binary.Uvarint(data)
+			if n <= 0 {
+				return nil, fmt.Errorf("invalid varint in field %d", f.Number)
+			}
+			data = data[n:]
+		case WireFixed64:
+			if len(data) < 8 {
+				return nil, fmt.Errorf("truncated field %d", f.Number)
+			}
+			f.Value, data = binary.LittleEndian.Uint64(data), data[8:]
+		case WireFixed32:
+			if len(data) < 4 {
+				return nil, fmt.Errorf("truncated field %d", f.Number)
+			}
+			f.Value, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case WireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return nil, fmt.Errorf("truncated field %d", f.Number)
+			}
+			f.Bytes, data = data[n:n+int(length)], data[n+int(length):]
+			if depth < maxProtoDepth && len(f.Bytes) > 0 {
+				if nested, err := decodeProtobuf(f.Bytes, depth+1); err == nil {
+					f.Message = nested
+				}
+			}
+		default:
+			// groups are deprecated and never sent by current encoders
+			return nil, fmt.Errorf("unsupported wire type %d in field %d", f.WireType, f.Number)
+		}
+		m = append(m, f)
+	}
+	return m, nil
+}
+
+// String formats the message like protoc --decode_raw does, one field per line, nested messages indented.
+// Length-delimited fields that are printable text are shown as strings rather than as nested messages
+func (m ProtoMessage) String() string {
+	var b strings.Builder
+	m.format(&b, "")
+	return b.String()
+}
+
+func (m ProtoMessage) format(b *strings.Builder, indent string) {
+	for _, f := range m {
+		name := strconv.Itoa(f.Number)
+		if f.Name != "" {
+			name = f.Name
+		}
+		switch {
+		case f.WireType != WireBytes:
+			fmt.Fprintf(b, "%s%s: %s\n", indent, name, f.formatValue())
+		case f.Type == "string" || f.Type == "bytes":
+			fmt.Fprintf(b, "%s%s: %s\n", indent, name, strconv.Quote(string(f.Bytes)))
+		case f.Message != nil && (f.Type != "" || !isPrintable(f.Bytes)):
+			fmt.Fprintf(b, "%s%s {\n", indent, name)
+			f.Message.format(b, indent+"  ")
+			fmt.Fprintf(b, "%s}\n", indent)
+		default:
+			fmt.Fprintf(b, "%s%s: %s\n", indent, name, strconv.Quote(string(f.Bytes)))
+		}
+	}
+}
+
+// formatValue formats the value of a varint or fixed size field according to its type, as an unsigned integer
+// when the type is unknown
+func (f ProtoField) formatValue() string {
+	switch f.Type {
+	case "int32", "int64", "sfixed64", "enum":
+		return strconv.FormatInt(int64(f.Value), 10)
+	case "sfixed32":
+		return strconv.FormatInt(int64(int32(f.Value)), 10)
+	case "sint32", "sint64":
+		return strconv.FormatInt(int64(f.Value>>1)^-int64(f.Value&1), 10)
+	case "bool":
+		return strconv.FormatBool(f.Value != 0)
+	case "double":
+		return strconv.FormatFloat(math.Float64frombits(f.Value), 'g', -1, 64)
+	case "float":
+		return strconv.FormatFloat(float64(math.Float32frombits(uint32(f.Value))), 'g', -1, 32)
+	}
+	return strconv.FormatUint(f.Value, 10)
+}
+
+// isPrintable reports whether b is UTF-8 text without control characters other than whitespace
+func isPrintable(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if r < 0x20 && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
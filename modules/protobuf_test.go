@@ -0,0 +1,113 @@
+package modules
+
+import (
+	"encoding/base64"
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+// searchRequest is SearchRequest{query: "testing", page: 150, filter: {id: -2}} on the wire
+var searchRequest = []byte{0x0a, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g', 0x10, 0x96, 0x01, 0x1a, 0x02, 0x08, 0x03}
+
+const searchProto = `
+syntax = "proto3";
+package search;
+
+import "google/protobuf/any.proto";
+
+// SearchRequest is sent by the search box
+message SearchRequest {
+  string query = 1;
+  int32 page = 2 [deprecated = true];
+  Filter filter = 3;
+  reserved 4, 5;
+
+  message Filter {
+    sint32 id = 1;
+    oneof kind {
+      Corpus corpus = 2;
+    }
+  }
+  /* corpus of the results */
+  enum Corpus {
+    UNIVERSAL = 0;
+    WEB = 1;
+  }
+  map<string, int32> counts = 6;
+}
+
+service SearchService {
+  rpc Search(SearchRequest) returns (SearchRequest);
+}
+`
+
+func TestDecodeProtobuf(t *testing.T) {
+	m, err := DecodeProtobuf(searchRequest)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(m), 3)
+	assert.Equal(t, m[0].Number, 1)
+	assert.Equal(t, string(m[0].Bytes), "testing")
+	assert.Equal(t, m[1].WireType, WireVarint)
+	assert.Equal(t, m[1].Value, uint64(150))
+	assert.Equal(t, m[2].Message, ProtoMessage{{Number: 1, WireType: WireVarint, Value: 3}})
+	assert.Equal(t, m.String(), "1: \"testing\"\n2: 150\n3 {\n  1: 3\n}\n")
+
+	_, err = DecodeProtobuf([]byte{0x0a, 0x07, 't', 'e'})
+	assert.Equal(t, err != nil, true)
+	_, err = DecodeProtobuf([]byte{0x00, 0x01})
+	assert.Equal(t, err != nil, true)
+}
+
+func TestDecodeProtobufBody(t *testing.T) {
+	frame := append([]byte{0x00, 0x00, 0x00, 0x00, byte(len(searchRequest))}, searchRequest...)
+	trailers := append([]byte{0x80, 0x00, 0x00, 0x00, 0x0f}, "grpc-status: 0\n"...)
+	body := append(append(append([]byte{}, frame...), frame...), trailers...)
+
+	messages, err := DecodeProtobufBody("application/grpc-web+proto", body)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(messages), 2)
+	assert.Equal(t, messages[1][1].Value, uint64(150))
+
+	text := base64.StdEncoding.EncodeToString(body)
+	messages, err = DecodeProtobufBody("application/grpc-web-text", []byte(text))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(messages), 2)
+
+	messages, err = DecodeProtobufBody("application/x-protobuf", searchRequest)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(messages), 1)
+
+	_, err = DecodeProtobufBody("application/grpc-web", frame[:8])
+	assert.Equal(t, err != nil, true)
+	compressed := append([]byte{0x01}, frame[1:]...)
+	_, err = DecodeProtobufBody("application/grpc-web", compressed)
+	assert.Equal(t, err != nil, true)
+
+	assert.Equal(t, IsProtobuf("application/grpc-web-text; charset=utf-8"), true)
+	assert.Equal(t, IsProtobuf("application/json"), false)
+}
+
+func TestProtoSchema(t *testing.T) {
+	s, err := ParseProtoSchema(searchProto)
+	assert.Equal(t, err, nil)
+
+	m, _ := DecodeProtobuf(searchRequest)
+	named, err := s.Name(m, "search.SearchRequest")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, named.String(), "query: \"testing\"\npage: 150\nfilter {\n  id: -2\n}\n")
+	// the decoded message is left alone
+	assert.Equal(t, m[0].Name, "")
+
+	named, err = s.Name(ProtoMessage{{Number: 2, WireType: WireVarint, Value: 1}}, "SearchRequest.Filter")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, named[0].Name, "corpus")
+	assert.Equal(t, named[0].Type, "enum")
+
+	_, err = s.Name(m, "Unknown")
+	assert.Equal(t, err != nil, true)
+
+	_, err = ParseProtoSchema("message Broken { string query = ; }")
+	assert.Equal(t, err != nil, true)
+	_, err = ParseProtoSchema("message Open { string query = 1;")
+	assert.Equal(t, err != nil, true)
+}
@@ -0,0 +1,195 @@
+package modules
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// protoTokenRegex splits the source of a .proto file into identifiers, numbers, strings and symbols
+var protoTokenRegex = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|[A-Za-z_.][\w.]*|-?\d+|\S`)
+
+// protoCommentRegex matches the comments of a .proto file
+var protoCommentRegex = regexp.MustCompile(`(?s)//[^\n]*|/\*.*?\*/`)
+
+// protoScalars are the scalar types of the protobuf language
+var protoScalars = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true, "uint64": true,
+	"sint32": true, "sint64": true, "fixed32": true, "fixed64": true, "sfixed32": true, "sfixed64": true,
+	"bool": true, "string": true, "bytes": true,
+}
+
+// ProtoSchema holds the message definitions of a .proto file, used to name the fields of messages decoded
+// from the wire. Only messages, their fields and enums are read, services and options are skipped
+type ProtoSchema struct {
+	messages map[string]map[int]protoFieldDef // Fields of each message by number, under its simple and full names
+	enums    map[string]bool
+}
+
+// protoFieldDef is the definition of a message field
+type protoFieldDef struct {
+	name string
+	typ  string
+}
+
+// ParseProtoSchema reads the message definitions of the source of a .proto file.
+// It returns a pointer to the schema and an error if the definitions cannot be read
+func ParseProtoSchema(src string) (*ProtoSchema, error) {
+	s := &ProtoSchema{messages: make(map[string]map[int]protoFieldDef), enums: make(map[string]bool)}
+	tokens := protoTokenRegex.FindAllString(protoCommentRegex.ReplaceAllString(src, " "), -1)
+
+	// scopes holds the full name of the enclosing messages, and "" for oneof blocks which add no scope
+	var scopes []string
+	message := func() string {
+		for i := len(scopes) - 1; i >= 0; i-- {
+			if scopes[i] != "" {
+				return scopes[i]
+			}
+		}
+		return ""
+	}
+	for i := 0; i < len(tokens); i++ {
+		switch t := tokens[i]; {
+		case t == "message" && i+2 < len(tokens) && tokens[i+2] == "{":
+			name := tokens[i+1]
+			if parent := message(); parent != "" {
+				name = parent + "." + name
+			}
+			s.messages[name] = make(map[int]protoFieldDef)
+			s.messages[lastProtoName(name)] = s.messages[name]
+			scopes = append(scopes, name)
+			i += 2
+		case t == "oneof" && i+2 < len(tokens) && tokens[i+2] == "{":
+			scopes = append(scopes, "")
+			i += 2
+		case (t == "enum" || t == "service" || t == "extend") && i+2 < len(tokens) && tokens[i+2] == "{":
+			if t == "enum" {
+				s.enums[tokens[i+1]] = true
+			}
+			end := skipProtoBlock(tokens, i+2)
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated %s %s", t, tokens[i+1])
+			}
+			i = end
+		case t == "}":
+			if len(scopes) == 0 {
+				return nil, fmt.Errorf("unexpected }")
+			}
+			scopes = scopes[:len(scopes)-1]
+		case t == ";":
+		case message() == "" || t == "option" || t == "reserved" || t == "extensions":
+			// syntax, package, import and option statements, and what cannot be a field
+			for i < len(tokens) && tokens[i] != ";" {
+				i++
+			}
+		default:
+			end, err := s.addField(message(), tokens, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		}
+	}
+	if len(scopes) > 0 {
+		return nil, fmt.Errorf("unterminated message %s", message())
+	}
+	return s, nil
+}
+
+// addField reads the field definition starting at tokens[start] into message.
+// It returns the index of the semicolon ending the definition
+func (s *ProtoSchema) addField(message string, tokens []string, start int) (int, error) {
+	i := start
+	if t := tokens[i]; t == "repeated" || t == "optional" || t == "required" {
+		i++
+	}
+	if i >= len(tokens) {
+		return 0, fmt.Errorf("unterminated field in message %s", message)
+	}
+	typ := tokens[i]
+	if typ == "map" {
+		// map<key, value>, sent as repeated entries with the key in field 1 and the value in field 2
+		for i < len(tokens) && tokens[i] != ">" {
+			i++
+		}
+	}
+	if i+4 >= len(tokens) || tokens[i+2] != "=" {
+		return 0, fmt.Errorf("invalid field definition in message %s near %q", message, tokens[start])
+	}
+	name := tokens[i+1]
+	number, err := strconv.Atoi(tokens[i+3])
+	if err != nil || number < 1 || number > maxFieldNumber {
+		return 0, fmt.Errorf("invalid number for field %s of message %s", name, message)
+	}
+	s.messages[message][number] = protoFieldDef{name: name, typ: typ}
+	for i += 4; i < len(tokens) && tokens[i] != ";"; i++ {
+		// field options, such as [packed = true]
+	}
+	return i, nil
+}
+
+// skipProtoBlock returns the index of the brace closing the one at tokens[open], -1 if there is none
+func skipProtoBlock(tokens []string, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// lastProtoName returns the last component of a dotted name
+func lastProtoName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// Name names the fields of a message decoded from the wire as defined by the message of the schema with the
+// given name, nested messages included. Fields missing from the definition keep their number.
+// It returns the named message and an error if the schema has no such message
+func (s *ProtoSchema) Name(m ProtoMessage, message string) (ProtoMessage, error) {
+	fields := s.lookup(message)
+	if fields == nil {
+		return nil, fmt.Errorf("no message %s in the schema", message)
+	}
+	return s.name(m, fields), nil
+}
+
+func (s *ProtoSchema) name(m ProtoMessage, fields map[int]protoFieldDef) ProtoMessage {
+	result := make(ProtoMessage, len(m))
+	for i, f := range m {
+		def, ok := fields[f.Number]
+		if ok {
+			f.Name = def.name
+			switch {
+			case protoScalars[def.typ]:
+				f.Type = def.typ
+			case s.enums[lastProtoName(def.typ)]:
+				f.Type = "enum"
+			case s.lookup(def.typ) != nil:
+				f.Type = "message"
+				if f.Message != nil {
+					f.Message = s.name(f.Message, s.lookup(def.typ))
+				}
+			}
+		}
+		result[i] = f
+	}
+	return result
+}
+
+// lookup returns the fields of the message with the given name, which may be qualified, nil if it is unknown
+func (s *ProtoSchema) lookup(name string) map[int]protoFieldDef {
+	name = strings.TrimPrefix(name, ".")
+	if fields, ok := s.messages[name]; ok {
+		return fields
+	}
+	return s.messages[lastProtoName(name)]
+}
@@ -0,0 +1,24 @@
+package modules
+
+import (
+	"encoding/base64"
+)
+
+// RawResponse holds a response exactly as Chrome handed it over, before gorp decoded or rebuilt anything.
+// Chrome does not give access to the bytes sent over the wire: bodies come decompressed and without chunked
+// framing, headers come as a map with repeated headers joined by newlines and their order lost, and
+// trailers and the raw frames of HTTP/2 and HTTP/3 responses are not exposed at all.
+type RawResponse struct {
+	Status  int                    // Status code, 0 when Chrome did not report it
+	Headers map[string]interface{} // Response headers as reported by Chrome
+	Body    string                 // Body as returned by Chrome, base64 encoded when Base64 is set
+	Base64  bool
+}
+
+// Bytes returns the body of the raw response, decoding it when Chrome returned it base64 encoded
+func (r *RawResponse) Bytes() ([]byte, error) {
+	if r.Base64 {
+		return base64.StdEncoding.DecodeString(r.Body)
+	}
+	return []byte(r.Body), nil
+}
@@ -0,0 +1,257 @@
+package modules
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// volatileHeaders change from one response to the next regardless of the application, and are left out of
+// session diffs
+var volatileHeaders = map[string]bool{
+	"age":            true,
+	"cf-ray":         true,
+	"content-length": true,
+	"date":           true,
+	"expires":        true,
+	"x-request-id":   true,
+}
+
+// SessionDiff describes what changed between two recorded sessions. Requests are identified by their method
+// and url, such as "GET https://example.com/app.js"
+type SessionDiff struct {
+	Added   []string       // Requests only made in the second session
+	Removed []string       // Requests only made in the first session
+	Changed []BodyChange   // Responses whose body changed
+	Headers []HeaderChange // Headers added, removed or changed
+}
+
+// BodyChange is a response whose body changed between two sessions
+type BodyChange struct {
+	Request string
+	Before  string // Hex encoded SHA-256 hash of the body in the first session
+	After   string // Hex encoded SHA-256 hash of the body in the second session
+}
+
+// HeaderChange is a header that differs between two sessions. Before is empty for added headers and After for
+// removed ones
+type HeaderChange struct {
+	Request       string
+	Name          string // Lowercase name of the header
+	Before        string
+	After         string
+	RequestHeader bool // Whether the header was sent with the request rather than the response
+}
+
+// Empty reports whether the sessions made the same requests and got the same responses
+func (s SessionDiff) Empty() bool {
+	return len(s.Added) == 0 && len(s.Removed) == 0 && len(s.Changed) == 0 && len(s.Headers) == 0
+}
+
+// sessionEntry is what a session recorded about a single request
+type sessionEntry struct {
+	hash            string // Empty when no response was recorded
+	requestHeaders  map[string]string
+	responseHeaders map[string]string
+}
+
+// DiffSessions compares two recorded sessions, each either a fixture file written with Options.RecordFixtures
+// or a HAR file exported from Chrome. Requests made more than once are compared on the last response
+// recorded. Headers such as Date, which change on every response, are ignored.
+// It returns the differences between the sessions and an error if either file cannot be read
+func DiffSessions(a string, b string) (SessionDiff, error) {
+	before, err := loadSession(a)
+	if err != nil {
+		return SessionDiff{}, err
+	}
+	after, err := loadSession(b)
+	if err != nil {
+		return SessionDiff{}, err
+	}
+
+	var diff SessionDiff
+	for _, key := range sortedEntryKeys(before) {
+		if _, ok := after[key]; !ok {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+	for _, key := range sortedEntryKeys(after) {
+		b, ok := before[key]
+		if !ok {
+			diff.Added = append(diff.Added, key)
+			continue
+		}
+		a := after[key]
+		if b.hash != "" && a.hash != "" && b.hash != a.hash {
+			diff.Changed = append(diff.Changed, BodyChange{Request: key, Before: b.hash, After: a.hash})
+		}
+		diff.Headers = append(diff.Headers, diffHeaders(key, b.requestHeaders, a.requestHeaders, true)...)
+		diff.Headers = append(diff.Headers, diffHeaders(key, b.responseHeaders, a.responseHeaders, false)...)
+	}
+	return diff, nil
+}
+
+func diffHeaders(key string, before map[string]string, after map[string]string, request bool) []HeaderChange {
+	names := make(map[string]bool)
+	for n := range before {
+		names[n] = true
+	}
+	for n := range after {
+		names[n] = true
+	}
+	var sorted []string
+	for n := range names {
+		sorted = append(sorted, n)
+	}
+	sort.Strings(sorted)
+
+	var changes []HeaderChange
+	for _, n := range sorted {
+		if before[n] != after[n] {
+			changes = append(changes, HeaderChange{Request: key, Name: n, Before: before[n], After: after[n], RequestHeader: request})
+		}
+	}
+	return changes
+}
+
+func sortedEntryKeys(entries map[string]*sessionEntry) []string {
+	var keys []string
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// loadSession reads a fixture or HAR file into entries keyed by method and url
+func loadSession(path string) (map[string]*sessionEntry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var webData []WebData
+	if isHAR(data) {
+		if webData, err = parseHAR(data); err != nil {
+			return nil, fmt.Errorf("unable to parse HAR file %s: %s", path, err)
+		}
+	} else if webData, err = LoadFixtures(path); err != nil {
+		return nil, fmt.Errorf("unable to parse fixture file %s: %s", path, err)
+	}
+
+	entries := make(map[string]*sessionEntry)
+	for _, w := range webData {
+		key := w.Method + " " + w.Url
+		e := entries[key]
+		if e == nil {
+			e = &sessionEntry{}
+			entries[key] = e
+		}
+		if w.Type == "Request" {
+			e.requestHeaders = diffableHeaders(w.Headers)
+			continue
+		}
+		sum := sha256.Sum256([]byte(w.Body))
+		e.hash = hex.EncodeToString(sum[:])
+		e.responseHeaders = diffableHeaders(w.Headers)
+		if w.RequestHeaders != nil {
+			e.requestHeaders = diffableHeaders(w.RequestHeaders)
+		}
+	}
+	return entries, nil
+}
+
+func diffableHeaders(headers map[string]interface{}) map[string]string {
+	result := make(map[string]string)
+	for k, v := range headers {
+		name := strings.ToLower(k)
+		if volatileHeaders[name] {
+			continue
+		}
+		result[name] = fmt.Sprint(v)
+	}
+	return result
+}
+
+// har holds the parts of a HAR file used to diff sessions
+type har struct {
+	Log struct {
+		Entries []struct {
+			ResourceType string `json:"_resourceType"`
+			Request      struct {
+				Method  string      `json:"method"`
+				Url     string      `json:"url"`
+				Headers []harHeader `json:"headers"`
+			} `json:"request"`
+			Response struct {
+				Headers []harHeader `json:"headers"`
+				Content struct {
+					Text     string `json:"text"`
+					Encoding string `json:"encoding"`
+				} `json:"content"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func isHAR(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	if !bytes.HasPrefix(data, []byte("{")) {
+		return false
+	}
+	var probe struct {
+		Log *json.RawMessage `json:"log"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.Log != nil
+}
+
+// parseHAR converts the entries of a HAR file to web data, one per response
+func parseHAR(data []byte) ([]WebData, error) {
+	var h har
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, err
+	}
+	var result []WebData
+	for _, e := range h.Log.Entries {
+		body := e.Response.Content.Text
+		if e.Response.Content.Encoding == "base64" {
+			decoded, err := base64.StdEncoding.DecodeString(body)
+			if err != nil {
+				return nil, fmt.Errorf("invalid body for %s: %s", e.Request.Url, err)
+			}
+			body = string(decoded)
+		}
+		result = append(result, WebData{
+			Body:           body,
+			Headers:        harHeaders(e.Response.Headers),
+			RequestHeaders: harHeaders(e.Request.Headers),
+			Type:           e.ResourceType,
+			Url:            e.Request.Url,
+			Method:         e.Request.Method,
+		})
+	}
+	return result, nil
+}
+
+// harHeaders converts HAR headers to a header map. Repeated headers are joined with a comma
+func harHeaders(headers []harHeader) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, h := range headers {
+		if v, ok := result[h.Name]; ok {
+			result[h.Name] = v.(string) + ", " + h.Value
+		} else {
+			result[h.Name] = h.Value
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package modules
+
+import (
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func recordSession(t *testing.T, path string, webData []WebData) {
+	r, err := NewFixtureRecorder(path)
+	assert.Equal(t, err, nil)
+	for _, w := range webData {
+		assert.Equal(t, r.Record(w), nil)
+	}
+	assert.Equal(t, r.Close(), nil)
+}
+
+func TestDiffSessions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-sessions")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")
+
+	script := func(body string, headers map[string]interface{}) WebData {
+		return WebData{Body: body, Headers: headers, Type: "Script", Url: "https://example.com/app.js", Method: "GET"}
+	}
+	api := func(path string) WebData {
+		return WebData{Body: `{"ok":true}`, Type: "XHR", Url: "https://example.com/api/" + path, Method: "GET",
+			Headers: map[string]interface{}{"Content-Type": "application/json", "Date": "Mon, 01 Jun 2020 10:00:00 GMT"}}
+	}
+	recordSession(t, a, []WebData{
+		script("var v=1;", map[string]interface{}{"Content-Type": "text/javascript", "Date": "Mon, 01 Jun 2020 10:00:00 GMT"}),
+		api("users"),
+		api("legacy"),
+	})
+	recordSession(t, b, []WebData{
+		script("var v=2;", map[string]interface{}{"Content-Type": "text/javascript", "Date": "Tue, 02 Jun 2020 10:00:00 GMT",
+			"X-Frame-Options": "DENY"}),
+		api("users"),
+		api("admin"),
+	})
+
+	diff, err := DiffSessions(a, b)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, diff.Added, []string{"GET https://example.com/api/admin"})
+	assert.Equal(t, diff.Removed, []string{"GET https://example.com/api/legacy"})
+	assert.Equal(t, len(diff.Changed), 1)
+	assert.Equal(t, diff.Changed[0].Request, "GET https://example.com/app.js")
+	assert.Equal(t, diff.Changed[0].Before == diff.Changed[0].After, false)
+	assert.Equal(t, diff.Headers, []HeaderChange{
+		{Request: "GET https://example.com/app.js", Name: "x-frame-options", After: "DENY"},
+	})
+
+	diff, err = DiffSessions(a, a)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, diff.Empty(), true)
+}
+
+func TestDiffSessionsHAR(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-sessions")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.har")
+
+	recordSession(t, a, []WebData{{Body: "hello", Type: "Document", Url: "https://example.com/", Method: "GET",
+		RequestHeaders: map[string]interface{}{"Accept": "text/html"}}})
+	har := `{"log":{"version":"1.2","entries":[{"_resourceType":"document",
+		"request":{"method":"GET","url":"https://example.com/","headers":[{"name":"Accept","value":"*/*"}]},
+		"response":{"status":200,"headers":[],"content":{"text":"aGVsbG8=","encoding":"base64"}}}]}}`
+	assert.Equal(t, ioutil.WriteFile(b, []byte(har), 0644), nil)
+
+	diff, err := DiffSessions(a, b)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(diff.Changed), 0)
+	assert.Equal(t, diff.Headers, []HeaderChange{
+		{Request: "GET https://example.com/", Name: "accept", Before: "text/html", After: "*/*", RequestHeader: true},
+	})
+
+	_, err = DiffSessions(a, filepath.Join(dir, "missing.json"))
+	assert.Equal(t, err != nil, true)
+}
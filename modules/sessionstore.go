@@ -0,0 +1,109 @@
+package modules
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// SessionStore holds the web data recorded during a session, so that exporters can read it back once the
+// session is over. Implementations must be safe for concurrent use.
+type SessionStore interface {
+	Append(webData WebData) error                 // Append records webData after what was recorded so far
+	Iterate(fn func(webData WebData) error) error // Iterate calls fn with the recorded web data in order, stopping at the first error
+	Close() error                                 // Close releases the resources held by the store
+}
+
+// MemoryStore is a SessionStore keeping web data in memory, for short sessions
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries []WebData
+}
+
+// NewMemoryStore returns an empty in-memory session store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Append records webData. Findings are left out, as they are when web data is recorded to a file
+func (s *MemoryStore) Append(webData WebData) error {
+	webData.Findings = nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, webData)
+	return nil
+}
+
+// Iterate calls fn with the web data recorded so far. Web data appended meanwhile is not passed to fn
+func (s *MemoryStore) Iterate(fn func(webData WebData) error) error {
+	s.mu.Lock()
+	entries := s.entries
+	s.mu.Unlock()
+	for _, w := range entries {
+		if err := fn(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close discards the recorded web data
+func (s *MemoryStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+	return nil
+}
+
+// FileStore is a SessionStore writing web data to a fixture file, one JSON object per line, so that long
+// sessions do not have to fit in memory. The file can be loaded with LoadFixtures as well
+type FileStore struct {
+	mu    sync.Mutex
+	file  *os.File
+	enc   *json.Encoder
+	start int64 // Offset the file ended at when it was opened, where the web data of this store starts
+}
+
+// NewFileStore creates the fixture file at path, or appends to it if it already exists. Web data recorded to
+// the file before is kept, but not passed to Iterate.
+// It returns a pointer to a FileStore and an error
+func NewFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &FileStore{file: f, enc: json.NewEncoder(f), start: info.Size()}, nil
+}
+
+// Append adds webData to the file. Multipart bodies are recorded as raw bodies, and findings are left out
+func (s *FileStore) Append(webData WebData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(webData)
+}
+
+// Iterate reads back what was appended to the file since it was opened, calling fn with each web data as
+// LoadFixtures returns it. Only one web data is held in memory at a time, and web data appended meanwhile is not
+// passed to fn
+func (s *FileStore) Iterate(fn func(webData WebData) error) error {
+	s.mu.Lock()
+	info, err := s.file.Stat()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return decodeFixtures(io.NewSectionReader(s.file, s.start, info.Size()-s.start), fn)
+}
+
+// Close closes the file
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
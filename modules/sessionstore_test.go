@@ -0,0 +1,118 @@
+package modules
+
+import (
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var storedSession = []WebData{
+	{
+		Body:      `{"id":1}`,
+		Headers:   map[string]interface{}{"Content-Type": "application/json", "Set-Cookie": "sid=1; HttpOnly"},
+		Type:      "XHR",
+		Url:       "https://example.com/api/users/1",
+		Method:    "GET",
+		RequestId: "1",
+		Findings:  &Findings{},
+	},
+	{
+		Body:      multipartBody,
+		Headers:   map[string]interface{}{"Content-Type": multipartType},
+		Type:      "Request",
+		Url:       "https://example.com/upload",
+		Method:    "POST",
+		RequestId: "2",
+	},
+}
+
+// iterateAll returns the urls of the web data of a store, checking that they come back as recorded
+func iterateAll(t *testing.T, s SessionStore) []string {
+	var urls []string
+	err := s.Iterate(func(webData WebData) error {
+		urls = append(urls, webData.Url)
+		assert.Equal(t, webData.Findings == nil, true)
+		return nil
+	})
+	assert.Equal(t, err, nil)
+	return urls
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-store")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "session.json")
+
+	s, err := NewFileStore(path)
+	assert.Equal(t, err, nil)
+	for _, w := range storedSession {
+		assert.Equal(t, s.Append(w), nil)
+	}
+
+	var entries []WebData
+	assert.Equal(t, s.Iterate(func(webData WebData) error {
+		entries = append(entries, webData)
+		return nil
+	}), nil)
+	assert.Equal(t, len(entries), 2)
+	assert.Equal(t, entries[0].Body, `{"id":1}`)
+	assert.Equal(t, entries[0].ResponseCookies[0].Name, "sid")
+	assert.Equal(t, entries[1].Multipart.Parts[1].FileName, "avatar.svg")
+
+	// the store can still be appended to once read, and iterated again
+	assert.Equal(t, s.Append(WebData{Url: "https://example.com/"}), nil)
+	assert.Equal(t, iterateAll(t, s), []string{
+		"https://example.com/api/users/1", "https://example.com/upload", "https://example.com/",
+	})
+
+	stop := errors.New("stop")
+	calls := 0
+	assert.Equal(t, s.Iterate(func(webData WebData) error {
+		calls++
+		return stop
+	}), stop)
+	assert.Equal(t, calls, 1)
+	assert.Equal(t, s.Close(), nil)
+
+	fixtures, err := LoadFixtures(path)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(fixtures), 3)
+}
+
+func TestFileStoreSkipsEarlierSessions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorp-store")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "session.json")
+
+	s, err := NewFileStore(path)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, s.Append(storedSession[0]), nil)
+	assert.Equal(t, s.Close(), nil)
+
+	s, err = NewFileStore(path)
+	assert.Equal(t, err, nil)
+	defer s.Close()
+	assert.Equal(t, iterateAll(t, s), []string(nil))
+	assert.Equal(t, s.Append(WebData{Url: "https://example.com/"}), nil)
+	assert.Equal(t, iterateAll(t, s), []string{"https://example.com/"})
+
+	// the earlier session is still in the file
+	fixtures, err := LoadFixtures(path)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(fixtures), 2)
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	for _, w := range storedSession {
+		assert.Equal(t, s.Append(w), nil)
+	}
+	assert.Equal(t, iterateAll(t, s), []string{"https://example.com/api/users/1", "https://example.com/upload"})
+	assert.Equal(t, s.Close(), nil)
+	assert.Equal(t, len(iterateAll(t, s)), 0)
+}